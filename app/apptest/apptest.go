@@ -0,0 +1,136 @@
+// Package apptest sets up what tests touching the database need. Those tests run against the
+// Postgres database TEST_DB_DSN points at and are skipped when it isn't set. Every test gets a
+// schema of its own, migrated like the application's, which is dropped when the test ends.
+package apptest
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gowesmart/api-gowesmart/app"
+	"github.com/gowesmart/api-gowesmart/model/entity"
+	"go.uber.org/zap"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func NewDB(t testing.TB) *gorm.DB {
+	t.Helper()
+
+	dsn := os.Getenv("TEST_DB_DSN")
+	if dsn == "" {
+		t.Skip("TEST_DB_DSN is not set")
+	}
+
+	admin, err := gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("connecting to the test database: %v", err)
+	}
+
+	schema := fmt.Sprintf("test_%d", time.Now().UnixNano())
+	if err := admin.Exec("CREATE SCHEMA " + schema).Error; err != nil {
+		t.Fatalf("creating schema %s: %v", schema, err)
+	}
+
+	t.Cleanup(func() {
+		admin.Exec("DROP SCHEMA " + schema + " CASCADE")
+		if sqlDB, err := admin.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+
+	t.Setenv("DB_DSN", withSearchPath(dsn, schema+",public"))
+
+	db := app.NewConnection()
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+
+	return db.Session(&gorm.Session{Logger: logger.Default.LogMode(logger.Silent)})
+}
+
+// NewContext returns a gin context carrying db and a no-op logger, the way the router hands them to the services.
+func NewContext(db *gorm.DB) *gin.Context {
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Set("db", db)
+	c.Set("logger", zap.NewNop())
+	return c
+}
+
+func withSearchPath(dsn, searchPath string) string {
+	if !strings.Contains(dsn, "://") {
+		return dsn + " search_path=" + searchPath
+	}
+	if strings.Contains(dsn, "?") {
+		return dsn + "&search_path=" + searchPath
+	}
+	return dsn + "?search_path=" + searchPath
+}
+
+var fixtureSeq atomic.Int64
+
+// CreateUser creates a user with the given role, creating the role when it doesn't exist yet.
+func CreateUser(t testing.TB, db *gorm.DB, roleID int) entity.User {
+	t.Helper()
+
+	n := fixtureSeq.Add(1)
+	role := entity.Role{ID: uint(roleID), Name: fmt.Sprintf("role-%d", roleID)}
+	mustCreate(t, db.Where(entity.Role{ID: role.ID}).Attrs(role).FirstOrCreate(&role))
+
+	user := entity.User{
+		RoleID:   role.ID,
+		Username: fmt.Sprintf("user%d", n),
+		Email:    fmt.Sprintf("user%d@example.com", n),
+		Password: "-",
+	}
+	mustCreate(t, db.Create(&user))
+
+	return user
+}
+
+// CreateVariant creates a bike, in a category of its own, with a single variant holding stock.
+func CreateVariant(t testing.TB, db *gorm.DB, price, stock int) entity.BikeVariant {
+	t.Helper()
+
+	n := fixtureSeq.Add(1)
+	category := entity.Category{Name: fmt.Sprintf("Category %d", n), Slug: fmt.Sprintf("category-%d", n)}
+	mustCreate(t, db.Create(&category))
+
+	bike := entity.Bike{
+		CategoryID:  category.ID,
+		Name:        fmt.Sprintf("Bike %d", n),
+		Brand:       "Brand",
+		Year:        2024,
+		Price:       price,
+		Stock:       stock,
+		IsAvailable: true,
+	}
+	mustCreate(t, db.Omit("Category").Create(&bike))
+
+	variant := entity.BikeVariant{
+		BikeID:      bike.ID,
+		SKU:         fmt.Sprintf("SKU-%d", n),
+		Stock:       stock,
+		IsAvailable: true,
+	}
+	mustCreate(t, db.Omit("Bike").Create(&variant))
+	variant.Bike = bike
+
+	return variant
+}
+
+func mustCreate(t testing.TB, result *gorm.DB) {
+	t.Helper()
+	if result.Error != nil {
+		t.Fatalf("creating fixture: %v", result.Error)
+	}
+}
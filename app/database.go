@@ -15,11 +15,85 @@ func NewConnection() *gorm.DB {
 	})
 	utils.PanicIfError(err)
 
-	err = db.AutoMigrate(&entity.User{}, &entity.Profile{}, &entity.Role{}, &entity.Bike{}, &entity.Review{}, &entity.Transaction{}, &entity.Order{}, &entity.Category{}, &entity.Cart{}, &entity.CartItem{})
+	// categories used to delete their bikes with them, AutoMigrate recreates the foreign key without the cascade
+	db.Exec(`DO $$ BEGIN
+		IF EXISTS (SELECT 1 FROM information_schema.referential_constraints WHERE constraint_name = 'fk_categories_bike' AND delete_rule = 'CASCADE') THEN
+			ALTER TABLE bikes DROP CONSTRAINT fk_categories_bike;
+		END IF;
+	END $$`)
+
+	// refunds and invoices used to be deleted with their transaction, AutoMigrate recreates the foreign keys restricting it
+	db.Exec(`DO $$ BEGIN
+		IF EXISTS (SELECT 1 FROM information_schema.referential_constraints WHERE constraint_name = 'fk_transactions_refunds' AND delete_rule = 'CASCADE') THEN
+			ALTER TABLE refunds DROP CONSTRAINT fk_transactions_refunds;
+		END IF;
+		IF EXISTS (SELECT 1 FROM information_schema.referential_constraints WHERE constraint_name = 'fk_transactions_invoice' AND delete_rule = 'CASCADE') THEN
+			ALTER TABLE invoices DROP CONSTRAINT fk_transactions_invoice;
+		END IF;
+	END $$`)
+
+	err = db.AutoMigrate(&entity.User{}, &entity.Profile{}, &entity.Role{}, &entity.Brand{}, &entity.Bike{}, &entity.BikeRelation{}, &entity.BikeVariant{}, &entity.BikeImage{}, &entity.SpecAttribute{}, &entity.BikeSpec{}, &entity.Discount{}, &entity.PriceHistory{}, &entity.StockMovement{}, &entity.StockAlert{}, &entity.Review{}, &entity.Transaction{}, &entity.TransactionStatusHistory{}, &entity.Order{}, &entity.Refund{}, &entity.RefundItem{}, &entity.Invoice{}, &entity.InvoiceCounter{}, &entity.Category{}, &entity.Cart{}, &entity.CartItem{}, &entity.IdempotencyKey{})
 	utils.PanicIfError(err)
 
+	// snapshot bike details onto orders placed before orders stored them
+	db.Exec("UPDATE orders SET unit_price = orders.total_price / orders.quantity, bike_name = bikes.name, bike_brand = bikes.brand FROM bikes WHERE bikes.id = orders.bike_id AND orders.bike_name IS NULL AND orders.quantity > 0")
+
+	// give bikes created before variants existed a default variant holding their stock,
+	// and point their cart items and orders at it
+	db.Exec("INSERT INTO bike_variants (bike_id, sku, stock, is_available, created_at, updated_at) SELECT id, 'BIKE-' || id, stock, is_available, NOW(), NOW() FROM bikes WHERE NOT EXISTS (SELECT 1 FROM bike_variants WHERE bike_variants.bike_id = bikes.id)")
+	db.Exec("UPDATE cart_items SET bike_variant_id = (SELECT MIN(id) FROM bike_variants WHERE bike_variants.bike_id = cart_items.bike_id) WHERE bike_variant_id IS NULL")
+	db.Exec("UPDATE orders SET bike_variant_id = bike_variants.id, sku = bike_variants.sku FROM bike_variants WHERE bike_variants.id = (SELECT MIN(id) FROM bike_variants WHERE bike_variants.bike_id = orders.bike_id) AND orders.bike_variant_id IS NULL")
+	db.Exec("DROP INDEX IF EXISTS idx_cart_bike")
+
+	// open the inventory ledger of variants created before it existed with their current stock
+	db.Exec(`INSERT INTO stock_movements (bike_id, bike_variant_id, type, quantity, note, created_at)
+		SELECT bike_id, id, 'adjustment', stock, 'opening balance', NOW() FROM bike_variants
+		WHERE stock <> 0 AND NOT EXISTS (SELECT 1 FROM stock_movements WHERE stock_movements.bike_variant_id = bike_variants.id)`)
+
+	// turn the distinct brand names of bikes into brands, spellings that only differ in case or spacing
+	// become one brand named after the most used spelling
+	db.Exec(`INSERT INTO brands (name, slug, created_at, updated_at)
+		SELECT DISTINCT ON (slug) name, slug, NOW(), NOW() FROM (
+			SELECT trim(brand) AS name, trim(both '-' from regexp_replace(lower(brand), '[^a-z0-9]+', '-', 'g')) AS slug, COUNT(*) AS uses
+			FROM bikes WHERE brand_id IS NULL GROUP BY 1, 2
+		) AS spellings
+		WHERE slug <> ''
+		ORDER BY slug, uses DESC, name
+		ON CONFLICT DO NOTHING`)
+	db.Exec(`UPDATE bikes SET brand_id = brands.id, brand = brands.name FROM brands
+		WHERE brands.slug = trim(both '-' from regexp_replace(lower(bikes.brand), '[^a-z0-9]+', '-', 'g')) AND bikes.brand_id IS NULL`)
+
+	// give categories created before slugs existed one derived from their name
+	db.Exec(`UPDATE categories SET slug = generated.slug || CASE WHEN generated.taken > 1 THEN '-' || categories.id ELSE '' END
+		FROM (
+			SELECT id, trim(both '-' from regexp_replace(lower(name), '[^a-z0-9]+', '-', 'g')) AS slug,
+				COUNT(*) OVER (PARTITION BY trim(both '-' from regexp_replace(lower(name), '[^a-z0-9]+', '-', 'g'))) AS taken
+			FROM categories WHERE slug IS NULL OR slug = ''
+		) AS generated
+		WHERE generated.id = categories.id`)
+
+	// partially refunded transactions go on with fulfilment from the stage they were refunded in
+	db.Exec(`UPDATE transactions SET fulfilment_status = COALESCE((
+			SELECT to_status FROM transaction_status_histories
+			WHERE transaction_status_histories.transaction_id = transactions.id AND to_status <> 'partially_refunded'
+			ORDER BY id DESC LIMIT 1
+		), 'paid')
+		WHERE status = 'partially_refunded' AND (fulfilment_status IS NULL OR fulfilment_status = '')`)
+
+	// invoice numbers are handed out in payment order, carrying on from the numbers the old sequence handed out
+	db.Exec(`INSERT INTO invoice_counters (id, last_number, updated_at)
+		SELECT 1, COALESCE(MAX(CAST(split_part(number, '-', 3) AS bigint)), 0), NOW() FROM invoices
+		ON CONFLICT DO NOTHING`)
+	db.Exec("DROP SEQUENCE IF EXISTS invoice_number_seq")
+
 	// create full text index on bikes.name
 	db.Exec("CREATE INDEX IF NOT EXISTS idx_name_fulltext ON bikes USING GIN (to_tsvector('english', name))")
 
+	// trigram indexes behind the typo tolerant search suggestions
+	db.Exec("CREATE EXTENSION IF NOT EXISTS pg_trgm")
+	db.Exec("CREATE INDEX IF NOT EXISTS idx_bikes_name_trgm ON bikes USING GIN (name gin_trgm_ops) WHERE deleted_at IS NULL")
+	db.Exec("CREATE INDEX IF NOT EXISTS idx_brands_name_trgm ON brands USING GIN (name gin_trgm_ops)")
+	db.Exec("CREATE INDEX IF NOT EXISTS idx_categories_name_trgm ON categories USING GIN (name gin_trgm_ops) WHERE deleted_at IS NULL")
+
 	return db
 }
@@ -1,10 +1,12 @@
 package app
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"net/url"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
@@ -20,6 +22,7 @@ import (
 	"github.com/gowesmart/api-gowesmart/exceptions"
 	"github.com/gowesmart/api-gowesmart/services"
 	"github.com/gowesmart/api-gowesmart/utils"
+	"github.com/gowesmart/api-gowesmart/workers"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 	"go.uber.org/zap"
@@ -55,6 +58,12 @@ func NewRouter() *gin.Engine {
 			_, err := url.ParseRequestURI(fl.Field().String())
 			return err == nil
 		})
+		v.RegisterValidation("slug", func(fl validator.FieldLevel) bool {
+			return utils.IsSlug(fl.Field().String())
+		})
+		v.RegisterValidation("spec_key", func(fl validator.FieldLevel) bool {
+			return utils.IsSpecKey(fl.Field().String())
+		})
 	}
 
 	cfg := zap.Config{
@@ -75,15 +84,65 @@ func NewRouter() *gin.Engine {
 
 	db := NewConnection()
 
+	paymentGateway := utils.NewPaymentGateway()
+
+	paymentWindow, err := time.ParseDuration(utils.GetEnv("TRANSACTION_PAYMENT_WINDOW", "24h"))
+	utils.PanicIfError(err)
+	restoreCartOnExpiry, err := strconv.ParseBool(utils.GetEnv("TRANSACTION_EXPIRY_RESTORE_CART", "true"))
+	utils.PanicIfError(err)
+
+	storage := utils.NewStorage()
+	bikeImageMaxSize, err := strconv.ParseInt(utils.GetEnv("BIKE_IMAGE_MAX_SIZE", "5242880"), 10, 64)
+	utils.PanicIfError(err)
+
 	userService := services.NewUserService()
 	roleService := services.NewRoleService()
 	profileService := services.NewProfileService()
-	transactionService := services.NewTransactionService()
+	transactionService := services.NewTransactionService(paymentGateway, paymentWindow, restoreCartOnExpiry, utils.NewInvoiceIssuer())
 	reviewService := services.NewReviewService()
 	categoryService := services.NewCategoryService()
+	brandService := services.NewBrandService()
+	specAttributeService := services.NewSpecAttributeService()
+	discountService := services.NewDiscountService()
 	bikeService := services.NewBikeService()
+	bikeVariantService := services.NewBikeVariantService()
+	bikeImageService := services.NewBikeImageService(storage, bikeImageMaxSize)
+	bikeCatalogService := services.NewBikeCatalogService()
+	stockMovementService := services.NewStockMovementService()
+	stockAlertService := services.NewStockAlertService(utils.NewNotifier(logger))
+	relatedBikeService := services.NewRelatedBikeService()
 	cartItemService := services.NewCartItemService()
 
+	// ======================== WORKERS =======================
+
+	if utils.GetEnv("TRANSACTION_EXPIRY_WORKER_ENABLED", "true") == "true" {
+		expiryInterval, err := time.ParseDuration(utils.GetEnv("TRANSACTION_EXPIRY_INTERVAL", "1m"))
+		utils.PanicIfError(err)
+
+		go workers.NewTransactionExpiryWorker(db, logger, transactionService, expiryInterval).Start(context.Background())
+	}
+
+	if utils.GetEnv("BIKE_RATING_WORKER_ENABLED", "true") == "true" {
+		ratingInterval, err := time.ParseDuration(utils.GetEnv("BIKE_RATING_WORKER_INTERVAL", "24h"))
+		utils.PanicIfError(err)
+
+		go workers.NewBikeRatingWorker(db, logger, reviewService, ratingInterval).Start(context.Background())
+	}
+
+	if utils.GetEnv("RELATED_BIKES_WORKER_ENABLED", "true") == "true" {
+		relatedInterval, err := time.ParseDuration(utils.GetEnv("RELATED_BIKES_WORKER_INTERVAL", "6h"))
+		utils.PanicIfError(err)
+
+		go workers.NewRelatedBikeWorker(db, logger, relatedBikeService, relatedInterval).Start(context.Background())
+	}
+
+	if utils.GetEnv("STOCK_ALERT_WORKER_ENABLED", "true") == "true" {
+		alertInterval, err := time.ParseDuration(utils.GetEnv("STOCK_ALERT_WORKER_INTERVAL", "1m"))
+		utils.PanicIfError(err)
+
+		go workers.NewStockAlertWorker(db, logger, stockAlertService, alertInterval).Start(context.Background())
+	}
+
 	// ======================== USER =======================
 
 	userController := controllers.NewUserController(userService, profileService, transactionService, cartItemService)
@@ -91,8 +150,13 @@ func NewRouter() *gin.Engine {
 	transactionController := controllers.NewTransactionController(*transactionService)
 	reviewController := controllers.NewReviewController(reviewService)
 	categoryController := controllers.NewCategoryController(categoryService)
-	bikeController := controllers.NewBikeController(bikeService, reviewService)
-	cartItemController := controllers.NewCartController(*cartItemService)
+	brandController := controllers.NewBrandController(brandService)
+	specAttributeController := controllers.NewSpecAttributeController(specAttributeService)
+	discountController := controllers.NewDiscountController(discountService)
+	stockAlertController := controllers.NewStockAlertController(stockAlertService)
+	bikeController := controllers.NewBikeController(bikeService, reviewService, bikeVariantService, bikeImageService, bikeCatalogService, stockMovementService, relatedBikeService)
+	cartItemController := controllers.NewCartController(*cartItemService, *transactionService)
+	paymentController := controllers.NewPaymentController(transactionService)
 
 	r := gin.Default()
 
@@ -101,7 +165,7 @@ func NewRouter() *gin.Engine {
 			AllowAllOrigins:  true,
 			AllowCredentials: true,
 			AllowMethods:     []string{"GET", "POST", "PATCH", "DELETE", "OPTIONS"},
-			AllowHeaders:     []string{"Content-Type", "X-XSRF-TOKEN", "Accept", "Origin", "X-Requested-With", "Authorization", "Pragma", "Cache-Control", "Expires"},
+			AllowHeaders:     []string{"Content-Type", "X-XSRF-TOKEN", "Accept", "Origin", "X-Requested-With", "Authorization", "Pragma", "Cache-Control", "Expires", middlewares.IdempotencyKeyHeader},
 			MaxAge:           12 * time.Hour,
 		},
 	))
@@ -113,12 +177,27 @@ func NewRouter() *gin.Engine {
 
 	r.Use(exceptions.GlobalErrorHandler)
 
+	// uploaded files are served by the api itself unless they live in an object storage
+	if localStorage, ok := storage.(*utils.LocalStorage); ok {
+		r.Static(utils.LocalStorageRoute, localStorage.Dir)
+	}
+
+	// the fake payment gateway's payment links are served by the api itself
+	if fakeGateway, ok := paymentGateway.(*utils.FakePaymentGateway); ok {
+		fakePaymentController := controllers.NewFakePaymentController(fakeGateway, transactionService)
+		r.GET("/fake-payments/:id", fakePaymentController.Pay)
+	}
+
 	r.NoRoute(func(c *gin.Context) {
 		panic(exceptions.NewCustomError(http.StatusNotFound, fmt.Sprintf("path not found, use https://%s for API docs", utils.MustGetEnv("SERVER_HOST")+"/docs/index.html")))
 	})
 
 	apiRouter := r.Group("/api")
 
+	idempotencyKeyTTL, err := time.ParseDuration(utils.GetEnv("IDEMPOTENCY_KEY_TTL", "24h"))
+	utils.PanicIfError(err)
+	idempotency := middlewares.NewIdempotencyMiddleware(idempotencyKeyTTL)
+
 	// ======================== AUTH ROUTE =======================
 
 	authRouter := apiRouter.Group("/auth")
@@ -147,10 +226,18 @@ func NewRouter() *gin.Engine {
 
 	transactionRouter.GET("", transactionController.GetAll)
 	transactionRouter.GET("/:id", transactionController.GetById)
-	transactionRouter.POST("", transactionController.Create)
+	transactionRouter.GET("/:id/invoice.pdf", transactionController.Invoice)
+	transactionRouter.POST("", idempotency, transactionController.Create)
 	transactionRouter.PATCH("/:id", transactionController.Update)
 	transactionRouter.DELETE("/:id", transactionController.Delete)
-	transactionRouter.PATCH("/payment/:id", transactionController.Pay)
+	transactionRouter.PATCH("/payment/:id", idempotency, transactionController.Pay)
+	transactionRouter.PATCH("/:id/status", transactionController.UpdateStatus)
+	transactionRouter.POST("/:id/refunds", idempotency, transactionController.Refund)
+
+	// ======================== PAYMENT ROUTE ======================
+	paymentRouter := apiRouter.Group("/payments")
+
+	paymentRouter.POST("/midtrans/notification", paymentController.MidtransNotification)
 
 	// ======================== Review ROUTE ======================
 	reviewRouter := apiRouter.Group("/reviews")
@@ -160,6 +247,7 @@ func NewRouter() *gin.Engine {
 	reviewRouter.GET("", reviewController.GetAllReviews)
 	reviewRouter.GET("/:id", reviewController.GetReviewByID)
 	reviewRouter.GET("/order/:id", reviewController.GetReviewByOrderID)
+	reviewRouter.POST("/recompute-ratings", reviewController.RecomputeBikeRatings)
 
 	// ======================== Category ROUTE ======================
 	categoryRouter := apiRouter.Group("/categories")
@@ -167,7 +255,37 @@ func NewRouter() *gin.Engine {
 	categoryRouter.PATCH("/:id", categoryController.UpdateCategory)
 	categoryRouter.DELETE("/:id", categoryController.DeleteCategory)
 	categoryRouter.GET("", categoryController.GetAllCategories)
+	categoryRouter.GET("/tree", categoryController.GetCategoryTree)
 	categoryRouter.GET("/:id", categoryController.GetCategoryByID)
+	categoryRouter.POST("/:id/restore", categoryController.RestoreCategory)
+
+	// ======================== Brand ROUTE ======================
+	brandRouter := apiRouter.Group("/brands")
+	brandRouter.POST("", brandController.CreateBrand)
+	brandRouter.PATCH("/:id", brandController.UpdateBrand)
+	brandRouter.DELETE("/:id", brandController.DeleteBrand)
+	brandRouter.GET("", brandController.GetAllBrands)
+	brandRouter.GET("/:id", brandController.GetBrandByID)
+
+	// ======================== Spec Attribute ROUTE ======================
+	specAttributeRouter := apiRouter.Group("/spec-attributes")
+	specAttributeRouter.POST("", specAttributeController.CreateSpecAttribute)
+	specAttributeRouter.PATCH("/:id", specAttributeController.UpdateSpecAttribute)
+	specAttributeRouter.DELETE("/:id", specAttributeController.DeleteSpecAttribute)
+	specAttributeRouter.GET("", specAttributeController.GetSpecAttributes)
+
+	// ======================== Discount ROUTE ======================
+	discountRouter := apiRouter.Group("/discounts")
+	discountRouter.POST("", discountController.CreateDiscount)
+	discountRouter.PATCH("/:id", discountController.UpdateDiscount)
+	discountRouter.DELETE("/:id", discountController.DeleteDiscount)
+	discountRouter.GET("", discountController.GetAllDiscounts)
+	discountRouter.GET("/:id", discountController.GetDiscountByID)
+
+	// ======================== Stock Alert ROUTE ======================
+	stockAlertRouter := apiRouter.Group("/stock-alerts")
+	stockAlertRouter.GET("", stockAlertController.GetStockAlerts)
+	stockAlertRouter.PATCH("/:id/acknowledge", stockAlertController.AcknowledgeStockAlert)
 
 	// ======================== Bike ROUTE ======================
 	bikeRouter := apiRouter.Group("/bikes")
@@ -175,8 +293,26 @@ func NewRouter() *gin.Engine {
 	bikeRouter.PATCH("/:id", bikeController.UpdateBike)
 	bikeRouter.DELETE("/:id", bikeController.DeleteBike)
 	bikeRouter.GET("", bikeController.GetAllBikes)
+	bikeRouter.POST("/import", bikeController.ImportBikes)
+	bikeRouter.GET("/export", bikeController.ExportBikes)
+	bikeRouter.GET("/compare", bikeController.CompareBikes)
+	bikeRouter.GET("/suggest", bikeController.SuggestBikes)
+	bikeRouter.POST("/stock/reconcile", bikeController.ReconcileStock)
+	bikeRouter.POST("/related/recompute", bikeController.RecomputeRelatedBikes)
 	bikeRouter.GET("/:id", bikeController.GetBikeByID)
 	bikeRouter.GET("/:id/reviews", bikeController.GetReviews)
+	bikeRouter.GET("/:id/related", bikeController.GetRelatedBikes)
+	bikeRouter.POST("/:id/restore", bikeController.RestoreBike)
+	bikeRouter.POST("/:id/variants", bikeController.CreateVariant)
+	bikeRouter.PATCH("/:id/variants/:variantId", bikeController.UpdateVariant)
+	bikeRouter.DELETE("/:id/variants/:variantId", bikeController.DeleteVariant)
+	bikeRouter.POST("/:id/variants/:variantId/stock-movements", bikeController.CreateStockMovement)
+	bikeRouter.GET("/:id/stock-movements", bikeController.GetStockMovements)
+	bikeRouter.GET("/:id/images", bikeController.GetImages)
+	bikeRouter.POST("/:id/images", bikeController.UploadImage)
+	bikeRouter.PATCH("/:id/images/order", bikeController.ReorderImages)
+	bikeRouter.PATCH("/:id/images/:imageId/primary", bikeController.SetPrimaryImage)
+	bikeRouter.DELETE("/:id/images/:imageId", bikeController.DeleteImage)
 
 	// ======================== CART ITEM ROUTE ======================
 	cartRouter := apiRouter.Group("/carts")
@@ -184,6 +320,7 @@ func NewRouter() *gin.Engine {
 	cartRouter.POST("", cartItemController.Create)
 	cartRouter.PATCH("", cartItemController.Update)
 	cartRouter.DELETE("", cartItemController.Delete)
+	cartRouter.POST("/checkout", idempotency, cartItemController.Checkout)
 
 	// Register routes
 	r.PATCH("/roles/update", roleController.UpdateRoleByUserID)
@@ -1,6 +1,7 @@
 package controllers
 
 import (
+	"fmt"
 	"net/http"
 	"strconv"
 
@@ -8,20 +9,30 @@ import (
 	"github.com/gowesmart/api-gowesmart/exceptions"
 	_ "github.com/gowesmart/api-gowesmart/model/web"
 	"github.com/gowesmart/api-gowesmart/model/web/request"
-	_ "github.com/gowesmart/api-gowesmart/model/web/response"
+	"github.com/gowesmart/api-gowesmart/model/web/response"
 	"github.com/gowesmart/api-gowesmart/services"
 	"github.com/gowesmart/api-gowesmart/utils"
 )
 
 type BikeController struct {
-	bikeService   services.BikeService
-	reviewService services.ReviewService
+	bikeService          services.BikeService
+	reviewService        services.ReviewService
+	bikeVariantService   services.BikeVariantService
+	bikeImageService     services.BikeImageService
+	bikeCatalogService   services.BikeCatalogService
+	stockMovementService services.StockMovementService
+	relatedBikeService   services.RelatedBikeService
 }
 
-func NewBikeController(bikeService *services.BikeService, reviewService *services.ReviewService) *BikeController {
+func NewBikeController(bikeService *services.BikeService, reviewService *services.ReviewService, bikeVariantService *services.BikeVariantService, bikeImageService *services.BikeImageService, bikeCatalogService *services.BikeCatalogService, stockMovementService *services.StockMovementService, relatedBikeService *services.RelatedBikeService) *BikeController {
 	return &BikeController{
 		*bikeService,
 		*reviewService,
+		*bikeVariantService,
+		*bikeImageService,
+		*bikeCatalogService,
+		*stockMovementService,
+		*relatedBikeService,
 	}
 }
 
@@ -85,8 +96,8 @@ func (controller *BikeController) UpdateBike(c *gin.Context) {
 }
 
 // DeleteBike godoc
-// @Summary Delete a bike
-// @Description	Delete a bike by ID
+// @Summary Archive a bike
+// @Description	Archive a bike by ID. It is hidden from listings and removed from carts, but still resolves for orders and reviews and can be restored.
 // @Tags Bikes
 // @Produce json
 // @Param Authorization	header string true "Authorization. How to input in swagger : 'Bearer <insert_your_token_here>'"
@@ -112,18 +123,28 @@ func (controller *BikeController) DeleteBike(c *gin.Context) {
 
 // GetAllBikes godoc
 // @Summary Get all bikes
-// @Description	Get all bikes
+// @Description	Search bikes. The metadata includes facet counts per brand, category and price bucket, each facet ignoring its own filter.
 // @Tags Bikes
 // @Produce json
 // @Param limit query int false "Limit" default(10)
 // @Param page query int false "Page" default(1)
 // @Param name query string false "Name"
-// @Param category_id query int false "Brand ID"
-// @Param min_price query int false "Minimum Price"
-// @Param max_price query int false "Maximum Price"
+// @Param category_id query int false "Category ID, subcategories included"
+// @Param category query string false "Category slug, subcategories included"
+// @Param brand query []string false "Brand slugs or names, repeated or comma separated" collectionFormat(multi)
+// @Param brand_id query []int false "Brand IDs" collectionFormat(multi)
+// @Param min_price query int false "Minimum sale price, discounts applied"
+// @Param max_price query int false "Maximum sale price, discounts applied"
 // @Param min_year query int false "Minimum Year"
 // @Param max_year query int false "Maximum Year"
+// @Param min_rating query number false "Minimum average rating"
+// @Param in_stock query bool false "Only bikes that can be bought"
+// @Param sort query string false "Sort order, defaults to id" Enums(price_asc, price_desc, newest, rating, popularity)
+// @Param include_archived query bool false "Include archived bikes, admin only"
+// @Param spec[key] query string false "Spec value, e.g. spec[wheel_size]=29. Enum values may be comma separated, numbers also take ranges with spec[weight_min] and spec[weight_max]"
 // @Success 200 {object} web.WebSuccess[[]response.BikeResponse]
+// @Failure 400 {object} web.WebBadRequestError
+// @Failure 403 {object} web.WebForbiddenError
 // @Failure 500 {object} web.WebInternalServerError
 // @Router /api/bikes [get]
 func (controller *BikeController) GetAllBikes(c *gin.Context) {
@@ -132,6 +153,12 @@ func (controller *BikeController) GetAllBikes(c *gin.Context) {
 	err := c.ShouldBindQuery(&bikeQueryRequest)
 	utils.PanicIfError(err)
 
+	bikeQueryRequest.Specs = c.QueryMap("spec")
+
+	if bikeQueryRequest.IncludeArchived {
+		utils.UserRoleMustAdmin(c)
+	}
+
 	res, metadata, err := controller.bikeService.GetAllBikes(c, &bikeQueryRequest)
 	utils.PanicIfError(err)
 
@@ -140,9 +167,10 @@ func (controller *BikeController) GetAllBikes(c *gin.Context) {
 
 // GetBikeByID godoc
 // @Summary Get a bike by ID
-// @Description	Get a bike by ID
+// @Description	Get a bike by ID. Admins also get its price history and the discounts that target it.
 // @Tags Bikes
 // @Produce json
+// @Param Authorization	header string false "Authorization, only needed for the admin details. How to input in swagger : 'Bearer <insert_your_token_here>'"
 // @Param id path uint true	"Bike ID"
 // @Success 200 {object} web.WebSuccess[response.BikeResponse]
 // @Failure 404 {object} web.WebNotFoundError
@@ -154,7 +182,10 @@ func (controller *BikeController) GetBikeByID(c *gin.Context) {
 		utils.PanicIfError(exceptions.NewCustomError(http.StatusBadRequest, "id must be an integer"))
 	}
 
-	res, err := controller.bikeService.GetBikeByID(c, uint(id))
+	claims, err := utils.ExtractTokenClaims(c)
+	isAdmin := err == nil && claims.IsAdmin()
+
+	res, err := controller.bikeService.GetBikeByID(c, uint(id), isAdmin)
 	utils.PanicIfError(err)
 
 	utils.ToResponseJSON(c, http.StatusOK, res, nil)
@@ -180,3 +211,571 @@ func (controller *BikeController) GetReviews(c *gin.Context) {
 
 	utils.ToResponseJSON(c, http.StatusOK, res, nil)
 }
+
+// SuggestBikes godoc
+// @Summary Suggest bikes
+// @Description Autocomplete a search with the bikes, brands and categories whose names look like what was typed, tolerating partial words and typos. Highlights mark the matched characters of each suggestion
+// @Tags Bikes
+// @Produce json
+// @Param q query string true "What was typed so far, at least 2 characters"
+// @Param limit query int false "Limit, at most 20" default(8)
+// @Success 200 {object} web.WebSuccess[[]response.BikeSuggestionResponse]
+// @Failure 400 {object} web.WebBadRequestError
+// @Failure 500 {object} web.WebInternalServerError
+// @Router /api/bikes/suggest [get]
+func (controller *BikeController) SuggestBikes(c *gin.Context) {
+	var suggestReq request.BikeSuggestRequest
+	err := c.ShouldBindQuery(&suggestReq)
+	utils.PanicIfError(err)
+
+	res, err := controller.bikeService.SuggestBikes(c, &suggestReq)
+	utils.PanicIfError(err)
+
+	utils.ToResponseJSON(c, http.StatusOK, res, nil)
+}
+
+// CompareBikes godoc
+// @Summary Compare bikes
+// @Description Line up 2 to 4 bikes attribute by attribute: price, year, brand, category, average rating, stock status and specs, marking the attributes whose values differ
+// @Tags Bikes
+// @Produce json
+// @Param ids query string true "Comma separated bike IDs, e.g. 1,2,3"
+// @Success 200 {object} web.WebSuccess[response.BikeComparisonResponse]
+// @Failure 400 {object} web.WebBadRequestError
+// @Failure 500 {object} web.WebInternalServerError
+// @Router /api/bikes/compare [get]
+func (controller *BikeController) CompareBikes(c *gin.Context) {
+	var compareReq request.BikeCompareRequest
+	err := c.ShouldBindQuery(&compareReq)
+	utils.PanicIfError(err)
+
+	res, err := controller.bikeService.CompareBikes(c, &compareReq)
+	utils.PanicIfError(err)
+
+	utils.ToResponseJSON(c, http.StatusOK, res, nil)
+}
+
+// GetRelatedBikes godoc
+// @Summary Get related bikes
+// @Description Get the bikes customers bought together with a bike, most often first, topped up with bikes of the same category at a similar price when there aren't enough
+// @Tags Bikes
+// @Produce json
+// @Param id path uint true "Bike ID"
+// @Param limit query int false "Limit, at most 20" default(8)
+// @Success 200 {object} web.WebSuccess[[]response.RelatedBikeResponse]
+// @Failure 400 {object} web.WebBadRequestError
+// @Failure 404 {object} web.WebNotFoundError
+// @Failure 500 {object} web.WebInternalServerError
+// @Router /api/bikes/{id}/related [get]
+func (controller *BikeController) GetRelatedBikes(c *gin.Context) {
+	bikeID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.PanicIfError(exceptions.NewCustomError(http.StatusBadRequest, "id must be an integer"))
+	}
+
+	var relatedQueryReq request.RelatedBikeQueryRequest
+	err = c.ShouldBindQuery(&relatedQueryReq)
+	utils.PanicIfError(err)
+
+	res, err := controller.relatedBikeService.GetRelatedBikes(c, uint(bikeID), &relatedQueryReq)
+	utils.PanicIfError(err)
+
+	utils.ToResponseJSON(c, http.StatusOK, res, nil)
+}
+
+// RecomputeRelatedBikes godoc
+// @Summary Recompute related bikes
+// @Description Rebuild the bikes bought together from the order history right away instead of waiting for the worker, returning how many relations were stored
+// @Tags Bikes
+// @Produce json
+// @Param Authorization	header string true "Authorization. How to input in swagger : 'Bearer <insert_your_token_here>'"
+// @Security BearerToken
+// @Success 200 {object} web.WebSuccess[response.RecomputeRelatedBikesResponse]
+// @Failure 403 {object} web.WebForbiddenError
+// @Failure 500 {object} web.WebInternalServerError
+// @Router /api/bikes/related/recompute [post]
+func (controller *BikeController) RecomputeRelatedBikes(c *gin.Context) {
+	utils.UserRoleMustAdmin(c)
+
+	db, logger := utils.GetDBAndLogger(c)
+
+	relations, err := controller.relatedBikeService.RecomputeRelatedBikes(db, logger)
+	utils.PanicIfError(err)
+
+	utils.ToResponseJSON(c, http.StatusOK, response.RecomputeRelatedBikesResponse{Relations: relations}, nil)
+}
+
+// CreateVariant godoc
+// @Summary Create a bike variant
+// @Description Add a variant (frame size, colour) with its own SKU, price and stock to a bike
+// @Tags Bikes
+// @Accept json
+// @Produce json
+// @Param Authorization	header string true "Authorization. How to input in swagger : 'Bearer <insert_your_token_here>'"
+// @Security BearerToken
+// @Param id path uint true "Bike ID"
+// @Param variant body request.CreateBikeVariantRequest true "Bike variant body"
+// @Success 201 {object} web.WebSuccess[response.BikeVariantResponse]
+// @Failure 400 {object} web.WebBadRequestError
+// @Failure 404 {object} web.WebNotFoundError
+// @Failure 409 {object} web.WebError
+// @Failure 500 {object} web.WebInternalServerError
+// @Router /api/bikes/{id}/variants [post]
+func (controller *BikeController) CreateVariant(c *gin.Context) {
+	utils.UserRoleMustAdmin(c)
+
+	bikeID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.PanicIfError(exceptions.NewCustomError(http.StatusBadRequest, "id must be an integer"))
+	}
+
+	var variantReq request.CreateBikeVariantRequest
+	err = c.ShouldBindJSON(&variantReq)
+	utils.PanicIfError(err)
+
+	res, err := controller.bikeVariantService.CreateVariant(c, uint(bikeID), &variantReq)
+	utils.PanicIfError(err)
+
+	utils.ToResponseJSON(c, http.StatusCreated, res, nil)
+}
+
+// UpdateVariant godoc
+// @Summary Update a bike variant
+// @Description Update a bike variant's SKU, attributes, price or availability. Stock is changed by posting stock movements
+// @Tags Bikes
+// @Accept json
+// @Produce json
+// @Param Authorization	header string true "Authorization. How to input in swagger : 'Bearer <insert_your_token_here>'"
+// @Security BearerToken
+// @Param id path uint true "Bike ID"
+// @Param variantId path uint true "Bike variant ID"
+// @Param variant body request.UpdateBikeVariantRequest true "Bike variant body"
+// @Success 200 {object} web.WebSuccess[response.BikeVariantResponse]
+// @Failure 400 {object} web.WebBadRequestError
+// @Failure 404 {object} web.WebNotFoundError
+// @Failure 409 {object} web.WebError
+// @Failure 500 {object} web.WebInternalServerError
+// @Router /api/bikes/{id}/variants/{variantId} [patch]
+func (controller *BikeController) UpdateVariant(c *gin.Context) {
+	utils.UserRoleMustAdmin(c)
+
+	bikeID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.PanicIfError(exceptions.NewCustomError(http.StatusBadRequest, "id must be an integer"))
+	}
+
+	variantID, err := strconv.ParseUint(c.Param("variantId"), 10, 32)
+	if err != nil {
+		utils.PanicIfError(exceptions.NewCustomError(http.StatusBadRequest, "variantId must be an integer"))
+	}
+
+	var variantReq request.UpdateBikeVariantRequest
+	err = c.ShouldBindJSON(&variantReq)
+	utils.PanicIfError(err)
+
+	res, err := controller.bikeVariantService.UpdateVariant(c, uint(bikeID), uint(variantID), &variantReq)
+	utils.PanicIfError(err)
+
+	utils.ToResponseJSON(c, http.StatusOK, res, nil)
+}
+
+// DeleteVariant godoc
+// @Summary Delete a bike variant
+// @Description Delete a bike variant, a bike must keep at least one variant and variants reserved by pending transactions can't be deleted
+// @Tags Bikes
+// @Produce json
+// @Param Authorization	header string true "Authorization. How to input in swagger : 'Bearer <insert_your_token_here>'"
+// @Security BearerToken
+// @Param id path uint true "Bike ID"
+// @Param variantId path uint true "Bike variant ID"
+// @Success 204
+// @Failure 400 {object} web.WebBadRequestError
+// @Failure 404 {object} web.WebNotFoundError
+// @Failure 409 {object} web.WebError
+// @Failure 500 {object} web.WebInternalServerError
+// @Router /api/bikes/{id}/variants/{variantId} [delete]
+func (controller *BikeController) DeleteVariant(c *gin.Context) {
+	utils.UserRoleMustAdmin(c)
+
+	bikeID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.PanicIfError(exceptions.NewCustomError(http.StatusBadRequest, "id must be an integer"))
+	}
+
+	variantID, err := strconv.ParseUint(c.Param("variantId"), 10, 32)
+	if err != nil {
+		utils.PanicIfError(exceptions.NewCustomError(http.StatusBadRequest, "variantId must be an integer"))
+	}
+
+	err = controller.bikeVariantService.DeleteVariant(c, uint(bikeID), uint(variantID))
+	utils.PanicIfError(err)
+
+	c.Status(http.StatusNoContent)
+}
+
+// CreateStockMovement godoc
+// @Summary Post a stock movement
+// @Description Restock a bike variant, take back returned units or adjust its stock after a count. The movement is recorded in the inventory ledger
+// @Tags Bikes
+// @Accept json
+// @Produce json
+// @Param Authorization	header string true "Authorization. How to input in swagger : 'Bearer <insert_your_token_here>'"
+// @Security BearerToken
+// @Param id path uint true "Bike ID"
+// @Param variantId path uint true "Bike variant ID"
+// @Param movement body request.CreateStockMovementRequest true "Stock movement body"
+// @Success 201 {object} web.WebSuccess[response.StockMovementResponse]
+// @Failure 400 {object} web.WebBadRequestError
+// @Failure 404 {object} web.WebNotFoundError
+// @Failure 409 {object} web.WebError
+// @Failure 500 {object} web.WebInternalServerError
+// @Router /api/bikes/{id}/variants/{variantId}/stock-movements [post]
+func (controller *BikeController) CreateStockMovement(c *gin.Context) {
+	utils.UserRoleMustAdmin(c)
+
+	claims, err := utils.ExtractTokenClaims(c)
+	utils.PanicIfError(err)
+
+	bikeID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.PanicIfError(exceptions.NewCustomError(http.StatusBadRequest, "id must be an integer"))
+	}
+
+	variantID, err := strconv.ParseUint(c.Param("variantId"), 10, 32)
+	if err != nil {
+		utils.PanicIfError(exceptions.NewCustomError(http.StatusBadRequest, "variantId must be an integer"))
+	}
+
+	var movementReq request.CreateStockMovementRequest
+	err = c.ShouldBindJSON(&movementReq)
+	utils.PanicIfError(err)
+
+	res, err := controller.stockMovementService.CreateStockMovement(c, uint(bikeID), uint(variantID), &movementReq, claims.UserID)
+	utils.PanicIfError(err)
+
+	utils.ToResponseJSON(c, http.StatusCreated, res, nil)
+}
+
+// GetStockMovements godoc
+// @Summary Get the stock movements of a bike
+// @Description Get the inventory ledger of a bike's variants, newest first
+// @Tags Bikes
+// @Produce json
+// @Param Authorization	header string true "Authorization. How to input in swagger : 'Bearer <insert_your_token_here>'"
+// @Security BearerToken
+// @Param id path uint true "Bike ID"
+// @Param variant_id query uint false "Only the movements of this variant"
+// @Param type query string false "Movement type" Enums(restock, sale, return, adjustment, reservation)
+// @Param limit query int false "Limit" default(10)
+// @Param page query int false "Page" default(1)
+// @Success 200 {object} web.WebSuccess[[]response.StockMovementResponse]
+// @Failure 400 {object} web.WebBadRequestError
+// @Failure 404 {object} web.WebNotFoundError
+// @Failure 500 {object} web.WebInternalServerError
+// @Router /api/bikes/{id}/stock-movements [get]
+func (controller *BikeController) GetStockMovements(c *gin.Context) {
+	utils.UserRoleMustAdmin(c)
+
+	bikeID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.PanicIfError(exceptions.NewCustomError(http.StatusBadRequest, "id must be an integer"))
+	}
+
+	var movementQueryReq request.StockMovementQueryRequest
+	err = c.ShouldBindQuery(&movementQueryReq)
+	utils.PanicIfError(err)
+
+	res, metadata, err := controller.stockMovementService.GetStockMovements(c, uint(bikeID), &movementQueryReq)
+	utils.PanicIfError(err)
+
+	utils.ToResponseJSON(c, http.StatusOK, res, metadata)
+}
+
+// ReconcileStock godoc
+// @Summary Reconcile stock with the inventory ledger
+// @Description Reset every bike variant whose stock doesn't match the sum of its stock movements to the ledger, and return the variants that were corrected
+// @Tags Bikes
+// @Produce json
+// @Param Authorization	header string true "Authorization. How to input in swagger : 'Bearer <insert_your_token_here>'"
+// @Security BearerToken
+// @Success 200 {object} web.WebSuccess[[]response.StockDiscrepancyResponse]
+// @Failure 403 {object} web.WebForbiddenError
+// @Failure 500 {object} web.WebInternalServerError
+// @Router /api/bikes/stock/reconcile [post]
+func (controller *BikeController) ReconcileStock(c *gin.Context) {
+	utils.UserRoleMustAdmin(c)
+
+	res, err := controller.stockMovementService.ReconcileStock(c)
+	utils.PanicIfError(err)
+
+	utils.ToResponseJSON(c, http.StatusOK, res, nil)
+}
+
+// GetImages godoc
+// @Summary Get bike images
+// @Description Get the image gallery of a bike in display order
+// @Tags Bikes
+// @Produce json
+// @Param id path uint true "Bike ID"
+// @Success 200 {object} web.WebSuccess[[]response.BikeImageResponse]
+// @Failure 400 {object} web.WebBadRequestError
+// @Failure 500 {object} web.WebInternalServerError
+// @Router /api/bikes/{id}/images [get]
+func (controller *BikeController) GetImages(c *gin.Context) {
+	bikeID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.PanicIfError(exceptions.NewCustomError(http.StatusBadRequest, "id must be an integer"))
+	}
+
+	res, err := controller.bikeImageService.GetImages(c, uint(bikeID))
+	utils.PanicIfError(err)
+
+	utils.ToResponseJSON(c, http.StatusOK, res, nil)
+}
+
+// UploadImage godoc
+// @Summary Upload a bike image
+// @Description Upload a JPEG, PNG or WebP image to the end of a bike's gallery. The first image of a bike becomes its primary image.
+// @Tags Bikes
+// @Accept multipart/form-data
+// @Produce json
+// @Param Authorization	header string true "Authorization. How to input in swagger : 'Bearer <insert_your_token_here>'"
+// @Security BearerToken
+// @Param id path uint true "Bike ID"
+// @Param image formData file true "Image file"
+// @Param is_primary formData bool false "Make the image the primary image of the bike"
+// @Success 201 {object} web.WebSuccess[response.BikeImageResponse]
+// @Failure 400 {object} web.WebBadRequestError
+// @Failure 404 {object} web.WebNotFoundError
+// @Failure 413 {object} web.WebError
+// @Failure 415 {object} web.WebError
+// @Failure 500 {object} web.WebInternalServerError
+// @Router /api/bikes/{id}/images [post]
+func (controller *BikeController) UploadImage(c *gin.Context) {
+	utils.UserRoleMustAdmin(c)
+
+	bikeID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.PanicIfError(exceptions.NewCustomError(http.StatusBadRequest, "id must be an integer"))
+	}
+
+	file, err := c.FormFile("image")
+	if err != nil {
+		utils.PanicIfError(exceptions.NewCustomError(http.StatusBadRequest, "image file is required"))
+	}
+
+	isPrimary := false
+	if value := c.PostForm("is_primary"); value != "" {
+		isPrimary, err = strconv.ParseBool(value)
+		if err != nil {
+			utils.PanicIfError(exceptions.NewCustomError(http.StatusBadRequest, "is_primary must be a boolean"))
+		}
+	}
+
+	res, err := controller.bikeImageService.UploadImage(c, uint(bikeID), file, isPrimary)
+	utils.PanicIfError(err)
+
+	utils.ToResponseJSON(c, http.StatusCreated, res, nil)
+}
+
+// ReorderImages godoc
+// @Summary Reorder bike images
+// @Description Set the display order of a bike's gallery, image_ids must list every image of the bike
+// @Tags Bikes
+// @Accept json
+// @Produce json
+// @Param Authorization	header string true "Authorization. How to input in swagger : 'Bearer <insert_your_token_here>'"
+// @Security BearerToken
+// @Param id path uint true "Bike ID"
+// @Param order body request.BikeImageReorderRequest true "Image order"
+// @Success 200 {object} web.WebSuccess[[]response.BikeImageResponse]
+// @Failure 400 {object} web.WebBadRequestError
+// @Failure 500 {object} web.WebInternalServerError
+// @Router /api/bikes/{id}/images/order [patch]
+func (controller *BikeController) ReorderImages(c *gin.Context) {
+	utils.UserRoleMustAdmin(c)
+
+	bikeID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.PanicIfError(exceptions.NewCustomError(http.StatusBadRequest, "id must be an integer"))
+	}
+
+	var reorderReq request.BikeImageReorderRequest
+	err = c.ShouldBindJSON(&reorderReq)
+	utils.PanicIfError(err)
+
+	res, err := controller.bikeImageService.ReorderImages(c, uint(bikeID), &reorderReq)
+	utils.PanicIfError(err)
+
+	utils.ToResponseJSON(c, http.StatusOK, res, nil)
+}
+
+// SetPrimaryImage godoc
+// @Summary Set the primary bike image
+// @Description Make an image the primary image of its bike, which is also used as the bike's image_url
+// @Tags Bikes
+// @Produce json
+// @Param Authorization	header string true "Authorization. How to input in swagger : 'Bearer <insert_your_token_here>'"
+// @Security BearerToken
+// @Param id path uint true "Bike ID"
+// @Param imageId path uint true "Bike image ID"
+// @Success 200 {object} web.WebSuccess[response.BikeImageResponse]
+// @Failure 400 {object} web.WebBadRequestError
+// @Failure 404 {object} web.WebNotFoundError
+// @Failure 500 {object} web.WebInternalServerError
+// @Router /api/bikes/{id}/images/{imageId}/primary [patch]
+func (controller *BikeController) SetPrimaryImage(c *gin.Context) {
+	utils.UserRoleMustAdmin(c)
+
+	bikeID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.PanicIfError(exceptions.NewCustomError(http.StatusBadRequest, "id must be an integer"))
+	}
+
+	imageID, err := strconv.ParseUint(c.Param("imageId"), 10, 32)
+	if err != nil {
+		utils.PanicIfError(exceptions.NewCustomError(http.StatusBadRequest, "imageId must be an integer"))
+	}
+
+	res, err := controller.bikeImageService.SetPrimaryImage(c, uint(bikeID), uint(imageID))
+	utils.PanicIfError(err)
+
+	utils.ToResponseJSON(c, http.StatusOK, res, nil)
+}
+
+// DeleteImage godoc
+// @Summary Delete a bike image
+// @Description Delete a bike image, the next image in the gallery becomes primary when the primary image is deleted
+// @Tags Bikes
+// @Produce json
+// @Param Authorization	header string true "Authorization. How to input in swagger : 'Bearer <insert_your_token_here>'"
+// @Security BearerToken
+// @Param id path uint true "Bike ID"
+// @Param imageId path uint true "Bike image ID"
+// @Success 204
+// @Failure 400 {object} web.WebBadRequestError
+// @Failure 404 {object} web.WebNotFoundError
+// @Failure 500 {object} web.WebInternalServerError
+// @Router /api/bikes/{id}/images/{imageId} [delete]
+func (controller *BikeController) DeleteImage(c *gin.Context) {
+	utils.UserRoleMustAdmin(c)
+
+	bikeID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.PanicIfError(exceptions.NewCustomError(http.StatusBadRequest, "id must be an integer"))
+	}
+
+	imageID, err := strconv.ParseUint(c.Param("imageId"), 10, 32)
+	if err != nil {
+		utils.PanicIfError(exceptions.NewCustomError(http.StatusBadRequest, "imageId must be an integer"))
+	}
+
+	err = controller.bikeImageService.DeleteImage(c, uint(bikeID), uint(imageID))
+	utils.PanicIfError(err)
+
+	c.Status(http.StatusNoContent)
+}
+
+// ImportBikes godoc
+// @Summary Import bikes
+// @Description Create or update bikes in bulk from a JSON array in the shape of database/bike.json, or from a CSV with the columns name, brand, category_id, description, year, price, image_url, stock, is_available, sku, frame_size, color and variant_price (one row per variant). Bikes are matched by SKU, then by name. Brands must exist and are matched by name regardless of case and spacing. Rows are validated like creating a bike; nothing is imported if any row is invalid. With dry_run the per-row report is returned without importing anything.
+// @Tags Bikes
+// @Accept json,text/csv
+// @Produce json
+// @Param Authorization	header string true "Authorization. How to input in swagger : 'Bearer <insert_your_token_here>'"
+// @Security BearerToken
+// @Param format query string false "Body format, defaults to csv for text/csv bodies and json otherwise" Enums(json, csv)
+// @Param dry_run query bool false "Only report what would be imported"
+// @Param bikes body []request.BikeImportRow true "Bikes"
+// @Success 200 {object} web.WebSuccess[response.BikeImportResponse]
+// @Failure 400 {object} web.WebBadRequestError
+// @Failure 413 {object} web.WebError
+// @Failure 500 {object} web.WebInternalServerError
+// @Router /api/bikes/import [post]
+func (controller *BikeController) ImportBikes(c *gin.Context) {
+	utils.UserRoleMustAdmin(c)
+
+	var query request.BikeImportQuery
+	err := c.ShouldBindQuery(&query)
+	utils.PanicIfError(err)
+
+	format := query.Format
+	if format == "" {
+		format = "json"
+		if c.ContentType() == "text/csv" {
+			format = "csv"
+		}
+	}
+
+	res, err := controller.bikeCatalogService.ImportBikes(c, format, query.DryRun)
+	utils.PanicIfError(err)
+
+	utils.ToResponseJSON(c, http.StatusOK, res, nil)
+}
+
+// ExportBikes godoc
+// @Summary Export bikes
+// @Description Stream the whole catalogue as JSON that can be imported again, or as CSV with one row per variant
+// @Tags Bikes
+// @Produce json,text/csv
+// @Param Authorization	header string true "Authorization. How to input in swagger : 'Bearer <insert_your_token_here>'"
+// @Security BearerToken
+// @Param format query string false "Export format, defaults to json" Enums(json, csv)
+// @Success 200 {array} request.CreateBikeRequest
+// @Failure 400 {object} web.WebBadRequestError
+// @Failure 500 {object} web.WebInternalServerError
+// @Router /api/bikes/export [get]
+func (controller *BikeController) ExportBikes(c *gin.Context) {
+	utils.UserRoleMustAdmin(c)
+
+	var query request.BikeExportQuery
+	err := c.ShouldBindQuery(&query)
+	utils.PanicIfError(err)
+
+	format := query.Format
+	contentType := "application/json"
+	if format == "csv" {
+		contentType = "text/csv; charset=utf-8"
+	} else {
+		format = "json"
+	}
+
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", "bikes."+format))
+
+	err = controller.bikeCatalogService.ExportBikes(c, format, c.Writer)
+	if err != nil && !c.Writer.Written() {
+		// nothing was streamed yet, so the error can still be reported as json
+		c.Writer.Header().Del("Content-Disposition")
+		c.Writer.Header().Del("Content-Type")
+		utils.PanicIfError(err)
+	}
+}
+
+// RestoreBike godoc
+// @Summary Restore a bike
+// @Description	Restore an archived bike, its category must not be archived
+// @Tags Bikes
+// @Produce json
+// @Param Authorization	header string true "Authorization. How to input in swagger : 'Bearer <insert_your_token_here>'"
+// @Security BearerToken
+// @Param id path uint true "Bike ID"
+// @Success 200 {object} web.WebSuccess[response.BikeResponse]
+// @Failure 400 {object} web.WebBadRequestError
+// @Failure 404 {object} web.WebNotFoundError
+// @Failure 409 {object} web.WebError
+// @Failure 500 {object} web.WebInternalServerError
+// @Router /api/bikes/{id}/restore [post]
+func (controller *BikeController) RestoreBike(c *gin.Context) {
+	utils.UserRoleMustAdmin(c)
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.PanicIfError(exceptions.NewCustomError(http.StatusBadRequest, "id must be an integer"))
+	}
+
+	res, err := controller.bikeService.RestoreBike(c, uint(id))
+	utils.PanicIfError(err)
+
+	utils.ToResponseJSON(c, http.StatusOK, res, nil)
+}
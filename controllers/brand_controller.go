@@ -0,0 +1,159 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gowesmart/api-gowesmart/exceptions"
+	_ "github.com/gowesmart/api-gowesmart/model/web"
+	"github.com/gowesmart/api-gowesmart/model/web/request"
+	_ "github.com/gowesmart/api-gowesmart/model/web/response"
+	"github.com/gowesmart/api-gowesmart/services"
+	"github.com/gowesmart/api-gowesmart/utils"
+)
+
+type BrandController struct {
+	brandService services.BrandService
+}
+
+func NewBrandController(brandService *services.BrandService) *BrandController {
+	return &BrandController{
+		*brandService,
+	}
+}
+
+// CreateBrand godoc
+// @Summary Create a brand
+// @Description Create a new brand. The slug defaults to one derived from the name
+// @Tags Brands
+// @Accept json
+// @Produce json
+// @Param Authorization	header string true "Authorization. How to input in swagger : 'Bearer <insert_your_token_here>'"
+// @Security BearerToken
+// @Param brand body request.CreateBrandRequest true "Brand body"
+// @Success 201 {object} web.WebSuccess[response.BrandResponse]
+// @Failure 400 {object} web.WebBadRequestError
+// @Failure 409 {object} web.WebError
+// @Failure 500 {object} web.WebInternalServerError
+// @Router /api/brands [post]
+func (controller *BrandController) CreateBrand(c *gin.Context) {
+	utils.UserRoleMustAdmin(c)
+
+	var brandReq request.CreateBrandRequest
+	err := c.ShouldBindJSON(&brandReq)
+	utils.PanicIfError(err)
+
+	res, err := controller.brandService.CreateBrand(c, &brandReq)
+	utils.PanicIfError(err)
+
+	utils.ToResponseJSON(c, http.StatusCreated, res, nil)
+}
+
+// UpdateBrand godoc
+// @Summary Update a brand
+// @Description	Update an existing brand. Renaming a brand renames it on its bikes too
+// @Tags Brands
+// @Accept json
+// @Produce json
+// @Param Authorization	header string true "Authorization. How to input in swagger : 'Bearer <insert_your_token_here>'"
+// @Security BearerToken
+// @Param id path uint true "Brand ID"
+// @Param brand body request.UpdateBrandRequest	true "Brand body"
+// @Success 200	{object} web.WebSuccess[response.BrandResponse]
+// @Failure 400	{object} web.WebBadRequestError
+// @Failure 404	{object} web.WebNotFoundError
+// @Failure 409	{object} web.WebError
+// @Failure 500	{object} web.WebInternalServerError
+// @Router /api/brands/{id} [patch]
+func (controller *BrandController) UpdateBrand(c *gin.Context) {
+	utils.UserRoleMustAdmin(c)
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.PanicIfError(exceptions.NewCustomError(http.StatusBadRequest, "id must be an integer"))
+	}
+
+	var brandReq request.UpdateBrandRequest
+	err = c.ShouldBindJSON(&brandReq)
+	utils.PanicIfError(err)
+
+	res, err := controller.brandService.UpdateBrand(c, uint(id), &brandReq)
+	utils.PanicIfError(err)
+
+	utils.ToResponseJSON(c, http.StatusOK, res, nil)
+}
+
+// DeleteBrand godoc
+// @Summary Delete a brand
+// @Description	Delete a brand that no bike refers to, archived bikes included
+// @Tags Brands
+// @Produce json
+// @Param Authorization	header string true "Authorization. How to input in swagger : 'Bearer <insert_your_token_here>'"
+// @Security BearerToken
+// @Param id path uint true	"Brand ID"
+// @Success 200	{object} web.WebSuccess[string]
+// @Failure 404	{object} web.WebNotFoundError
+// @Failure 409	{object} web.WebError
+// @Failure 500	{object} web.WebInternalServerError
+// @Router /api/brands/{id} [delete]
+func (controller *BrandController) DeleteBrand(c *gin.Context) {
+	utils.UserRoleMustAdmin(c)
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.PanicIfError(exceptions.NewCustomError(http.StatusBadRequest, "id must be an integer"))
+	}
+
+	err = controller.brandService.DeleteBrand(c, uint(id))
+	utils.PanicIfError(err)
+
+	utils.ToResponseJSON(c, http.StatusOK, "Brand deleted", nil)
+}
+
+// GetAllBrands godoc
+// @Summary Get all brands
+// @Description	Get all brands ordered by name, with the number of active bikes of each
+// @Tags Brands
+// @Produce json
+// @Param name query string false "Name contains"
+// @Param limit query int false "Limit" default(10)
+// @Param page query int false "Page" default(1)
+// @Success 200	{object} web.WebSuccess[[]response.BrandResponse]
+// @Failure 400	{object} web.WebBadRequestError
+// @Failure 500	{object} web.WebInternalServerError
+// @Router /api/brands [get]
+func (controller *BrandController) GetAllBrands(c *gin.Context) {
+	var brandQueryReq request.BrandQueryRequest
+
+	err := c.ShouldBindQuery(&brandQueryReq)
+	utils.PanicIfError(err)
+
+	res, metadata, err := controller.brandService.GetAllBrands(c, &brandQueryReq)
+	utils.PanicIfError(err)
+
+	utils.ToResponseJSON(c, http.StatusOK, res, metadata)
+}
+
+// GetBrandByID godoc
+// @Summary Get a brand by ID
+// @Description	Get a brand by ID
+// @Tags Brands
+// @Produce json
+// @Param id path uint true	"Brand ID"
+// @Success 200	{object} web.WebSuccess[response.BrandResponse]
+// @Failure 400	{object} web.WebBadRequestError
+// @Failure 404	{object} web.WebNotFoundError
+// @Failure 500	{object} web.WebInternalServerError
+// @Router /api/brands/{id} [get]
+func (controller *BrandController) GetBrandByID(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.PanicIfError(exceptions.NewCustomError(http.StatusBadRequest, "id must be an integer"))
+	}
+
+	res, err := controller.brandService.GetBrandByID(c, uint(id))
+	utils.PanicIfError(err)
+
+	utils.ToResponseJSON(c, http.StatusOK, res, nil)
+}
@@ -12,11 +12,12 @@ import (
 )
 
 type CartController struct {
-	service services.CartItemService
+	service            services.CartItemService
+	transactionService services.TransactionService
 }
 
-func NewCartController(service services.CartItemService) CartController {
-	return CartController{service: service}
+func NewCartController(service services.CartItemService, transactionService services.TransactionService) CartController {
+	return CartController{service: service, transactionService: transactionService}
 }
 
 // Create godoc
@@ -95,8 +96,42 @@ func (ctrl CartController) Delete(c *gin.Context) {
 	claims, err := utils.ExtractTokenClaims(c)
 	utils.PanicIfError(err)
 
-	err = ctrl.service.Delete(c, req.BikeID, claims.UserID)
+	err = ctrl.service.Delete(c, req.VariantID, claims.UserID)
 	utils.PanicIfError(err)
 
 	utils.ToResponseJSON(c, http.StatusOK, "Cart item successfully deleted", nil)
 }
+
+// Checkout godoc
+// @Summary Checkout the cart
+// @Description Turn the whole cart, or only the selected cart items, into a transaction and get its payment link
+// @Tags Carts
+// @Accept json
+// @Produce json
+// @Param Authorization	header string true	"Authorization. How to input in swagger : 'Bearer <insert_your_token_here>'"
+// @Security BearerToken
+// @Param Idempotency-Key header string false "Key to safely retry the request, repeats within 24 hours get the first response"
+// @Param cart body request.CartCheckoutRequest false "Cart items to checkout, leave empty to checkout the whole cart"
+// @Success 201 {object} web.WebSuccess[response.CreateTransactionResponse]
+// @Failure 400 {object} web.WebBadRequestError
+// @Failure 404 {object} web.WebNotFoundError
+// @Failure 409 {object} web.WebError
+// @Failure 422 {object} web.WebError
+// @Failure 500 {object} web.WebInternalServerError
+// @Failure 502 {object} web.WebError
+// @Router /api/carts/checkout [post]
+func (ctrl CartController) Checkout(c *gin.Context) {
+	var req request.CartCheckoutRequest
+	if c.Request.ContentLength != 0 {
+		err := c.ShouldBindJSON(&req)
+		utils.PanicIfError(err)
+	}
+
+	claims, err := utils.ExtractTokenClaims(c)
+	utils.PanicIfError(err)
+
+	res, err := ctrl.transactionService.Checkout(c, &req, int(claims.UserID))
+	utils.PanicIfError(err)
+
+	utils.ToResponseJSON(c, http.StatusCreated, res, nil)
+}
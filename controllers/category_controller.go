@@ -6,7 +6,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/gowesmart/api-gowesmart/exceptions"
-	"github.com/gowesmart/api-gowesmart/model/web"
+	_ "github.com/gowesmart/api-gowesmart/model/web"
 	"github.com/gowesmart/api-gowesmart/model/web/request"
 	_ "github.com/gowesmart/api-gowesmart/model/web/response"
 	"github.com/gowesmart/api-gowesmart/services"
@@ -25,7 +25,7 @@ func NewCategoryController(categoryService *services.CategoryService) *CategoryC
 
 // CreateCategory godoc
 // @Summary Create a category
-// @Description Create a new category
+// @Description Create a new category, optionally under a parent. The slug defaults to one derived from the name
 // @Tags Categories
 // @Accept json
 // @Produce json
@@ -34,6 +34,8 @@ func NewCategoryController(categoryService *services.CategoryService) *CategoryC
 // @Param category body request.CreateCategoryRequest true "Category body"
 // @Success 201 {object} web.WebSuccess[response.CategoryResponse]
 // @Failure 400 {object} web.WebBadRequestError
+// @Failure 404 {object} web.WebNotFoundError
+// @Failure 409 {object} web.WebError
 // @Failure 500 {object} web.WebInternalServerError
 // @Router /api/categories [post]
 func (controller *CategoryController) CreateCategory(c *gin.Context) {
@@ -51,7 +53,7 @@ func (controller *CategoryController) CreateCategory(c *gin.Context) {
 
 // UpdateCategory godoc
 // @Summary Update a category
-// @Description	Update an existing category
+// @Description	Update an existing category. Set parent_id to move it, 0 moves it to the top level
 // @Tags Categories
 // @Accept json
 // @Produce json
@@ -62,6 +64,7 @@ func (controller *CategoryController) CreateCategory(c *gin.Context) {
 // @Success 200	{object} web.WebSuccess[response.CategoryResponse]
 // @Failure 400	{object} web.WebBadRequestError
 // @Failure 404	{object} web.WebNotFoundError
+// @Failure 409	{object} web.WebError
 // @Failure 500	{object} web.WebInternalServerError
 // @Router /api/categories/{id} [patch]
 func (controller *CategoryController) UpdateCategory(c *gin.Context) {
@@ -84,8 +87,8 @@ func (controller *CategoryController) UpdateCategory(c *gin.Context) {
 }
 
 // DeleteCategory godoc
-// @Summary Delete a category
-// @Description	Delete a category by ID
+// @Summary Archive a category
+// @Description	Archive a category and its bikes. They are hidden from listings but still resolve for orders and reviews, and can be restored.
 // @Tags Categories
 // @Produce json
 // @Param Authorization	header string true "Authorization. How to input in swagger : 'Bearer <insert_your_token_here>'"
@@ -93,6 +96,7 @@ func (controller *CategoryController) UpdateCategory(c *gin.Context) {
 // @Param id path uint true	"Category ID"
 // @Success 204	{object} web.WebSuccess[response.CategoryResponse]
 // @Failure 404	{object} web.WebNotFoundError
+// @Failure 409	{object} web.WebError
 // @Failure 500	{object} web.WebInternalServerError
 // @Router /api/categories/{id} [delete]
 func (controller *CategoryController) DeleteCategory(c *gin.Context) {
@@ -106,7 +110,7 @@ func (controller *CategoryController) DeleteCategory(c *gin.Context) {
 	err = controller.categoryService.DeleteCategory(c, uint(id))
 	utils.PanicIfError(err)
 
-	utils.ToResponseJSON(c, http.StatusOK, "Category deleted", nil)
+	utils.ToResponseJSON(c, http.StatusOK, "Category archived", nil)
 }
 
 // GetAllCategories godoc
@@ -116,21 +120,42 @@ func (controller *CategoryController) DeleteCategory(c *gin.Context) {
 // @Produce json
 // @Param limit query int false "Limit" default(10)
 // @Param page query int false "Page" default(1)
+// @Param include_archived query bool false "Include archived categories, admin only"
 // @Success 200	{object} web.WebSuccess[[]response.CategoryResponse]
+// @Failure 403	{object} web.WebForbiddenError
 // @Failure 500	{object} web.WebInternalServerError
 // @Router /api/categories [get]
 func (controller *CategoryController) GetAllCategories(c *gin.Context) {
-	var pagination web.PaginationRequest
+	var categoryQueryReq request.CategoryQueryRequest
 
-	err := c.ShouldBindQuery(&pagination)
+	err := c.ShouldBindQuery(&categoryQueryReq)
 	utils.PanicIfError(err)
 
-	res, metadata, err := controller.categoryService.GetAllCategories(c, &pagination)
+	if categoryQueryReq.IncludeArchived {
+		utils.UserRoleMustAdmin(c)
+	}
+
+	res, metadata, err := controller.categoryService.GetAllCategories(c, &categoryQueryReq)
 	utils.PanicIfError(err)
 
 	utils.ToResponseJSON(c, http.StatusOK, res, metadata)
 }
 
+// GetCategoryTree godoc
+// @Summary Get the category tree
+// @Description	Get the active categories nested under their parents, with the number of active bikes in each category and in its whole subtree
+// @Tags Categories
+// @Produce json
+// @Success 200	{object} web.WebSuccess[[]response.CategoryTreeResponse]
+// @Failure 500	{object} web.WebInternalServerError
+// @Router /api/categories/tree [get]
+func (controller *CategoryController) GetCategoryTree(c *gin.Context) {
+	res, err := controller.categoryService.GetCategoryTree(c)
+	utils.PanicIfError(err)
+
+	utils.ToResponseJSON(c, http.StatusOK, res, nil)
+}
+
 // GetCategoryByID godoc
 // @Summary Get a category by ID
 // @Description	Get a category by ID
@@ -154,3 +179,30 @@ func (controller *CategoryController) GetCategoryByID(c *gin.Context) {
 
 	utils.ToResponseJSON(c, http.StatusOK, res, nil)
 }
+
+// RestoreCategory godoc
+// @Summary Restore a category
+// @Description	Restore an archived category together with the bikes that were archived with it
+// @Tags Categories
+// @Produce json
+// @Param Authorization	header string true "Authorization. How to input in swagger : 'Bearer <insert_your_token_here>'"
+// @Security BearerToken
+// @Param id path uint true	"Category ID"
+// @Success 200	{object} web.WebSuccess[response.CategoryResponse]
+// @Failure 400	{object} web.WebBadRequestError
+// @Failure 404	{object} web.WebNotFoundError
+// @Failure 500	{object} web.WebInternalServerError
+// @Router /api/categories/{id}/restore [post]
+func (controller *CategoryController) RestoreCategory(c *gin.Context) {
+	utils.UserRoleMustAdmin(c)
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.PanicIfError(exceptions.NewCustomError(http.StatusBadRequest, "id must be an integer"))
+	}
+
+	res, err := controller.categoryService.RestoreCategory(c, uint(id))
+	utils.PanicIfError(err)
+
+	utils.ToResponseJSON(c, http.StatusOK, res, nil)
+}
@@ -0,0 +1,170 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gowesmart/api-gowesmart/exceptions"
+	_ "github.com/gowesmart/api-gowesmart/model/web"
+	"github.com/gowesmart/api-gowesmart/model/web/request"
+	_ "github.com/gowesmart/api-gowesmart/model/web/response"
+	"github.com/gowesmart/api-gowesmart/services"
+	"github.com/gowesmart/api-gowesmart/utils"
+)
+
+type DiscountController struct {
+	discountService services.DiscountService
+}
+
+func NewDiscountController(discountService *services.DiscountService) *DiscountController {
+	return &DiscountController{
+		*discountService,
+	}
+}
+
+// CreateDiscount godoc
+// @Summary Create a discount
+// @Description Schedule a percentage or fixed amount discount on a bike, a category and its subcategories, or a brand. Discounts don't stack, a bike sells with the discount that lowers its price most.
+// @Tags Discounts
+// @Accept json
+// @Produce json
+// @Param Authorization	header string true "Authorization. How to input in swagger : 'Bearer <insert_your_token_here>'"
+// @Security BearerToken
+// @Param discount body request.CreateDiscountRequest true "Discount body"
+// @Success 201 {object} web.WebSuccess[response.DiscountResponse]
+// @Failure 400 {object} web.WebBadRequestError
+// @Failure 403 {object} web.WebForbiddenError
+// @Failure 404 {object} web.WebNotFoundError
+// @Failure 500 {object} web.WebInternalServerError
+// @Router /api/discounts [post]
+func (controller *DiscountController) CreateDiscount(c *gin.Context) {
+	utils.UserRoleMustAdmin(c)
+
+	var discountReq request.CreateDiscountRequest
+	err := c.ShouldBindJSON(&discountReq)
+	utils.PanicIfError(err)
+
+	res, err := controller.discountService.CreateDiscount(c, &discountReq)
+	utils.PanicIfError(err)
+
+	utils.ToResponseJSON(c, http.StatusCreated, res, nil)
+}
+
+// UpdateDiscount godoc
+// @Summary Update a discount
+// @Description	Update the name, value or schedule of a discount. Setting ends_at to now ends it.
+// @Tags Discounts
+// @Accept json
+// @Produce json
+// @Param Authorization	header string true "Authorization. How to input in swagger : 'Bearer <insert_your_token_here>'"
+// @Security BearerToken
+// @Param id path uint true "Discount ID"
+// @Param discount body request.UpdateDiscountRequest true "Discount body"
+// @Success 200	{object} web.WebSuccess[response.DiscountResponse]
+// @Failure 400	{object} web.WebBadRequestError
+// @Failure 403	{object} web.WebForbiddenError
+// @Failure 404	{object} web.WebNotFoundError
+// @Failure 500	{object} web.WebInternalServerError
+// @Router /api/discounts/{id} [patch]
+func (controller *DiscountController) UpdateDiscount(c *gin.Context) {
+	utils.UserRoleMustAdmin(c)
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.PanicIfError(exceptions.NewCustomError(http.StatusBadRequest, "id must be an integer"))
+	}
+
+	var discountReq request.UpdateDiscountRequest
+	err = c.ShouldBindJSON(&discountReq)
+	utils.PanicIfError(err)
+
+	res, err := controller.discountService.UpdateDiscount(c, uint(id), &discountReq)
+	utils.PanicIfError(err)
+
+	utils.ToResponseJSON(c, http.StatusOK, res, nil)
+}
+
+// DeleteDiscount godoc
+// @Summary Delete a discount
+// @Description	Delete a discount. Orders keep the price they were placed with.
+// @Tags Discounts
+// @Produce json
+// @Param Authorization	header string true "Authorization. How to input in swagger : 'Bearer <insert_your_token_here>'"
+// @Security BearerToken
+// @Param id path uint true	"Discount ID"
+// @Success 200	{object} web.WebSuccess[string]
+// @Failure 403	{object} web.WebForbiddenError
+// @Failure 404	{object} web.WebNotFoundError
+// @Failure 500	{object} web.WebInternalServerError
+// @Router /api/discounts/{id} [delete]
+func (controller *DiscountController) DeleteDiscount(c *gin.Context) {
+	utils.UserRoleMustAdmin(c)
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.PanicIfError(exceptions.NewCustomError(http.StatusBadRequest, "id must be an integer"))
+	}
+
+	err = controller.discountService.DeleteDiscount(c, uint(id))
+	utils.PanicIfError(err)
+
+	utils.ToResponseJSON(c, http.StatusOK, "Discount deleted", nil)
+}
+
+// GetAllDiscounts godoc
+// @Summary Get all discounts
+// @Description	Get discounts, newest first
+// @Tags Discounts
+// @Produce json
+// @Param Authorization	header string true "Authorization. How to input in swagger : 'Bearer <insert_your_token_here>'"
+// @Security BearerToken
+// @Param target_type query string false "Target type" Enums(bike, category, brand)
+// @Param target_id query int false "Target ID"
+// @Param status query string false "Status" Enums(scheduled, active, expired)
+// @Param limit query int false "Limit" default(10)
+// @Param page query int false "Page" default(1)
+// @Success 200	{object} web.WebSuccess[[]response.DiscountResponse]
+// @Failure 400	{object} web.WebBadRequestError
+// @Failure 403	{object} web.WebForbiddenError
+// @Failure 500	{object} web.WebInternalServerError
+// @Router /api/discounts [get]
+func (controller *DiscountController) GetAllDiscounts(c *gin.Context) {
+	utils.UserRoleMustAdmin(c)
+
+	var discountQueryReq request.DiscountQueryRequest
+	err := c.ShouldBindQuery(&discountQueryReq)
+	utils.PanicIfError(err)
+
+	res, metadata, err := controller.discountService.GetAllDiscounts(c, &discountQueryReq)
+	utils.PanicIfError(err)
+
+	utils.ToResponseJSON(c, http.StatusOK, res, metadata)
+}
+
+// GetDiscountByID godoc
+// @Summary Get a discount by ID
+// @Description	Get a discount by ID
+// @Tags Discounts
+// @Produce json
+// @Param Authorization	header string true "Authorization. How to input in swagger : 'Bearer <insert_your_token_here>'"
+// @Security BearerToken
+// @Param id path uint true	"Discount ID"
+// @Success 200	{object} web.WebSuccess[response.DiscountResponse]
+// @Failure 403	{object} web.WebForbiddenError
+// @Failure 404	{object} web.WebNotFoundError
+// @Failure 500	{object} web.WebInternalServerError
+// @Router /api/discounts/{id} [get]
+func (controller *DiscountController) GetDiscountByID(c *gin.Context) {
+	utils.UserRoleMustAdmin(c)
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.PanicIfError(exceptions.NewCustomError(http.StatusBadRequest, "id must be an integer"))
+	}
+
+	res, err := controller.discountService.GetDiscountByID(c, uint(id))
+	utils.PanicIfError(err)
+
+	utils.ToResponseJSON(c, http.StatusOK, res, nil)
+}
@@ -0,0 +1,78 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gowesmart/api-gowesmart/exceptions"
+	_ "github.com/gowesmart/api-gowesmart/model/web"
+	"github.com/gowesmart/api-gowesmart/model/web/request"
+	"github.com/gowesmart/api-gowesmart/services"
+	"github.com/gowesmart/api-gowesmart/utils"
+)
+
+type PaymentController struct {
+	transactionService *services.TransactionService
+}
+
+func NewPaymentController(transactionService *services.TransactionService) *PaymentController {
+	return &PaymentController{transactionService: transactionService}
+}
+
+// MidtransNotification godoc
+// @Summary Midtrans HTTP notification
+// @Description Webhook called by Midtrans whenever a payment changes status. The request is authenticated with its signature_key.
+// @Tags Payments
+// @Accept json
+// @Produce json
+// @Param notification body request.MidtransNotificationRequest true "Midtrans notification body"
+// @Success 200 {object} web.WebSuccess[string]
+// @Failure 400 {object} web.WebBadRequestError
+// @Failure 403 {object} web.WebForbiddenError
+// @Failure 404 {object} web.WebNotFoundError
+// @Failure 500 {object} web.WebInternalServerError
+// @Router /api/payments/midtrans/notification [post]
+func (controller *PaymentController) MidtransNotification(c *gin.Context) {
+	var notificationReq request.MidtransNotificationRequest
+	err := c.ShouldBindJSON(&notificationReq)
+	utils.PanicIfError(err)
+
+	err = controller.transactionService.HandleMidtransNotification(c, &notificationReq)
+	utils.PanicIfError(err)
+
+	utils.ToResponseJSON(c, http.StatusOK, "notification processed", nil)
+}
+
+// FakePaymentController stands in for the payment page of the fake payment gateway during local development.
+type FakePaymentController struct {
+	gateway            *utils.FakePaymentGateway
+	transactionService *services.TransactionService
+}
+
+func NewFakePaymentController(gateway *utils.FakePaymentGateway, transactionService *services.TransactionService) *FakePaymentController {
+	return &FakePaymentController{gateway: gateway, transactionService: transactionService}
+}
+
+// Pay godoc
+// @Summary Pay with the fake payment gateway
+// @Description Payment link of the fake payment gateway, only served when PAYMENT_GATEWAY is fake. Opening it settles the charge, or moves it to the given Midtrans transaction_status, and handles the notification Midtrans would send.
+// @Tags Payments
+// @Produce json
+// @Param id path string true "Order ID"
+// @Param transaction_status query string false "Midtrans transaction_status, settlement by default"
+// @Success 200 {object} web.WebSuccess[string]
+// @Failure 400 {object} web.WebBadRequestError
+// @Failure 404 {object} web.WebNotFoundError
+// @Failure 500 {object} web.WebInternalServerError
+// @Router /fake-payments/{id} [get]
+func (controller *FakePaymentController) Pay(c *gin.Context) {
+	notificationReq, err := controller.gateway.Notify(c.Param("id"), c.DefaultQuery("transaction_status", "settlement"))
+	if err != nil {
+		utils.PanicIfError(exceptions.NewCustomError(http.StatusNotFound, err.Error()))
+	}
+
+	err = controller.transactionService.HandleMidtransNotification(c, notificationReq)
+	utils.PanicIfError(err)
+
+	utils.ToResponseJSON(c, http.StatusOK, "payment "+notificationReq.TransactionStatus, nil)
+}
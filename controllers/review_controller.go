@@ -8,7 +8,7 @@ import (
 	"github.com/gowesmart/api-gowesmart/exceptions"
 	"github.com/gowesmart/api-gowesmart/model/web"
 	"github.com/gowesmart/api-gowesmart/model/web/request"
-	_ "github.com/gowesmart/api-gowesmart/model/web/response"
+	"github.com/gowesmart/api-gowesmart/model/web/response"
 	"github.com/gowesmart/api-gowesmart/services"
 	"github.com/gowesmart/api-gowesmart/utils"
 )
@@ -186,3 +186,25 @@ func (controller *ReviewController) GetReviewByOrderID(c *gin.Context) {
 
 	utils.ToResponseJSON(c, http.StatusOK, res, nil)
 }
+
+// RecomputeBikeRatings godoc
+// @Summary Recompute bike ratings
+// @Description Rebuild the average rating and rating histogram of every bike from its reviews, returning how many bikes were corrected
+// @Tags Reviews
+// @Produce json
+// @Param Authorization	header string true "Authorization. How to input in swagger : 'Bearer <insert_your_token_here>'"
+// @Security BearerToken
+// @Success 200 {object} web.WebSuccess[response.RecomputeBikeRatingsResponse]
+// @Failure 403 {object} web.WebForbiddenError
+// @Failure 500 {object} web.WebInternalServerError
+// @Router /api/reviews/recompute-ratings [post]
+func (controller *ReviewController) RecomputeBikeRatings(c *gin.Context) {
+	utils.UserRoleMustAdmin(c)
+
+	db, logger := utils.GetDBAndLogger(c)
+
+	corrected, err := controller.reviewService.RecomputeBikeRatings(db, logger)
+	utils.PanicIfError(err)
+
+	utils.ToResponseJSON(c, http.StatusOK, response.RecomputeBikeRatingsResponse{Corrected: corrected}, nil)
+}
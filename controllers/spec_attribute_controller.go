@@ -0,0 +1,134 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gowesmart/api-gowesmart/exceptions"
+	_ "github.com/gowesmart/api-gowesmart/model/web"
+	"github.com/gowesmart/api-gowesmart/model/web/request"
+	_ "github.com/gowesmart/api-gowesmart/model/web/response"
+	"github.com/gowesmart/api-gowesmart/services"
+	"github.com/gowesmart/api-gowesmart/utils"
+)
+
+type SpecAttributeController struct {
+	specAttributeService services.SpecAttributeService
+}
+
+func NewSpecAttributeController(specAttributeService *services.SpecAttributeService) *SpecAttributeController {
+	return &SpecAttributeController{
+		*specAttributeService,
+	}
+}
+
+// CreateSpecAttribute godoc
+// @Summary Create a spec attribute
+// @Description Define a technical spec of the bikes in a category and its subcategories. Enum attributes list their options, number attributes may have a unit.
+// @Tags Spec Attributes
+// @Accept json
+// @Produce json
+// @Param Authorization	header string true "Authorization. How to input in swagger : 'Bearer <insert_your_token_here>'"
+// @Security BearerToken
+// @Param attribute body request.CreateSpecAttributeRequest true "Spec attribute body"
+// @Success 201 {object} web.WebSuccess[response.SpecAttributeResponse]
+// @Failure 400 {object} web.WebBadRequestError
+// @Failure 404 {object} web.WebNotFoundError
+// @Failure 409 {object} web.WebError
+// @Failure 500 {object} web.WebInternalServerError
+// @Router /api/spec-attributes [post]
+func (controller *SpecAttributeController) CreateSpecAttribute(c *gin.Context) {
+	utils.UserRoleMustAdmin(c)
+
+	var attributeReq request.CreateSpecAttributeRequest
+	err := c.ShouldBindJSON(&attributeReq)
+	utils.PanicIfError(err)
+
+	res, err := controller.specAttributeService.CreateSpecAttribute(c, &attributeReq)
+	utils.PanicIfError(err)
+
+	utils.ToResponseJSON(c, http.StatusCreated, res, nil)
+}
+
+// UpdateSpecAttribute godoc
+// @Summary Update a spec attribute
+// @Description	Update the name, unit or enum options of a spec attribute. Options still used by bikes can't be removed.
+// @Tags Spec Attributes
+// @Accept json
+// @Produce json
+// @Param Authorization	header string true "Authorization. How to input in swagger : 'Bearer <insert_your_token_here>'"
+// @Security BearerToken
+// @Param id path uint true "Spec attribute ID"
+// @Param attribute body request.UpdateSpecAttributeRequest true "Spec attribute body"
+// @Success 200	{object} web.WebSuccess[response.SpecAttributeResponse]
+// @Failure 400	{object} web.WebBadRequestError
+// @Failure 404	{object} web.WebNotFoundError
+// @Failure 409	{object} web.WebError
+// @Failure 500	{object} web.WebInternalServerError
+// @Router /api/spec-attributes/{id} [patch]
+func (controller *SpecAttributeController) UpdateSpecAttribute(c *gin.Context) {
+	utils.UserRoleMustAdmin(c)
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.PanicIfError(exceptions.NewCustomError(http.StatusBadRequest, "id must be an integer"))
+	}
+
+	var attributeReq request.UpdateSpecAttributeRequest
+	err = c.ShouldBindJSON(&attributeReq)
+	utils.PanicIfError(err)
+
+	res, err := controller.specAttributeService.UpdateSpecAttribute(c, uint(id), &attributeReq)
+	utils.PanicIfError(err)
+
+	utils.ToResponseJSON(c, http.StatusOK, res, nil)
+}
+
+// DeleteSpecAttribute godoc
+// @Summary Delete a spec attribute
+// @Description	Delete a spec attribute together with the values bikes have for it
+// @Tags Spec Attributes
+// @Produce json
+// @Param Authorization	header string true "Authorization. How to input in swagger : 'Bearer <insert_your_token_here>'"
+// @Security BearerToken
+// @Param id path uint true	"Spec attribute ID"
+// @Success 200	{object} web.WebSuccess[string]
+// @Failure 404	{object} web.WebNotFoundError
+// @Failure 500	{object} web.WebInternalServerError
+// @Router /api/spec-attributes/{id} [delete]
+func (controller *SpecAttributeController) DeleteSpecAttribute(c *gin.Context) {
+	utils.UserRoleMustAdmin(c)
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.PanicIfError(exceptions.NewCustomError(http.StatusBadRequest, "id must be an integer"))
+	}
+
+	err = controller.specAttributeService.DeleteSpecAttribute(c, uint(id))
+	utils.PanicIfError(err)
+
+	utils.ToResponseJSON(c, http.StatusOK, "Spec attribute deleted", nil)
+}
+
+// GetSpecAttributes godoc
+// @Summary Get spec attributes
+// @Description	Get the spec attributes that apply to a category, those of its parent categories included, or all of them
+// @Tags Spec Attributes
+// @Produce json
+// @Param category_id query int false "Category ID"
+// @Success 200	{object} web.WebSuccess[[]response.SpecAttributeResponse]
+// @Failure 400	{object} web.WebBadRequestError
+// @Failure 500	{object} web.WebInternalServerError
+// @Router /api/spec-attributes [get]
+func (controller *SpecAttributeController) GetSpecAttributes(c *gin.Context) {
+	var attributeQueryReq request.SpecAttributeQueryRequest
+
+	err := c.ShouldBindQuery(&attributeQueryReq)
+	utils.PanicIfError(err)
+
+	res, err := controller.specAttributeService.GetSpecAttributes(c, &attributeQueryReq)
+	utils.PanicIfError(err)
+
+	utils.ToResponseJSON(c, http.StatusOK, res, nil)
+}
@@ -0,0 +1,84 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gowesmart/api-gowesmart/exceptions"
+	_ "github.com/gowesmart/api-gowesmart/model/web"
+	"github.com/gowesmart/api-gowesmart/model/web/request"
+	_ "github.com/gowesmart/api-gowesmart/model/web/response"
+	"github.com/gowesmart/api-gowesmart/services"
+	"github.com/gowesmart/api-gowesmart/utils"
+)
+
+type StockAlertController struct {
+	stockAlertService services.StockAlertService
+}
+
+func NewStockAlertController(stockAlertService *services.StockAlertService) *StockAlertController {
+	return &StockAlertController{
+		*stockAlertService,
+	}
+}
+
+// GetStockAlerts godoc
+// @Summary Get stock alerts
+// @Description Get the alerts raised when bike variants fell to their low stock threshold or ran out, newest first
+// @Tags Stock Alerts
+// @Produce json
+// @Param Authorization	header string true "Authorization. How to input in swagger : 'Bearer <insert_your_token_here>'"
+// @Security BearerToken
+// @Param bike_id query uint false "Bike ID"
+// @Param type query string false "Alert type" Enums(low_stock, out_of_stock)
+// @Param status query string false "Alert status" Enums(open, acknowledged, resolved)
+// @Param limit query int false "Limit" default(10)
+// @Param page query int false "Page" default(1)
+// @Success 200 {object} web.WebSuccess[[]response.StockAlertResponse]
+// @Failure 400 {object} web.WebBadRequestError
+// @Failure 403 {object} web.WebForbiddenError
+// @Failure 500 {object} web.WebInternalServerError
+// @Router /api/stock-alerts [get]
+func (controller *StockAlertController) GetStockAlerts(c *gin.Context) {
+	utils.UserRoleMustAdmin(c)
+
+	var alertQueryReq request.StockAlertQueryRequest
+	err := c.ShouldBindQuery(&alertQueryReq)
+	utils.PanicIfError(err)
+
+	res, metadata, err := controller.stockAlertService.GetStockAlerts(c, &alertQueryReq)
+	utils.PanicIfError(err)
+
+	utils.ToResponseJSON(c, http.StatusOK, res, metadata)
+}
+
+// AcknowledgeStockAlert godoc
+// @Summary Acknowledge a stock alert
+// @Description Mark a stock alert as seen, it resolves on its own once the variant is restocked
+// @Tags Stock Alerts
+// @Produce json
+// @Param Authorization	header string true "Authorization. How to input in swagger : 'Bearer <insert_your_token_here>'"
+// @Security BearerToken
+// @Param id path uint true "Stock alert ID"
+// @Success 200 {object} web.WebSuccess[response.StockAlertResponse]
+// @Failure 400 {object} web.WebBadRequestError
+// @Failure 404 {object} web.WebNotFoundError
+// @Failure 500 {object} web.WebInternalServerError
+// @Router /api/stock-alerts/{id}/acknowledge [patch]
+func (controller *StockAlertController) AcknowledgeStockAlert(c *gin.Context) {
+	utils.UserRoleMustAdmin(c)
+
+	claims, err := utils.ExtractTokenClaims(c)
+	utils.PanicIfError(err)
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.PanicIfError(exceptions.NewCustomError(http.StatusBadRequest, "id must be an integer"))
+	}
+
+	res, err := controller.stockAlertService.AcknowledgeStockAlert(c, uint(id), claims.UserID)
+	utils.PanicIfError(err)
+
+	utils.ToResponseJSON(c, http.StatusOK, res, nil)
+}
@@ -1,6 +1,7 @@
 package controllers
 
 import (
+	"fmt"
 	"net/http"
 	"strconv"
 
@@ -50,26 +51,61 @@ func (t TransactionController) GetAll(c *gin.Context) {
 
 // GetById godoc
 // @Summary Get transaction by ID
-// @Description Get transaction by ID
+// @Description Get transaction by ID, only the buyer or an admin can access.
 // @Tags Transactions
 // @Produce json
+// @Param Authorization	header string true	"Authorization. How to input in swagger : 'Bearer <insert_your_token_here>'"
+// @Security BearerToken
 // @Param id path int true "Transaction ID"
 // @Success 200 {object} web.WebSuccess[response.TransactionResponse]
 // @Failure 400 {object} web.WebBadRequestError
+// @Failure 404 {object} web.WebNotFoundError
 // @Failure 500 {object} web.WebInternalServerError
 // @Router /api/transactions/{id} [get]
 func (t TransactionController) GetById(c *gin.Context) {
+	claims, err := utils.ExtractTokenClaims(c)
+	utils.PanicIfError(err)
+
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
 		utils.PanicIfError(exceptions.NewCustomError(http.StatusBadRequest, "id must be an integer"))
 	}
 
-	res, err := t.service.GetById(c, id)
+	res, err := t.service.GetById(c, id, claims.UserID, claims.IsAdmin())
 	utils.PanicIfError(err)
 
 	utils.ToResponseJSON(c, http.StatusOK, res, nil)
 }
 
+// Invoice godoc
+// @Summary Download a transaction invoice
+// @Description Download the PDF invoice of a paid transaction, only the buyer or an admin can access.
+// @Tags Transactions
+// @Produce application/pdf
+// @Param Authorization	header string true	"Authorization. How to input in swagger : 'Bearer <insert_your_token_here>'"
+// @Security BearerToken
+// @Param id path int true "Transaction ID"
+// @Success 200 {file} file
+// @Failure 400 {object} web.WebBadRequestError
+// @Failure 404 {object} web.WebNotFoundError
+// @Failure 500 {object} web.WebInternalServerError
+// @Router /api/transactions/{id}/invoice.pdf [get]
+func (t TransactionController) Invoice(c *gin.Context) {
+	claims, err := utils.ExtractTokenClaims(c)
+	utils.PanicIfError(err)
+
+	transactionID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.PanicIfError(exceptions.NewCustomError(http.StatusBadRequest, "id must be an integer"))
+	}
+
+	file, filename, err := t.service.GetInvoice(c, transactionID, claims.UserID, claims.IsAdmin())
+	utils.PanicIfError(err)
+
+	c.Header("Content-Disposition", fmt.Sprintf("inline; filename=%q", filename))
+	c.Data(http.StatusOK, "application/pdf", file)
+}
+
 // Create godoc
 // @Summary Create a new transaction
 // @Description Create a new transaction
@@ -78,10 +114,14 @@ func (t TransactionController) GetById(c *gin.Context) {
 // @Produce json
 // @Param Authorization	header string true	"Authorization. How to input in swagger : 'Bearer <insert_your_token_here>'"
 // @Security BearerToken
+// @Param Idempotency-Key header string false "Key to safely retry the request, repeats within 24 hours get the first response"
 // @Param payload body []request.TransactionCreate true "Transaction payload"
 // @Success 200 {object} web.WebSuccess[response.CreateTransactionResponse]
 // @Failure 400 {object} web.WebBadRequestError
+// @Failure 409 {object} web.WebError
+// @Failure 422 {object} web.WebError
 // @Failure 500 {object} web.WebInternalServerError
+// @Failure 502 {object} web.WebError
 // @Router /api/transactions [post]
 func (t TransactionController) Create(c *gin.Context) {
 	claims, err := utils.ExtractTokenClaims(c)
@@ -92,16 +132,14 @@ func (t TransactionController) Create(c *gin.Context) {
 	utils.PanicIfError(err)
 
 	data, err := t.service.Create(c, payload, int(claims.UserID))
-	if err != nil {
-		utils.PanicIfError(exceptions.NewCustomError(500, err.Error()))
-	}
+	utils.PanicIfError(err)
 
 	utils.ToResponseJSON(c, http.StatusOK, data, nil)
 }
 
 // Update godoc
 // @Summary Update a transaction
-// @Description Update a transaction
+// @Description Update the orders of a pending transaction, the total can't change once its payment was created
 // @Tags Transactions
 // @Accept json
 // @Produce json
@@ -111,6 +149,7 @@ func (t TransactionController) Create(c *gin.Context) {
 // @Param payload body []request.TransactionUpdate true "Transaction update payload"
 // @Success 200 {object} web.WebSuccess[string]
 // @Failure 400 {object} web.WebBadRequestError
+// @Failure 409 {object} web.WebError
 // @Failure 500 {object} web.WebInternalServerError
 // @Router /api/transactions/{id} [patch]
 func (t TransactionController) Update(c *gin.Context) {
@@ -134,7 +173,7 @@ func (t TransactionController) Update(c *gin.Context) {
 
 // Delete godoc
 // @Summary Delete a transaction
-// @Description Delete a transaction
+// @Description Delete a pending, cancelled or expired transaction, paid transactions can't be deleted
 // @Tags Transactions
 // @Produce json
 // @Param Authorization	header string true	"Authorization. How to input in swagger : 'Bearer <insert_your_token_here>'"
@@ -160,18 +199,23 @@ func (t TransactionController) Delete(c *gin.Context) {
 }
 
 // Pay godoc
-// @Summary Pay for a transaction
-// @Description Pay for a transaction
+// @Summary Mark a transaction as paid
+// @Description Manually mark a pending transaction as paid, only admin can access. Buyers' transactions are settled through the Midtrans notification.
 // @Tags Transactions
 // @Produce json
 // @Param Authorization	header string true	"Authorization. How to input in swagger : 'Bearer <insert_your_token_here>'"
 // @Security BearerToken
+// @Param Idempotency-Key header string false "Key to safely retry the request, repeats within 24 hours get the first response"
 // @Param id path int true "Transaction ID"
 // @Success 200 {object} web.WebSuccess[string]
 // @Failure 400 {object} web.WebBadRequestError
+// @Failure 403 {object} web.WebForbiddenError
+// @Failure 422 {object} web.WebError
 // @Failure 500 {object} web.WebInternalServerError
 // @Router /api/transactions/payment/{id} [patch]
 func (t TransactionController) Pay(c *gin.Context) {
+	utils.UserRoleMustAdmin(c)
+
 	claims, err := utils.ExtractTokenClaims(c)
 	utils.PanicIfError(err)
 
@@ -180,8 +224,85 @@ func (t TransactionController) Pay(c *gin.Context) {
 		utils.PanicIfError(exceptions.NewCustomError(http.StatusBadRequest, "id must be an integer"))
 	}
 
-	err = t.service.Pay(c, transactionID, int(claims.UserID))
+	err = t.service.Pay(c, transactionID, claims.UserID)
 	utils.PanicIfError(err)
 
 	utils.ToResponseJSON(c, http.StatusOK, "payment success", nil)
 }
+
+// UpdateStatus godoc
+// @Summary Update a transaction status
+// @Description Move a transaction along its lifecycle (processing, shipped, delivered, completed) or cancel it, only admin can access.
+// @Tags Transactions
+// @Accept json
+// @Produce json
+// @Param Authorization	header string true	"Authorization. How to input in swagger : 'Bearer <insert_your_token_here>'"
+// @Security BearerToken
+// @Param id path int true "Transaction ID"
+// @Param payload body request.TransactionStatusUpdate true "Transaction status payload"
+// @Success 200 {object} web.WebSuccess[string]
+// @Failure 400 {object} web.WebBadRequestError
+// @Failure 403 {object} web.WebForbiddenError
+// @Failure 404 {object} web.WebNotFoundError
+// @Failure 500 {object} web.WebInternalServerError
+// @Router /api/transactions/{id}/status [patch]
+func (t TransactionController) UpdateStatus(c *gin.Context) {
+	utils.UserRoleMustAdmin(c)
+
+	claims, err := utils.ExtractTokenClaims(c)
+	utils.PanicIfError(err)
+
+	transactionID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.PanicIfError(exceptions.NewCustomError(http.StatusBadRequest, "id must be an integer"))
+	}
+
+	var payload request.TransactionStatusUpdate
+	err = c.ShouldBindJSON(&payload)
+	utils.PanicIfError(err)
+
+	err = t.service.UpdateStatus(c, transactionID, &payload, claims.UserID)
+	utils.PanicIfError(err)
+
+	utils.ToResponseJSON(c, http.StatusOK, "status successfully updated", nil)
+}
+
+// Refund godoc
+// @Summary Refund a transaction
+// @Description Refund a paid transaction through the payment gateway, either entirely or only some of its orders, only admin can access.
+// @Tags Transactions
+// @Accept json
+// @Produce json
+// @Param Authorization	header string true	"Authorization. How to input in swagger : 'Bearer <insert_your_token_here>'"
+// @Security BearerToken
+// @Param Idempotency-Key header string false "Key to safely retry the request, repeats within 24 hours get the first response"
+// @Param id path int true "Transaction ID"
+// @Param payload body request.RefundCreate true "Refund payload, leave items empty to refund the whole transaction"
+// @Success 201 {object} web.WebSuccess[response.RefundResponse]
+// @Failure 400 {object} web.WebBadRequestError
+// @Failure 403 {object} web.WebForbiddenError
+// @Failure 404 {object} web.WebNotFoundError
+// @Failure 422 {object} web.WebError
+// @Failure 500 {object} web.WebInternalServerError
+// @Failure 502 {object} web.WebError
+// @Router /api/transactions/{id}/refunds [post]
+func (t TransactionController) Refund(c *gin.Context) {
+	utils.UserRoleMustAdmin(c)
+
+	claims, err := utils.ExtractTokenClaims(c)
+	utils.PanicIfError(err)
+
+	transactionID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.PanicIfError(exceptions.NewCustomError(http.StatusBadRequest, "id must be an integer"))
+	}
+
+	var payload request.RefundCreate
+	err = c.ShouldBindJSON(&payload)
+	utils.PanicIfError(err)
+
+	res, err := t.service.Refund(c, transactionID, &payload, claims.UserID)
+	utils.PanicIfError(err)
+
+	utils.ToResponseJSON(c, http.StatusCreated, res, nil)
+}
@@ -213,7 +213,7 @@ const docTemplate = `{
         },
         "/api/bikes": {
             "get": {
-                "description": "Get all bikes",
+                "description": "Search bikes. The metadata includes facet counts per brand, category and price bucket, each facet ignoring its own filter.",
                 "produces": [
                     "application/json"
                 ],
@@ -244,19 +244,45 @@ const docTemplate = `{
                     },
                     {
                         "type": "integer",
-                        "description": "Brand ID",
+                        "description": "Category ID, subcategories included",
                         "name": "category_id",
                         "in": "query"
                     },
+                    {
+                        "type": "string",
+                        "description": "Category slug, subcategories included",
+                        "name": "category",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Brand slugs or names, repeated or comma separated",
+                        "name": "brand",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "integer"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Brand IDs",
+                        "name": "brand_id",
+                        "in": "query"
+                    },
                     {
                         "type": "integer",
-                        "description": "Minimum Price",
+                        "description": "Minimum sale price, discounts applied",
                         "name": "min_price",
                         "in": "query"
                     },
                     {
                         "type": "integer",
-                        "description": "Maximum Price",
+                        "description": "Maximum sale price, discounts applied",
                         "name": "max_price",
                         "in": "query"
                     },
@@ -271,6 +297,43 @@ const docTemplate = `{
                         "description": "Maximum Year",
                         "name": "max_year",
                         "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Minimum average rating",
+                        "name": "min_rating",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only bikes that can be bought",
+                        "name": "in_stock",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "price_asc",
+                            "price_desc",
+                            "newest",
+                            "rating",
+                            "popularity"
+                        ],
+                        "type": "string",
+                        "description": "Sort order, defaults to id",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include archived bikes, admin only",
+                        "name": "include_archived",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Spec value, e.g. spec[wheel_size]=29. Enum values may be comma separated, numbers also take ranges with spec[weight_min] and spec[weight_max]",
+                        "name": "spec[key]",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -280,6 +343,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/web.WebSuccess-array_response_BikeResponse"
                         }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebBadRequestError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebForbiddenError"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -345,22 +420,22 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/bikes/{id}": {
+        "/api/bikes/compare": {
             "get": {
-                "description": "Get a bike by ID",
+                "description": "Line up 2 to 4 bikes attribute by attribute: price, year, brand, category, average rating, stock status and specs, marking the attributes whose values differ",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Bikes"
                 ],
-                "summary": "Get a bike by ID",
+                "summary": "Compare bikes",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Bike ID",
-                        "name": "id",
-                        "in": "path",
+                        "type": "string",
+                        "description": "Comma separated bike IDs, e.g. 1,2,3",
+                        "name": "ids",
+                        "in": "query",
                         "required": true
                     }
                 ],
@@ -368,13 +443,13 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/web.WebSuccess-response_BikeResponse"
+                            "$ref": "#/definitions/web.WebSuccess-response_BikeComparisonResponse"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/web.WebNotFoundError"
+                            "$ref": "#/definitions/web.WebBadRequestError"
                         }
                     },
                     "500": {
@@ -384,21 +459,24 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/api/bikes/export": {
+            "get": {
                 "security": [
                     {
                         "BearerToken": []
                     }
                 ],
-                "description": "Delete a bike by ID",
+                "description": "Stream the whole catalogue as JSON that can be imported again, or as CSV with one row per variant",
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "text/csv"
                 ],
                 "tags": [
                     "Bikes"
                 ],
-                "summary": "Delete a bike",
+                "summary": "Export bikes",
                 "parameters": [
                     {
                         "type": "string",
@@ -408,24 +486,30 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "Bike ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "enum": [
+                            "json",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "description": "Export format, defaults to json",
+                        "name": "format",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/web.WebSuccess-response_BikeResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/request.CreateBikeRequest"
+                            }
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/web.WebNotFoundError"
+                            "$ref": "#/definitions/web.WebBadRequestError"
                         }
                     },
                     "500": {
@@ -435,16 +519,19 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "patch": {
+            }
+        },
+        "/api/bikes/import": {
+            "post": {
                 "security": [
                     {
                         "BearerToken": []
                     }
                 ],
-                "description": "Update an existing bike",
+                "description": "Create or update bikes in bulk from a JSON array in the shape of database/bike.json, or from a CSV with the columns name, brand, category_id, description, year, price, image_url, stock, is_available, sku, frame_size, color and variant_price (one row per variant). Bikes are matched by SKU, then by name. Brands must exist and are matched by name regardless of case and spacing. Rows are validated like creating a bike; nothing is imported if any row is invalid. With dry_run the per-row report is returned without importing anything.",
                 "consumes": [
-                    "application/json"
+                    "application/json",
+                    "text/csv"
                 ],
                 "produces": [
                     "application/json"
@@ -452,7 +539,7 @@ const docTemplate = `{
                 "tags": [
                     "Bikes"
                 ],
-                "summary": "Update a bike",
+                "summary": "Import bikes",
                 "parameters": [
                     {
                         "type": "string",
@@ -462,19 +549,31 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "Bike ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "enum": [
+                            "json",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "description": "Body format, defaults to csv for text/csv bodies and json otherwise",
+                        "name": "format",
+                        "in": "query"
                     },
                     {
-                        "description": "Bike body",
-                        "name": "bike",
+                        "type": "boolean",
+                        "description": "Only report what would be imported",
+                        "name": "dry_run",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Bikes",
+                        "name": "bikes",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/request.UpdateBikeRequest"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/request.BikeImportRow"
+                            }
                         }
                     }
                 ],
@@ -482,7 +581,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/web.WebSuccess-response_BikeResponse"
+                            "$ref": "#/definitions/web.WebSuccess-response_BikeImportResponse"
                         }
                     },
                     "400": {
@@ -491,45 +590,10 @@ const docTemplate = `{
                             "$ref": "#/definitions/web.WebBadRequestError"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/web.WebNotFoundError"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/web.WebInternalServerError"
-                        }
-                    }
-                }
-            }
-        },
-        "/api/bikes/{id}/reviews": {
-            "get": {
-                "description": "Get reviews by bike id",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Bikes"
-                ],
-                "summary": "Get reviews by bike id",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Bike ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
+                    "413": {
+                        "description": "Request Entity Too Large",
                         "schema": {
-                            "$ref": "#/definitions/web.WebSuccess-array_response_ReviewResponse"
+                            "$ref": "#/definitions/web.WebError"
                         }
                     },
                     "500": {
@@ -541,24 +605,21 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/carts": {
+        "/api/bikes/related/recompute": {
             "post": {
                 "security": [
                     {
                         "BearerToken": []
                     }
                 ],
-                "description": "Create a new cart item",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Rebuild the bikes bought together from the order history right away instead of waiting for the worker, returning how many relations were stored",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Carts"
+                    "Bikes"
                 ],
-                "summary": "Create a new cart item",
+                "summary": "Recompute related bikes",
                 "parameters": [
                     {
                         "type": "string",
@@ -566,28 +627,19 @@ const docTemplate = `{
                         "name": "Authorization",
                         "in": "header",
                         "required": true
-                    },
-                    {
-                        "description": "Cart Item Create",
-                        "name": "cart",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/request.CartItemCreateRequest"
-                        }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/web.WebSuccess-response_CartResponse"
+                            "$ref": "#/definitions/web.WebSuccess-response_RecomputeRelatedBikesResponse"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
-                            "$ref": "#/definitions/web.WebBadRequestError"
+                            "$ref": "#/definitions/web.WebForbiddenError"
                         }
                     },
                     "500": {
@@ -597,24 +649,23 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/api/bikes/stock/reconcile": {
+            "post": {
                 "security": [
                     {
                         "BearerToken": []
                     }
                 ],
-                "description": "Delete a cart",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Reset every bike variant whose stock doesn't match the sum of its stock movements to the ledger, and return the variants that were corrected",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Carts"
+                    "Bikes"
                 ],
-                "summary": "Delete a cart",
+                "summary": "Reconcile stock with the inventory ledger",
                 "parameters": [
                     {
                         "type": "string",
@@ -622,31 +673,19 @@ const docTemplate = `{
                         "name": "Authorization",
                         "in": "header",
                         "required": true
-                    },
-                    {
-                        "description": "Cart Update",
-                        "name": "cart",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/request.CartItemDeleteRequest"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/web.WebSuccess-string"
+                            "$ref": "#/definitions/web.WebSuccess-array_response_StockDiscrepancyResponse"
                         }
                     },
-                    "204": {
-                        "description": "No Content"
-                    },
-                    "400": {
-                        "description": "Bad Request",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
-                            "$ref": "#/definitions/web.WebBadRequestError"
+                            "$ref": "#/definitions/web.WebForbiddenError"
                         }
                     },
                     "500": {
@@ -656,47 +695,39 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "patch": {
-                "security": [
-                    {
-                        "BearerToken": []
-                    }
-                ],
-                "description": "Update a cart",
-                "consumes": [
-                    "application/json"
-                ],
+            }
+        },
+        "/api/bikes/suggest": {
+            "get": {
+                "description": "Autocomplete a search with the bikes, brands and categories whose names look like what was typed, tolerating partial words and typos. Highlights mark the matched characters of each suggestion",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Carts"
+                    "Bikes"
                 ],
-                "summary": "Update a cart",
+                "summary": "Suggest bikes",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Authorization. How to input in swagger : 'Bearer \u003cinsert_your_token_here\u003e'",
-                        "name": "Authorization",
-                        "in": "header",
+                        "description": "What was typed so far, at least 2 characters",
+                        "name": "q",
+                        "in": "query",
                         "required": true
                     },
                     {
-                        "description": "Cart Item Update",
-                        "name": "cart",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/request.CartItemUpdateRequest"
-                        }
+                        "type": "integer",
+                        "default": 8,
+                        "description": "Limit, at most 20",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/web.WebSuccess-response_CartResponse"
+                            "$ref": "#/definitions/web.WebSuccess-array_response_BikeSuggestionResponse"
                         }
                     },
                     "400": {
@@ -714,37 +745,42 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/categories": {
+        "/api/bikes/{id}": {
             "get": {
-                "description": "Get all categories",
+                "description": "Get a bike by ID. Admins also get its price history and the discounts that target it.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Categories"
+                    "Bikes"
                 ],
-                "summary": "Get all categories",
+                "summary": "Get a bike by ID",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "default": 10,
-                        "description": "Limit",
-                        "name": "limit",
-                        "in": "query"
+                        "type": "string",
+                        "description": "Authorization, only needed for the admin details. How to input in swagger : 'Bearer \u003cinsert_your_token_here\u003e'",
+                        "name": "Authorization",
+                        "in": "header"
                     },
                     {
                         "type": "integer",
-                        "default": 1,
-                        "description": "Page",
-                        "name": "page",
-                        "in": "query"
+                        "description": "Bike ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/web.WebSuccess-array_response_CategoryResponse"
+                            "$ref": "#/definitions/web.WebSuccess-response_BikeResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebNotFoundError"
                         }
                     },
                     "500": {
@@ -755,23 +791,74 @@ const docTemplate = `{
                     }
                 }
             },
-            "post": {
+            "delete": {
                 "security": [
                     {
                         "BearerToken": []
                     }
                 ],
-                "description": "Create a new category",
-                "consumes": [
+                "description": "Archive a bike by ID. It is hidden from listings and removed from carts, but still resolves for orders and reviews and can be restored.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Bikes"
+                ],
+                "summary": "Archive a bike",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authorization. How to input in swagger : 'Bearer \u003cinsert_your_token_here\u003e'",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Bike ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebSuccess-response_BikeResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebNotFoundError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebInternalServerError"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerToken": []
+                    }
+                ],
+                "description": "Update an existing bike",
+                "consumes": [
                     "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Categories"
+                    "Bikes"
                 ],
-                "summary": "Create a category",
+                "summary": "Update a bike",
                 "parameters": [
                     {
                         "type": "string",
@@ -781,20 +868,27 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Category body",
-                        "name": "category",
+                        "type": "integer",
+                        "description": "Bike ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Bike body",
+                        "name": "bike",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/request.CreateCategoryRequest"
+                            "$ref": "#/definitions/request.UpdateBikeRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/web.WebSuccess-response_CategoryResponse"
+                            "$ref": "#/definitions/web.WebSuccess-response_BikeResponse"
                         }
                     },
                     "400": {
@@ -803,6 +897,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/web.WebBadRequestError"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebNotFoundError"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -812,20 +912,20 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/categories/{id}": {
+        "/api/bikes/{id}/images": {
             "get": {
-                "description": "Get a category by ID",
+                "description": "Get the image gallery of a bike in display order",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Categories"
+                    "Bikes"
                 ],
-                "summary": "Get a category by ID",
+                "summary": "Get bike images",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Category ID",
+                        "description": "Bike ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -835,13 +935,13 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/web.WebSuccess-response_CategoryResponse"
+                            "$ref": "#/definitions/web.WebSuccess-array_response_BikeImageResponse"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/web.WebNotFoundError"
+                            "$ref": "#/definitions/web.WebBadRequestError"
                         }
                     },
                     "500": {
@@ -852,20 +952,23 @@ const docTemplate = `{
                     }
                 }
             },
-            "delete": {
+            "post": {
                 "security": [
                     {
                         "BearerToken": []
                     }
                 ],
-                "description": "Delete a category by ID",
+                "description": "Upload a JPEG, PNG or WebP image to the end of a bike's gallery. The first image of a bike becomes its primary image.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Categories"
+                    "Bikes"
                 ],
-                "summary": "Delete a category",
+                "summary": "Upload a bike image",
                 "parameters": [
                     {
                         "type": "string",
@@ -876,17 +979,36 @@ const docTemplate = `{
                     },
                     {
                         "type": "integer",
-                        "description": "Category ID",
+                        "description": "Bike ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Image file",
+                        "name": "image",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Make the image the primary image of the bike",
+                        "name": "is_primary",
+                        "in": "formData"
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/web.WebSuccess-response_CategoryResponse"
+                            "$ref": "#/definitions/web.WebSuccess-response_BikeImageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebBadRequestError"
                         }
                     },
                     "404": {
@@ -895,6 +1017,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/web.WebNotFoundError"
                         }
                     },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebError"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebError"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -902,14 +1036,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/api/bikes/{id}/images/order": {
             "patch": {
                 "security": [
                     {
                         "BearerToken": []
                     }
                 ],
-                "description": "Update an existing category",
+                "description": "Set the display order of a bike's gallery, image_ids must list every image of the bike",
                 "consumes": [
                     "application/json"
                 ],
@@ -917,9 +1053,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Categories"
+                    "Bikes"
                 ],
-                "summary": "Update a category",
+                "summary": "Reorder bike images",
                 "parameters": [
                     {
                         "type": "string",
@@ -930,18 +1066,18 @@ const docTemplate = `{
                     },
                     {
                         "type": "integer",
-                        "description": "Category ID",
+                        "description": "Bike ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Category body",
-                        "name": "category",
+                        "description": "Image order",
+                        "name": "order",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/request.UpdateCategoryRequest"
+                            "$ref": "#/definitions/request.BikeImageReorderRequest"
                         }
                     }
                 ],
@@ -949,7 +1085,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/web.WebSuccess-response_CategoryResponse"
+                            "$ref": "#/definitions/web.WebSuccess-array_response_BikeImageResponse"
                         }
                     },
                     "400": {
@@ -958,12 +1094,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/web.WebBadRequestError"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/web.WebNotFoundError"
-                        }
-                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -973,21 +1103,21 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/reviews": {
-            "get": {
+        "/api/bikes/{id}/images/{imageId}": {
+            "delete": {
                 "security": [
                     {
                         "BearerToken": []
                     }
                 ],
-                "description": "Get all reviews",
+                "description": "Delete a bike image, the next image in the gallery becomes primary when the primary image is deleted",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Reviews"
+                    "Bikes"
                 ],
-                "summary": "Get all reviews",
+                "summary": "Delete a bike image",
                 "parameters": [
                     {
                         "type": "string",
@@ -998,24 +1128,33 @@ const docTemplate = `{
                     },
                     {
                         "type": "integer",
-                        "default": 10,
-                        "description": "Limit",
-                        "name": "limit",
-                        "in": "query"
+                        "description": "Bike ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     },
                     {
                         "type": "integer",
-                        "default": 1,
-                        "description": "Page",
-                        "name": "page",
-                        "in": "query"
+                        "description": "Bike image ID",
+                        "name": "imageId",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/web.WebSuccess-array_response_ReviewResponse"
+                            "$ref": "#/definitions/web.WebBadRequestError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebNotFoundError"
                         }
                     },
                     "500": {
@@ -1025,24 +1164,23 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/api/bikes/{id}/images/{imageId}/primary": {
+            "patch": {
                 "security": [
                     {
                         "BearerToken": []
                     }
                 ],
-                "description": "Create a new review",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Make an image the primary image of its bike, which is also used as the bike's image_url",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Reviews"
+                    "Bikes"
                 ],
-                "summary": "Create a review",
+                "summary": "Set the primary bike image",
                 "parameters": [
                     {
                         "type": "string",
@@ -1052,20 +1190,25 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Review body",
-                        "name": "review",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/request.CreateReviewRequest"
-                        }
+                        "type": "integer",
+                        "description": "Bike ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Bike image ID",
+                        "name": "imageId",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/web.WebSuccess-response_ReviewResponse"
+                            "$ref": "#/definitions/web.WebSuccess-response_BikeImageResponse"
                         }
                     },
                     "400": {
@@ -1074,6 +1217,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/web.WebBadRequestError"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebNotFoundError"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1083,42 +1232,43 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/reviews/order/{orderId}": {
+        "/api/bikes/{id}/related": {
             "get": {
-                "security": [
-                    {
-                        "BearerToken": []
-                    }
-                ],
-                "description": "Get a review by ID",
+                "description": "Get the bikes customers bought together with a bike, most often first, topped up with bikes of the same category at a similar price when there aren't enough",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Reviews"
+                    "Bikes"
                 ],
-                "summary": "Get a review by ID",
+                "summary": "Get related bikes",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Authorization. How to input in swagger : 'Bearer \u003cinsert_your_token_here\u003e'",
-                        "name": "Authorization",
-                        "in": "header",
+                        "type": "integer",
+                        "description": "Bike ID",
+                        "name": "id",
+                        "in": "path",
                         "required": true
                     },
                     {
                         "type": "integer",
-                        "description": "Order ID",
-                        "name": "Id",
-                        "in": "path",
-                        "required": true
+                        "default": 8,
+                        "description": "Limit, at most 20",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/web.WebSuccess-response_ReviewResponse"
+                            "$ref": "#/definitions/web.WebSuccess-array_response_RelatedBikeResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebBadRequestError"
                         }
                     },
                     "404": {
@@ -1136,21 +1286,21 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/reviews/{id}": {
-            "get": {
+        "/api/bikes/{id}/restore": {
+            "post": {
                 "security": [
                     {
                         "BearerToken": []
                     }
                 ],
-                "description": "Get a review by ID",
+                "description": "Restore an archived bike, its category must not be archived",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Reviews"
+                    "Bikes"
                 ],
-                "summary": "Get a review by ID",
+                "summary": "Restore a bike",
                 "parameters": [
                     {
                         "type": "string",
@@ -1161,7 +1311,7 @@ const docTemplate = `{
                     },
                     {
                         "type": "integer",
-                        "description": "Review ID",
+                        "description": "Bike ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -1171,7 +1321,13 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/web.WebSuccess-response_ReviewResponse"
+                            "$ref": "#/definitions/web.WebSuccess-response_BikeResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebBadRequestError"
                         }
                     },
                     "404": {
@@ -1180,6 +1336,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/web.WebNotFoundError"
                         }
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebError"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1187,21 +1349,58 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/api/bikes/{id}/reviews": {
+            "get": {
+                "description": "Get reviews by bike id",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Bikes"
+                ],
+                "summary": "Get reviews by bike id",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Bike ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebSuccess-array_response_ReviewResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebInternalServerError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/bikes/{id}/stock-movements": {
+            "get": {
                 "security": [
                     {
                         "BearerToken": []
                     }
                 ],
-                "description": "Delete a review by ID",
+                "description": "Get the inventory ledger of a bike's variants, newest first",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Reviews"
+                    "Bikes"
                 ],
-                "summary": "Delete a review",
+                "summary": "Get the stock movements of a bike",
                 "parameters": [
                     {
                         "type": "string",
@@ -1212,17 +1411,56 @@ const docTemplate = `{
                     },
                     {
                         "type": "integer",
-                        "description": "Review ID",
+                        "description": "Bike ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only the movements of this variant",
+                        "name": "variant_id",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "restock",
+                            "sale",
+                            "return",
+                            "adjustment",
+                            "reservation"
+                        ],
+                        "type": "string",
+                        "description": "Movement type",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page",
+                        "name": "page",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/web.WebSuccess-response_ReviewResponse"
+                            "$ref": "#/definitions/web.WebSuccess-array_response_StockMovementResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebBadRequestError"
                         }
                     },
                     "404": {
@@ -1238,14 +1476,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "patch": {
+            }
+        },
+        "/api/bikes/{id}/variants": {
+            "post": {
                 "security": [
                     {
                         "BearerToken": []
                     }
                 ],
-                "description": "Update an existing review",
+                "description": "Add a variant (frame size, colour) with its own SKU, price and stock to a bike",
                 "consumes": [
                     "application/json"
                 ],
@@ -1253,9 +1493,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Reviews"
+                    "Bikes"
                 ],
-                "summary": "Update a review",
+                "summary": "Create a bike variant",
                 "parameters": [
                     {
                         "type": "string",
@@ -1266,26 +1506,26 @@ const docTemplate = `{
                     },
                     {
                         "type": "integer",
-                        "description": "Review ID",
+                        "description": "Bike ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Review body",
-                        "name": "review",
+                        "description": "Bike variant body",
+                        "name": "variant",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/request.UpdateReviewRequest"
+                            "$ref": "#/definitions/request.CreateBikeVariantRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/web.WebSuccess-response_ReviewResponse"
+                            "$ref": "#/definitions/web.WebSuccess-response_BikeVariantResponse"
                         }
                     },
                     "400": {
@@ -1300,6 +1540,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/web.WebNotFoundError"
                         }
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebError"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1309,21 +1555,21 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/transactions": {
-            "get": {
+        "/api/bikes/{id}/variants/{variantId}": {
+            "delete": {
                 "security": [
                     {
                         "BearerToken": []
                     }
                 ],
-                "description": "Registering a user from public access.",
+                "description": "Delete a bike variant, a bike must keep at least one variant and variants reserved by pending transactions can't be deleted",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Transactions"
+                    "Bikes"
                 ],
-                "summary": "Get all transaction.",
+                "summary": "Delete a bike variant",
                 "parameters": [
                     {
                         "type": "string",
@@ -1334,25 +1580,22 @@ const docTemplate = `{
                     },
                     {
                         "type": "integer",
-                        "default": 10,
-                        "description": "Limit",
-                        "name": "limit",
-                        "in": "query"
+                        "description": "Bike ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     },
                     {
                         "type": "integer",
-                        "default": 1,
-                        "description": "Page",
-                        "name": "page",
-                        "in": "query"
+                        "description": "Bike variant ID",
+                        "name": "variantId",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/web.WebSuccess-array_response_TransactionResponse"
-                        }
+                    "204": {
+                        "description": "No Content"
                     },
                     "400": {
                         "description": "Bad Request",
@@ -1360,6 +1603,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/web.WebBadRequestError"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebNotFoundError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebError"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1368,13 +1623,13 @@ const docTemplate = `{
                     }
                 }
             },
-            "post": {
+            "patch": {
                 "security": [
                     {
                         "BearerToken": []
                     }
                 ],
-                "description": "Create a new transaction",
+                "description": "Update a bike variant's SKU, attributes, price or availability. Stock is changed by posting stock movements",
                 "consumes": [
                     "application/json"
                 ],
@@ -1382,9 +1637,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Transactions"
+                    "Bikes"
                 ],
-                "summary": "Create a new transaction",
+                "summary": "Update a bike variant",
                 "parameters": [
                     {
                         "type": "string",
@@ -1394,15 +1649,26 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Transaction payload",
-                        "name": "payload",
+                        "type": "integer",
+                        "description": "Bike ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Bike variant ID",
+                        "name": "variantId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Bike variant body",
+                        "name": "variant",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/request.TransactionCreate"
-                            }
+                            "$ref": "#/definitions/request.UpdateBikeVariantRequest"
                         }
                     }
                 ],
@@ -1410,7 +1676,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/web.WebSuccess-response_CreateTransactionResponse"
+                            "$ref": "#/definitions/web.WebSuccess-response_BikeVariantResponse"
                         }
                     },
                     "400": {
@@ -1419,6 +1685,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/web.WebBadRequestError"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebNotFoundError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebError"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1428,21 +1706,24 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/transactions/payment/{id}": {
-            "patch": {
+        "/api/bikes/{id}/variants/{variantId}/stock-movements": {
+            "post": {
                 "security": [
                     {
                         "BearerToken": []
                     }
                 ],
-                "description": "Pay for a transaction",
+                "description": "Restock a bike variant, take back returned units or adjust its stock after a count. The movement is recorded in the inventory ledger",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Transactions"
+                    "Bikes"
                 ],
-                "summary": "Pay for a transaction",
+                "summary": "Post a stock movement",
                 "parameters": [
                     {
                         "type": "string",
@@ -1453,17 +1734,33 @@ const docTemplate = `{
                     },
                     {
                         "type": "integer",
-                        "description": "Transaction ID",
+                        "description": "Bike ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Bike variant ID",
+                        "name": "variantId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Stock movement body",
+                        "name": "movement",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.CreateStockMovementRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/web.WebSuccess-string"
+                            "$ref": "#/definitions/web.WebSuccess-response_StockMovementResponse"
                         }
                     },
                     "400": {
@@ -1472,6 +1769,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/web.WebBadRequestError"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebNotFoundError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebError"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1481,30 +1790,43 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/transactions/{id}": {
+        "/api/brands": {
             "get": {
-                "description": "Get transaction by ID",
+                "description": "Get all brands ordered by name, with the number of active bikes of each",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Transactions"
+                    "Brands"
                 ],
-                "summary": "Get transaction by ID",
+                "summary": "Get all brands",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name contains",
+                        "name": "name",
+                        "in": "query"
+                    },
                     {
                         "type": "integer",
-                        "description": "Transaction ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "default": 10,
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page",
+                        "name": "page",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/web.WebSuccess-response_TransactionResponse"
+                            "$ref": "#/definitions/web.WebSuccess-array_response_BrandResponse"
                         }
                     },
                     "400": {
@@ -1521,20 +1843,23 @@ const docTemplate = `{
                     }
                 }
             },
-            "delete": {
+            "post": {
                 "security": [
                     {
                         "BearerToken": []
                     }
                 ],
-                "description": "Delete a transaction",
+                "description": "Create a new brand. The slug defaults to one derived from the name",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Transactions"
+                    "Brands"
                 ],
-                "summary": "Delete a transaction",
+                "summary": "Create a brand",
                 "parameters": [
                     {
                         "type": "string",
@@ -1544,18 +1869,20 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "Transaction ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
+                        "description": "Brand body",
+                        "name": "brand",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.CreateBrandRequest"
+                        }
+                    }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/web.WebSuccess-string"
+                            "$ref": "#/definitions/web.WebSuccess-response_BrandResponse"
                         }
                     },
                     "400": {
@@ -1564,6 +1891,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/web.WebBadRequestError"
                         }
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebError"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1571,57 +1904,32 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "patch": {
-                "security": [
-                    {
-                        "BearerToken": []
-                    }
-                ],
-                "description": "Update a transaction",
-                "consumes": [
-                    "application/json"
-                ],
+            }
+        },
+        "/api/brands/{id}": {
+            "get": {
+                "description": "Get a brand by ID",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Transactions"
+                    "Brands"
                 ],
-                "summary": "Update a transaction",
+                "summary": "Get a brand by ID",
                 "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Authorization. How to input in swagger : 'Bearer \u003cinsert_your_token_here\u003e'",
-                        "name": "Authorization",
-                        "in": "header",
-                        "required": true
-                    },
                     {
                         "type": "integer",
-                        "description": "Transaction ID",
+                        "description": "Brand ID",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Transaction update payload",
-                        "name": "payload",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/request.TransactionUpdate"
-                            }
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/web.WebSuccess-string"
+                            "$ref": "#/definitions/web.WebSuccess-response_BrandResponse"
                         }
                     },
                     "400": {
@@ -1630,6 +1938,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/web.WebBadRequestError"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebNotFoundError"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1637,23 +1951,21 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/users": {
-            "get": {
+            },
+            "delete": {
                 "security": [
                     {
                         "BearerToken": []
                     }
                 ],
-                "description": "Get all users",
+                "description": "Delete a brand that no bike refers to, archived bikes included",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Brands"
                 ],
-                "summary": "Get all users",
+                "summary": "Delete a brand",
                 "parameters": [
                     {
                         "type": "string",
@@ -1664,24 +1976,29 @@ const docTemplate = `{
                     },
                     {
                         "type": "integer",
-                        "default": 10,
-                        "description": "Limit",
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "default": 1,
-                        "description": "Page",
-                        "name": "page",
-                        "in": "query"
+                        "description": "Brand ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/web.WebSuccess-array_response_UserResponse"
+                            "$ref": "#/definitions/web.WebSuccess-string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebNotFoundError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebError"
                         }
                     },
                     "500": {
@@ -1691,23 +2008,24 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/users/current": {
-            "get": {
+            },
+            "patch": {
                 "security": [
                     {
                         "BearerToken": []
                     }
                 ],
-                "description": "Get current user.",
+                "description": "Update an existing brand. Renaming a brand renames it on its bikes too",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Brands"
                 ],
-                "summary": "Get current user.",
+                "summary": "Update a brand",
                 "parameters": [
                     {
                         "type": "string",
@@ -1715,13 +2033,29 @@ const docTemplate = `{
                         "name": "Authorization",
                         "in": "header",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Brand ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Brand body",
+                        "name": "brand",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.UpdateBrandRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/web.WebSuccess-response_GetUserCurrentResponse"
+                            "$ref": "#/definitions/web.WebSuccess-response_BrandResponse"
                         }
                     },
                     "400": {
@@ -1736,6 +2070,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/web.WebNotFoundError"
                         }
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebError"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1745,21 +2085,24 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/users/current/carts": {
-            "get": {
+        "/api/carts": {
+            "post": {
                 "security": [
                     {
                         "BearerToken": []
                     }
                 ],
-                "description": "Find a user carts by username.",
+                "description": "Create a new cart item",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Carts"
                 ],
-                "summary": "Find user carts.",
+                "summary": "Create a new cart item",
                 "parameters": [
                     {
                         "type": "string",
@@ -1767,19 +2110,28 @@ const docTemplate = `{
                         "name": "Authorization",
                         "in": "header",
                         "required": true
+                    },
+                    {
+                        "description": "Cart Item Create",
+                        "name": "cart",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.CartItemCreateRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/web.WebSuccess-response_GetUserCartResponse"
+                            "$ref": "#/definitions/web.WebSuccess-response_CartResponse"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/web.WebNotFoundError"
+                            "$ref": "#/definitions/web.WebBadRequestError"
                         }
                     },
                     "500": {
@@ -1789,23 +2141,24 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/users/current/transactions": {
-            "get": {
+            },
+            "delete": {
                 "security": [
                     {
                         "BearerToken": []
                     }
                 ],
-                "description": "Find a user transactions by username.",
+                "description": "Delete a cart",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Carts"
                 ],
-                "summary": "Find user transactions.",
+                "summary": "Delete a cart",
                 "parameters": [
                     {
                         "type": "string",
@@ -1813,19 +2166,31 @@ const docTemplate = `{
                         "name": "Authorization",
                         "in": "header",
                         "required": true
+                    },
+                    {
+                        "description": "Cart Update",
+                        "name": "cart",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.CartItemDeleteRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/web.WebSuccess-response_UserTransactionResponse"
+                            "$ref": "#/definitions/web.WebSuccess-string"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/web.WebNotFoundError"
+                            "$ref": "#/definitions/web.WebBadRequestError"
                         }
                     },
                     "500": {
@@ -1835,23 +2200,24 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/users/profile": {
+            },
             "patch": {
                 "security": [
                     {
                         "BearerToken": []
                     }
                 ],
-                "description": "Update user profile.",
+                "description": "Update a cart",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Carts"
                 ],
-                "summary": "Update user profile.",
+                "summary": "Update a cart",
                 "parameters": [
                     {
                         "type": "string",
@@ -1861,12 +2227,12 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "the body to reset password",
-                        "name": "Body",
+                        "description": "Cart Item Update",
+                        "name": "cart",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/request.ProfileUpdateRequest"
+                            "$ref": "#/definitions/request.CartItemUpdateRequest"
                         }
                     }
                 ],
@@ -1874,7 +2240,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/web.WebSuccess-response_ProfileResponse"
+                            "$ref": "#/definitions/web.WebSuccess-response_CartResponse"
                         }
                     },
                     "400": {
@@ -1883,18 +2249,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/web.WebBadRequestError"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
-                        "schema": {
-                            "$ref": "#/definitions/web.WebUnauthorizedError"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/web.WebNotFoundError"
-                        }
-                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1904,30 +2258,58 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/users/profile/{username}": {
-            "get": {
-                "description": "Find a user profile by username.",
+        "/api/carts/checkout": {
+            "post": {
+                "security": [
+                    {
+                        "BearerToken": []
+                    }
+                ],
+                "description": "Turn the whole cart, or only the selected cart items, into a transaction and get its payment link",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Carts"
                 ],
-                "summary": "Find user profile.",
+                "summary": "Checkout the cart",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "username",
-                        "name": "username",
-                        "in": "path",
+                        "description": "Authorization. How to input in swagger : 'Bearer \u003cinsert_your_token_here\u003e'",
+                        "name": "Authorization",
+                        "in": "header",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Key to safely retry the request, repeats within 24 hours get the first response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Cart items to checkout, leave empty to checkout the whole cart",
+                        "name": "cart",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/request.CartCheckoutRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/web.WebSuccess-response_ProfileResponse"
+                            "$ref": "#/definitions/web.WebSuccess-response_CreateTransactionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebBadRequestError"
                         }
                     },
                     "404": {
@@ -1936,23 +2318,93 @@ const docTemplate = `{
                             "$ref": "#/definitions/web.WebNotFoundError"
                         }
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebError"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebError"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/web.WebInternalServerError"
                         }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebError"
+                        }
                     }
                 }
             }
         },
-        "/roles/update": {
-            "patch": {
+        "/api/categories": {
+            "get": {
+                "description": "Get all categories",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Categories"
+                ],
+                "summary": "Get all categories",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include archived categories, admin only",
+                        "name": "include_archived",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebSuccess-array_response_CategoryResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebForbiddenError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebInternalServerError"
+                        }
+                    }
+                }
+            },
+            "post": {
                 "security": [
                     {
                         "BearerToken": []
                     }
                 ],
-                "description": "Update the role for a user based on user ID and role ID. Role 1 is for admin and role 2 is for user.",
+                "description": "Create a new category, optionally under a parent. The slug defaults to one derived from the name",
                 "consumes": [
                     "application/json"
                 ],
@@ -1960,9 +2412,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Roles"
+                    "Categories"
                 ],
-                "summary": "Update role for a specific user by ID",
+                "summary": "Create a category",
                 "parameters": [
                     {
                         "type": "string",
@@ -1972,20 +2424,20 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Update Role Request",
-                        "name": "request",
+                        "description": "Category body",
+                        "name": "category",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/request.UpdateRoleRequest"
+                            "$ref": "#/definitions/request.CreateCategoryRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/web.WebSuccess-response_RoleResponse"
+                            "$ref": "#/definitions/web.WebSuccess-response_CategoryResponse"
                         }
                     },
                     "400": {
@@ -2000,6 +2452,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/web.WebNotFoundError"
                         }
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebError"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -2008,839 +2466,5345 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "request.CartItemCreateRequest": {
-            "type": "object",
-            "required": [
-                "bike_id",
-                "quantity"
-            ],
-            "properties": {
-                "bike_id": {
-                    "type": "integer"
-                },
-                "quantity": {
-                    "type": "integer"
-                }
-            }
-        },
-        "request.CartItemDeleteRequest": {
-            "type": "object",
-            "required": [
-                "bike_id"
-            ],
-            "properties": {
-                "bike_id": {
-                    "type": "integer"
-                }
-            }
-        },
-        "request.CartItemUpdateRequest": {
-            "type": "object",
-            "required": [
-                "bike_id",
-                "quantity"
-            ],
-            "properties": {
-                "bike_id": {
-                    "type": "integer"
-                },
-                "quantity": {
-                    "type": "integer"
-                }
-            }
-        },
-        "request.CreateBikeRequest": {
-            "type": "object",
-            "required": [
-                "brand",
-                "category_id",
-                "image_url",
-                "name",
-                "price",
-                "stock",
-                "year"
-            ],
-            "properties": {
-                "brand": {
-                    "type": "string"
-                },
-                "category_id": {
-                    "type": "integer"
-                },
-                "description": {
-                    "type": "string"
-                },
-                "image_url": {
-                    "type": "string"
-                },
-                "is_available": {
-                    "type": "boolean"
-                },
-                "name": {
-                    "type": "string"
-                },
-                "price": {
-                    "type": "integer"
-                },
-                "stock": {
-                    "type": "integer"
-                },
-                "year": {
-                    "type": "integer"
-                }
-            }
         },
-        "request.CreateCategoryRequest": {
-            "type": "object",
-            "required": [
-                "name"
-            ],
-            "properties": {
-                "name": {
-                    "type": "string"
+        "/api/categories/tree": {
+            "get": {
+                "description": "Get the active categories nested under their parents, with the number of active bikes in each category and in its whole subtree",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Categories"
+                ],
+                "summary": "Get the category tree",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebSuccess-array_response_CategoryTreeResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebInternalServerError"
+                        }
+                    }
                 }
             }
         },
-        "request.CreateReviewRequest": {
-            "type": "object",
-            "required": [
-                "bike_id",
-                "comment",
-                "order_id",
-                "rating"
-            ],
-            "properties": {
-                "bike_id": {
-                    "type": "integer"
-                },
-                "comment": {
-                    "type": "string"
-                },
-                "order_id": {
-                    "type": "integer"
-                },
-                "rating": {
-                    "type": "integer",
-                    "maximum": 5,
-                    "minimum": 1
+        "/api/categories/{id}": {
+            "get": {
+                "description": "Get a category by ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Categories"
+                ],
+                "summary": "Get a category by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Category ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebSuccess-response_CategoryResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebNotFoundError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebInternalServerError"
+                        }
+                    }
                 }
-            }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerToken": []
+                    }
+                ],
+                "description": "Archive a category and its bikes. They are hidden from listings but still resolve for orders and reviews, and can be restored.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Categories"
+                ],
+                "summary": "Archive a category",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authorization. How to input in swagger : 'Bearer \u003cinsert_your_token_here\u003e'",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Category ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebSuccess-response_CategoryResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebNotFoundError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebInternalServerError"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerToken": []
+                    }
+                ],
+                "description": "Update an existing category. Set parent_id to move it, 0 moves it to the top level",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Categories"
+                ],
+                "summary": "Update a category",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authorization. How to input in swagger : 'Bearer \u003cinsert_your_token_here\u003e'",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Category ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Category body",
+                        "name": "category",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.UpdateCategoryRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebSuccess-response_CategoryResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebBadRequestError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebNotFoundError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebInternalServerError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/categories/{id}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "BearerToken": []
+                    }
+                ],
+                "description": "Restore an archived category together with the bikes that were archived with it",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Categories"
+                ],
+                "summary": "Restore a category",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authorization. How to input in swagger : 'Bearer \u003cinsert_your_token_here\u003e'",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Category ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebSuccess-response_CategoryResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebBadRequestError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebNotFoundError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebInternalServerError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/discounts": {
+            "get": {
+                "security": [
+                    {
+                        "BearerToken": []
+                    }
+                ],
+                "description": "Get discounts, newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Discounts"
+                ],
+                "summary": "Get all discounts",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authorization. How to input in swagger : 'Bearer \u003cinsert_your_token_here\u003e'",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "bike",
+                            "category",
+                            "brand"
+                        ],
+                        "type": "string",
+                        "description": "Target type",
+                        "name": "target_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Target ID",
+                        "name": "target_id",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "scheduled",
+                            "active",
+                            "expired"
+                        ],
+                        "type": "string",
+                        "description": "Status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page",
+                        "name": "page",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebSuccess-array_response_DiscountResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebBadRequestError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebForbiddenError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebInternalServerError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerToken": []
+                    }
+                ],
+                "description": "Schedule a percentage or fixed amount discount on a bike, a category and its subcategories, or a brand. Discounts don't stack, a bike sells with the discount that lowers its price most.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Discounts"
+                ],
+                "summary": "Create a discount",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authorization. How to input in swagger : 'Bearer \u003cinsert_your_token_here\u003e'",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Discount body",
+                        "name": "discount",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.CreateDiscountRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebSuccess-response_DiscountResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebBadRequestError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebForbiddenError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebNotFoundError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebInternalServerError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/discounts/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerToken": []
+                    }
+                ],
+                "description": "Get a discount by ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Discounts"
+                ],
+                "summary": "Get a discount by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authorization. How to input in swagger : 'Bearer \u003cinsert_your_token_here\u003e'",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Discount ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebSuccess-response_DiscountResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebForbiddenError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebNotFoundError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebInternalServerError"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerToken": []
+                    }
+                ],
+                "description": "Delete a discount. Orders keep the price they were placed with.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Discounts"
+                ],
+                "summary": "Delete a discount",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authorization. How to input in swagger : 'Bearer \u003cinsert_your_token_here\u003e'",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Discount ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebSuccess-string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebForbiddenError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebNotFoundError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebInternalServerError"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerToken": []
+                    }
+                ],
+                "description": "Update the name, value or schedule of a discount. Setting ends_at to now ends it.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Discounts"
+                ],
+                "summary": "Update a discount",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authorization. How to input in swagger : 'Bearer \u003cinsert_your_token_here\u003e'",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Discount ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Discount body",
+                        "name": "discount",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.UpdateDiscountRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebSuccess-response_DiscountResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebBadRequestError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebForbiddenError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebNotFoundError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebInternalServerError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/payments/midtrans/notification": {
+            "post": {
+                "description": "Webhook called by Midtrans whenever a payment changes status. The request is authenticated with its signature_key.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Payments"
+                ],
+                "summary": "Midtrans HTTP notification",
+                "parameters": [
+                    {
+                        "description": "Midtrans notification body",
+                        "name": "notification",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.MidtransNotificationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebSuccess-string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebBadRequestError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebForbiddenError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebNotFoundError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebInternalServerError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/reviews": {
+            "get": {
+                "security": [
+                    {
+                        "BearerToken": []
+                    }
+                ],
+                "description": "Get all reviews",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Reviews"
+                ],
+                "summary": "Get all reviews",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authorization. How to input in swagger : 'Bearer \u003cinsert_your_token_here\u003e'",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page",
+                        "name": "page",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebSuccess-array_response_ReviewResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebInternalServerError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerToken": []
+                    }
+                ],
+                "description": "Create a new review",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Reviews"
+                ],
+                "summary": "Create a review",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authorization. How to input in swagger : 'Bearer \u003cinsert_your_token_here\u003e'",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Review body",
+                        "name": "review",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.CreateReviewRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebSuccess-response_ReviewResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebBadRequestError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebInternalServerError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/reviews/order/{orderId}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerToken": []
+                    }
+                ],
+                "description": "Get a review by ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Reviews"
+                ],
+                "summary": "Get a review by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authorization. How to input in swagger : 'Bearer \u003cinsert_your_token_here\u003e'",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Order ID",
+                        "name": "Id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebSuccess-response_ReviewResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebNotFoundError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebInternalServerError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/reviews/recompute-ratings": {
+            "post": {
+                "security": [
+                    {
+                        "BearerToken": []
+                    }
+                ],
+                "description": "Rebuild the average rating and rating histogram of every bike from its reviews, returning how many bikes were corrected",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Reviews"
+                ],
+                "summary": "Recompute bike ratings",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authorization. How to input in swagger : 'Bearer \u003cinsert_your_token_here\u003e'",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebSuccess-response_RecomputeBikeRatingsResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebForbiddenError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebInternalServerError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/reviews/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerToken": []
+                    }
+                ],
+                "description": "Get a review by ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Reviews"
+                ],
+                "summary": "Get a review by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authorization. How to input in swagger : 'Bearer \u003cinsert_your_token_here\u003e'",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Review ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebSuccess-response_ReviewResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebNotFoundError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebInternalServerError"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerToken": []
+                    }
+                ],
+                "description": "Delete a review by ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Reviews"
+                ],
+                "summary": "Delete a review",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authorization. How to input in swagger : 'Bearer \u003cinsert_your_token_here\u003e'",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Review ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebSuccess-response_ReviewResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebNotFoundError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebInternalServerError"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerToken": []
+                    }
+                ],
+                "description": "Update an existing review",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Reviews"
+                ],
+                "summary": "Update a review",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authorization. How to input in swagger : 'Bearer \u003cinsert_your_token_here\u003e'",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Review ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Review body",
+                        "name": "review",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.UpdateReviewRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebSuccess-response_ReviewResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebBadRequestError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebNotFoundError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebInternalServerError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/spec-attributes": {
+            "get": {
+                "description": "Get the spec attributes that apply to a category, those of its parent categories included, or all of them",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Spec Attributes"
+                ],
+                "summary": "Get spec attributes",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Category ID",
+                        "name": "category_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebSuccess-array_response_SpecAttributeResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebBadRequestError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebInternalServerError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerToken": []
+                    }
+                ],
+                "description": "Define a technical spec of the bikes in a category and its subcategories. Enum attributes list their options, number attributes may have a unit.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Spec Attributes"
+                ],
+                "summary": "Create a spec attribute",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authorization. How to input in swagger : 'Bearer \u003cinsert_your_token_here\u003e'",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Spec attribute body",
+                        "name": "attribute",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.CreateSpecAttributeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebSuccess-response_SpecAttributeResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebBadRequestError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebNotFoundError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebInternalServerError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/spec-attributes/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerToken": []
+                    }
+                ],
+                "description": "Delete a spec attribute together with the values bikes have for it",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Spec Attributes"
+                ],
+                "summary": "Delete a spec attribute",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authorization. How to input in swagger : 'Bearer \u003cinsert_your_token_here\u003e'",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Spec attribute ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebSuccess-string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebNotFoundError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebInternalServerError"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerToken": []
+                    }
+                ],
+                "description": "Update the name, unit or enum options of a spec attribute. Options still used by bikes can't be removed.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Spec Attributes"
+                ],
+                "summary": "Update a spec attribute",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authorization. How to input in swagger : 'Bearer \u003cinsert_your_token_here\u003e'",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Spec attribute ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Spec attribute body",
+                        "name": "attribute",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.UpdateSpecAttributeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebSuccess-response_SpecAttributeResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebBadRequestError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebNotFoundError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebInternalServerError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/stock-alerts": {
+            "get": {
+                "security": [
+                    {
+                        "BearerToken": []
+                    }
+                ],
+                "description": "Get the alerts raised when bike variants fell to their low stock threshold or ran out, newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Stock Alerts"
+                ],
+                "summary": "Get stock alerts",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authorization. How to input in swagger : 'Bearer \u003cinsert_your_token_here\u003e'",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Bike ID",
+                        "name": "bike_id",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "low_stock",
+                            "out_of_stock"
+                        ],
+                        "type": "string",
+                        "description": "Alert type",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "open",
+                            "acknowledged",
+                            "resolved"
+                        ],
+                        "type": "string",
+                        "description": "Alert status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page",
+                        "name": "page",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebSuccess-array_response_StockAlertResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebBadRequestError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebForbiddenError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebInternalServerError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/stock-alerts/{id}/acknowledge": {
+            "patch": {
+                "security": [
+                    {
+                        "BearerToken": []
+                    }
+                ],
+                "description": "Mark a stock alert as seen, it resolves on its own once the variant is restocked",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Stock Alerts"
+                ],
+                "summary": "Acknowledge a stock alert",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authorization. How to input in swagger : 'Bearer \u003cinsert_your_token_here\u003e'",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Stock alert ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebSuccess-response_StockAlertResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebBadRequestError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebNotFoundError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebInternalServerError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/transactions": {
+            "get": {
+                "security": [
+                    {
+                        "BearerToken": []
+                    }
+                ],
+                "description": "Registering a user from public access.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Transactions"
+                ],
+                "summary": "Get all transaction.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authorization. How to input in swagger : 'Bearer \u003cinsert_your_token_here\u003e'",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page",
+                        "name": "page",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebSuccess-array_response_TransactionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebBadRequestError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebInternalServerError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerToken": []
+                    }
+                ],
+                "description": "Create a new transaction",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Transactions"
+                ],
+                "summary": "Create a new transaction",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authorization. How to input in swagger : 'Bearer \u003cinsert_your_token_here\u003e'",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Key to safely retry the request, repeats within 24 hours get the first response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Transaction payload",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/request.TransactionCreate"
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebSuccess-response_CreateTransactionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebBadRequestError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebError"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebInternalServerError"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/transactions/payment/{id}": {
+            "patch": {
+                "security": [
+                    {
+                        "BearerToken": []
+                    }
+                ],
+                "description": "Manually mark a pending transaction as paid, only admin can access. Buyers' transactions are settled through the Midtrans notification.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Transactions"
+                ],
+                "summary": "Mark a transaction as paid",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authorization. How to input in swagger : 'Bearer \u003cinsert_your_token_here\u003e'",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Key to safely retry the request, repeats within 24 hours get the first response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Transaction ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebSuccess-string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebBadRequestError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebForbiddenError"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebInternalServerError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/transactions/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerToken": []
+                    }
+                ],
+                "description": "Get transaction by ID, only the buyer or an admin can access.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Transactions"
+                ],
+                "summary": "Get transaction by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authorization. How to input in swagger : 'Bearer \u003cinsert_your_token_here\u003e'",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Transaction ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebSuccess-response_TransactionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebBadRequestError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebNotFoundError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebInternalServerError"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerToken": []
+                    }
+                ],
+                "description": "Delete a pending, cancelled or expired transaction, paid transactions can't be deleted",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Transactions"
+                ],
+                "summary": "Delete a transaction",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authorization. How to input in swagger : 'Bearer \u003cinsert_your_token_here\u003e'",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Transaction ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebSuccess-string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebBadRequestError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebInternalServerError"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerToken": []
+                    }
+                ],
+                "description": "Update the orders of a pending transaction, the total can't change once its payment was created",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Transactions"
+                ],
+                "summary": "Update a transaction",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authorization. How to input in swagger : 'Bearer \u003cinsert_your_token_here\u003e'",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Transaction ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Transaction update payload",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/request.TransactionUpdate"
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebSuccess-string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebBadRequestError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebInternalServerError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/transactions/{id}/invoice.pdf": {
+            "get": {
+                "security": [
+                    {
+                        "BearerToken": []
+                    }
+                ],
+                "description": "Download the PDF invoice of a paid transaction, only the buyer or an admin can access.",
+                "produces": [
+                    "application/pdf"
+                ],
+                "tags": [
+                    "Transactions"
+                ],
+                "summary": "Download a transaction invoice",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authorization. How to input in swagger : 'Bearer \u003cinsert_your_token_here\u003e'",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Transaction ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebBadRequestError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebNotFoundError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebInternalServerError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/transactions/{id}/refunds": {
+            "post": {
+                "security": [
+                    {
+                        "BearerToken": []
+                    }
+                ],
+                "description": "Refund a paid transaction through the payment gateway, either entirely or only some of its orders, only admin can access.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Transactions"
+                ],
+                "summary": "Refund a transaction",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authorization. How to input in swagger : 'Bearer \u003cinsert_your_token_here\u003e'",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Key to safely retry the request, repeats within 24 hours get the first response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Transaction ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Refund payload, leave items empty to refund the whole transaction",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.RefundCreate"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebSuccess-response_RefundResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebBadRequestError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebForbiddenError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebNotFoundError"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebInternalServerError"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/transactions/{id}/status": {
+            "patch": {
+                "security": [
+                    {
+                        "BearerToken": []
+                    }
+                ],
+                "description": "Move a transaction along its lifecycle (processing, shipped, delivered, completed) or cancel it, only admin can access.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Transactions"
+                ],
+                "summary": "Update a transaction status",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authorization. How to input in swagger : 'Bearer \u003cinsert_your_token_here\u003e'",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Transaction ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Transaction status payload",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.TransactionStatusUpdate"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebSuccess-string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebBadRequestError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebForbiddenError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebNotFoundError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebInternalServerError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/users": {
+            "get": {
+                "security": [
+                    {
+                        "BearerToken": []
+                    }
+                ],
+                "description": "Get all users",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Get all users",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authorization. How to input in swagger : 'Bearer \u003cinsert_your_token_here\u003e'",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page",
+                        "name": "page",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebSuccess-array_response_UserResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebInternalServerError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/users/current": {
+            "get": {
+                "security": [
+                    {
+                        "BearerToken": []
+                    }
+                ],
+                "description": "Get current user.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Get current user.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authorization. How to input in swagger : 'Bearer \u003cinsert_your_token_here\u003e'",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebSuccess-response_GetUserCurrentResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebBadRequestError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebNotFoundError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebInternalServerError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/users/current/carts": {
+            "get": {
+                "security": [
+                    {
+                        "BearerToken": []
+                    }
+                ],
+                "description": "Find a user carts by username.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Find user carts.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authorization. How to input in swagger : 'Bearer \u003cinsert_your_token_here\u003e'",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebSuccess-response_GetUserCartResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebNotFoundError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebInternalServerError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/users/current/transactions": {
+            "get": {
+                "security": [
+                    {
+                        "BearerToken": []
+                    }
+                ],
+                "description": "Find a user transactions by username.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Find user transactions.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authorization. How to input in swagger : 'Bearer \u003cinsert_your_token_here\u003e'",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebSuccess-response_UserTransactionResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebNotFoundError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebInternalServerError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/users/profile": {
+            "patch": {
+                "security": [
+                    {
+                        "BearerToken": []
+                    }
+                ],
+                "description": "Update user profile.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Update user profile.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authorization. How to input in swagger : 'Bearer \u003cinsert_your_token_here\u003e'",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "the body to reset password",
+                        "name": "Body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.ProfileUpdateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebSuccess-response_ProfileResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebBadRequestError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebUnauthorizedError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebNotFoundError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebInternalServerError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/users/profile/{username}": {
+            "get": {
+                "description": "Find a user profile by username.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Find user profile.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "username",
+                        "name": "username",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebSuccess-response_ProfileResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebNotFoundError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebInternalServerError"
+                        }
+                    }
+                }
+            }
+        },
+        "/fake-payments/{id}": {
+            "get": {
+                "description": "Payment link of the fake payment gateway, only served when PAYMENT_GATEWAY is fake. Opening it settles the charge, or moves it to the given Midtrans transaction_status, and handles the notification Midtrans would send.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Payments"
+                ],
+                "summary": "Pay with the fake payment gateway",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Midtrans transaction_status, settlement by default",
+                        "name": "transaction_status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebSuccess-string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebBadRequestError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebNotFoundError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebInternalServerError"
+                        }
+                    }
+                }
+            }
+        },
+        "/roles/update": {
+            "patch": {
+                "security": [
+                    {
+                        "BearerToken": []
+                    }
+                ],
+                "description": "Update the role for a user based on user ID and role ID. Role 1 is for admin and role 2 is for user.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Roles"
+                ],
+                "summary": "Update role for a specific user by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authorization. How to input in swagger : 'Bearer \u003cinsert_your_token_here\u003e'",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Update Role Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.UpdateRoleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebSuccess-response_RoleResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebBadRequestError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebNotFoundError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebInternalServerError"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "request.BikeImageReorderRequest": {
+            "type": "object",
+            "required": [
+                "image_ids"
+            ],
+            "properties": {
+                "image_ids": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "request.BikeImportRow": {
+            "type": "object",
+            "required": [
+                "category_id",
+                "image_url",
+                "name",
+                "price",
+                "year"
+            ],
+            "properties": {
+                "brand": {
+                    "type": "string"
+                },
+                "brand_id": {
+                    "type": "integer"
+                },
+                "category_id": {
+                    "type": "integer"
+                },
+                "color": {
+                    "type": "string",
+                    "maxLength": 30
+                },
+                "description": {
+                    "type": "string"
+                },
+                "frame_size": {
+                    "type": "string",
+                    "maxLength": 10
+                },
+                "image_url": {
+                    "type": "string"
+                },
+                "is_available": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "integer"
+                },
+                "sku": {
+                    "type": "string",
+                    "maxLength": 50
+                },
+                "specs": {
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "stock": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "variant_price": {
+                    "type": "integer"
+                },
+                "variants": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/request.CreateBikeVariantRequest"
+                    }
+                },
+                "year": {
+                    "type": "integer"
+                }
+            }
+        },
+        "request.CartCheckoutRequest": {
+            "type": "object",
+            "properties": {
+                "cart_item_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "request.CartItemCreateRequest": {
+            "type": "object",
+            "required": [
+                "quantity",
+                "variant_id"
+            ],
+            "properties": {
+                "quantity": {
+                    "type": "integer"
+                },
+                "variant_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "request.CartItemDeleteRequest": {
+            "type": "object",
+            "required": [
+                "variant_id"
+            ],
+            "properties": {
+                "variant_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "request.CartItemUpdateRequest": {
+            "type": "object",
+            "required": [
+                "quantity",
+                "variant_id"
+            ],
+            "properties": {
+                "quantity": {
+                    "type": "integer"
+                },
+                "variant_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "request.CreateBikeRequest": {
+            "type": "object",
+            "required": [
+                "category_id",
+                "image_url",
+                "name",
+                "price",
+                "year"
+            ],
+            "properties": {
+                "brand": {
+                    "type": "string"
+                },
+                "brand_id": {
+                    "type": "integer"
+                },
+                "category_id": {
+                    "type": "integer"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "image_url": {
+                    "type": "string"
+                },
+                "is_available": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "integer"
+                },
+                "specs": {
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "stock": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "variants": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/request.CreateBikeVariantRequest"
+                    }
+                },
+                "year": {
+                    "type": "integer"
+                }
+            }
+        },
+        "request.CreateBikeVariantRequest": {
+            "type": "object",
+            "required": [
+                "sku"
+            ],
+            "properties": {
+                "auto_availability": {
+                    "type": "boolean"
+                },
+                "color": {
+                    "type": "string",
+                    "maxLength": 30
+                },
+                "frame_size": {
+                    "type": "string",
+                    "maxLength": 10
+                },
+                "is_available": {
+                    "type": "boolean"
+                },
+                "low_stock_threshold": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "price": {
+                    "type": "integer"
+                },
+                "sku": {
+                    "type": "string",
+                    "maxLength": 50
+                },
+                "stock": {
+                    "type": "integer",
+                    "minimum": 0
+                }
+            }
+        },
+        "request.CreateBrandRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "country": {
+                    "type": "string",
+                    "maxLength": 50
+                },
+                "description": {
+                    "type": "string",
+                    "maxLength": 1000
+                },
+                "logo_url": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 20
+                },
+                "slug": {
+                    "type": "string",
+                    "maxLength": 30
+                }
+            }
+        },
+        "request.CreateCategoryRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string",
+                    "maxLength": 500
+                },
+                "image_url": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 50
+                },
+                "parent_id": {
+                    "type": "integer"
+                },
+                "slug": {
+                    "type": "string",
+                    "maxLength": 60
+                }
+            }
+        },
+        "request.CreateDiscountRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "target_id",
+                "target_type",
+                "type",
+                "value"
+            ],
+            "properties": {
+                "ends_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "starts_at": {
+                    "type": "string"
+                },
+                "target_id": {
+                    "type": "integer"
+                },
+                "target_type": {
+                    "type": "string",
+                    "enum": [
+                        "bike",
+                        "category",
+                        "brand"
+                    ]
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "percentage",
+                        "fixed"
+                    ]
+                },
+                "value": {
+                    "type": "integer"
+                }
+            }
+        },
+        "request.CreateReviewRequest": {
+            "type": "object",
+            "required": [
+                "bike_id",
+                "comment",
+                "order_id",
+                "rating"
+            ],
+            "properties": {
+                "bike_id": {
+                    "type": "integer"
+                },
+                "comment": {
+                    "type": "string"
+                },
+                "order_id": {
+                    "type": "integer"
+                },
+                "rating": {
+                    "type": "integer",
+                    "maximum": 5,
+                    "minimum": 1
+                }
+            }
+        },
+        "request.CreateSpecAttributeRequest": {
+            "type": "object",
+            "required": [
+                "category_id",
+                "key",
+                "name",
+                "options",
+                "type"
+            ],
+            "properties": {
+                "category_id": {
+                    "type": "integer"
+                },
+                "key": {
+                    "type": "string",
+                    "maxLength": 50
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 50
+                },
+                "options": {
+                    "type": "array",
+                    "uniqueItems": true,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "enum",
+                        "number",
+                        "boolean"
+                    ]
+                },
+                "unit": {
+                    "type": "string",
+                    "maxLength": 20
+                }
+            }
+        },
+        "request.CreateStockMovementRequest": {
+            "type": "object",
+            "required": [
+                "quantity",
+                "type"
+            ],
+            "properties": {
+                "note": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "reference": {
+                    "type": "string",
+                    "maxLength": 50
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "restock",
+                        "return",
+                        "adjustment"
+                    ]
+                }
+            }
+        },
+        "request.ForgotPasswordRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "username"
+            ],
+            "properties": {
+                "username": {
+                    "type": "string",
+                    "maxLength": 20,
+                    "minLength": 3,
+                    "x-order": "0"
+                },
+                "email": {
+                    "type": "string"
+                }
+            }
+        },
+        "request.LoginRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "password"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "x-order": "0"
+                },
+                "password": {
+                    "type": "string",
+                    "x-order": "1",
+                    "example": "password"
+                }
+            }
+        },
+        "request.MidtransNotificationRequest": {
+            "type": "object",
+            "required": [
+                "gross_amount",
+                "order_id",
+                "signature_key",
+                "status_code",
+                "transaction_status"
+            ],
+            "properties": {
+                "fraud_status": {
+                    "type": "string"
+                },
+                "gross_amount": {
+                    "type": "string"
+                },
+                "order_id": {
+                    "type": "string"
+                },
+                "payment_type": {
+                    "type": "string"
+                },
+                "settlement_time": {
+                    "type": "string"
+                },
+                "signature_key": {
+                    "type": "string"
+                },
+                "status_code": {
+                    "type": "string"
+                },
+                "transaction_id": {
+                    "type": "string"
+                },
+                "transaction_status": {
+                    "type": "string"
+                },
+                "transaction_time": {
+                    "type": "string"
+                }
+            }
+        },
+        "request.ProfileUpdateRequest": {
+            "type": "object",
+            "properties": {
+                "username": {
+                    "type": "string",
+                    "maxLength": 20,
+                    "minLength": 3,
+                    "x-order": "0"
+                },
+                "email": {
+                    "type": "string",
+                    "x-order": "1"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 150,
+                    "minLength": 3,
+                    "x-order": "2"
+                },
+                "bio": {
+                    "type": "string",
+                    "maxLength": 700,
+                    "x-order": "3"
+                },
+                "gender": {
+                    "type": "string",
+                    "enum": [
+                        "MALE",
+                        "FEMALE"
+                    ],
+                    "x-order": "5"
+                },
+                "age": {
+                    "type": "integer",
+                    "minimum": 0
+                }
+            }
+        },
+        "request.RefundCreate": {
+            "type": "object",
+            "required": [
+                "reason"
+            ],
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/request.RefundItemCreate"
+                    }
+                },
+                "reason": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "restock": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "request.RefundItemCreate": {
+            "type": "object",
+            "required": [
+                "order_id",
+                "quantity"
+            ],
+            "properties": {
+                "amount": {
+                    "type": "integer"
+                },
+                "order_id": {
+                    "type": "integer"
+                },
+                "quantity": {
+                    "type": "integer"
+                }
+            }
+        },
+        "request.RegisterRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "password",
+                "username"
+            ],
+            "properties": {
+                "username": {
+                    "type": "string",
+                    "maxLength": 20,
+                    "minLength": 3,
+                    "x-order": "0"
+                },
+                "email": {
+                    "type": "string",
+                    "x-order": "1"
+                },
+                "password": {
+                    "type": "string",
+                    "minLength": 8,
+                    "x-order": "2",
+                    "example": "password"
+                }
+            }
+        },
+        "request.ResetPasswordRequest": {
+            "type": "object",
+            "required": [
+                "new_password"
+            ],
+            "properties": {
+                "new_password": {
+                    "type": "string",
+                    "minLength": 8,
+                    "x-order": "0",
+                    "example": "new_password"
+                }
+            }
+        },
+        "request.TransactionCreate": {
+            "type": "object",
+            "required": [
+                "quantity",
+                "variant_id"
+            ],
+            "properties": {
+                "quantity": {
+                    "type": "integer"
+                },
+                "variant_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "request.TransactionStatusUpdate": {
+            "type": "object",
+            "required": [
+                "status"
+            ],
+            "properties": {
+                "reason": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "processing",
+                        "shipped",
+                        "delivered",
+                        "completed",
+                        "cancelled"
+                    ]
+                }
+            }
+        },
+        "request.TransactionUpdate": {
+            "type": "object",
+            "required": [
+                "id",
+                "quantity",
+                "variant_id"
+            ],
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "variant_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "request.UpdateBikeRequest": {
+            "type": "object",
+            "properties": {
+                "brand": {
+                    "type": "string"
+                },
+                "brand_id": {
+                    "type": "integer"
+                },
+                "category_id": {
+                    "type": "integer"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "image_url": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "integer"
+                },
+                "specs": {
+                    "description": "Specs sets the given specs, a null value removes one",
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "year": {
+                    "type": "integer"
+                }
+            }
+        },
+        "request.UpdateBikeVariantRequest": {
+            "type": "object",
+            "properties": {
+                "auto_availability": {
+                    "type": "boolean"
+                },
+                "clear_price": {
+                    "description": "ClearPrice drops the variant's own price, so it sells at the bike's price again",
+                    "type": "boolean"
+                },
+                "color": {
+                    "type": "string",
+                    "maxLength": 30
+                },
+                "frame_size": {
+                    "type": "string",
+                    "maxLength": 10
+                },
+                "is_available": {
+                    "type": "boolean"
+                },
+                "low_stock_threshold": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "price": {
+                    "type": "integer"
+                },
+                "sku": {
+                    "type": "string",
+                    "maxLength": 50
+                }
+            }
+        },
+        "request.UpdateBrandRequest": {
+            "type": "object",
+            "properties": {
+                "country": {
+                    "type": "string",
+                    "maxLength": 50
+                },
+                "description": {
+                    "type": "string",
+                    "maxLength": 1000
+                },
+                "logo_url": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 20
+                },
+                "slug": {
+                    "type": "string",
+                    "maxLength": 30
+                }
+            }
+        },
+        "request.UpdateCategoryRequest": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string",
+                    "maxLength": 500
+                },
+                "image_url": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 50
+                },
+                "parent_id": {
+                    "type": "integer"
+                },
+                "slug": {
+                    "type": "string",
+                    "maxLength": 60
+                }
+            }
+        },
+        "request.UpdateDiscountRequest": {
+            "type": "object",
+            "properties": {
+                "ends_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "starts_at": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "integer"
+                }
+            }
+        },
+        "request.UpdateReviewRequest": {
+            "type": "object",
+            "required": [
+                "comment",
+                "rating"
+            ],
+            "properties": {
+                "comment": {
+                    "type": "string"
+                },
+                "rating": {
+                    "type": "integer",
+                    "maximum": 5,
+                    "minimum": 1
+                }
+            }
+        },
+        "request.UpdateRoleRequest": {
+            "type": "object",
+            "required": [
+                "role",
+                "user_id"
+            ],
+            "properties": {
+                "role": {
+                    "type": "integer",
+                    "maximum": 2,
+                    "minimum": 1
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "request.UpdateSpecAttributeRequest": {
+            "type": "object",
+            "required": [
+                "options"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "maxLength": 50
+                },
+                "options": {
+                    "type": "array",
+                    "uniqueItems": true,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "unit": {
+                    "type": "string",
+                    "maxLength": 20
+                }
+            }
+        },
+        "response.BikeComparisonAttributeResponse": {
+            "type": "object",
+            "properties": {
+                "differs": {
+                    "type": "boolean"
+                },
+                "key": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "unit": {
+                    "type": "string"
+                },
+                "values": {
+                    "type": "array",
+                    "items": {}
+                }
+            }
+        },
+        "response.BikeComparisonBikeResponse": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "image_url": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "response.BikeComparisonResponse": {
+            "type": "object",
+            "properties": {
+                "attributes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.BikeComparisonAttributeResponse"
+                    }
+                },
+                "bikes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.BikeComparisonBikeResponse"
+                    }
+                }
+            }
+        },
+        "response.BikeImageResponse": {
+            "type": "object",
+            "properties": {
+                "bike_id": {
+                    "type": "integer"
+                },
+                "content_type": {
+                    "type": "string"
+                },
+                "height": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "is_primary": {
+                    "type": "boolean"
+                },
+                "position": {
+                    "type": "integer"
+                },
+                "size": {
+                    "type": "integer"
+                },
+                "thumbnail_url": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                },
+                "width": {
+                    "type": "integer"
+                }
+            }
+        },
+        "response.BikeImportResponse": {
+            "type": "object",
+            "properties": {
+                "created": {
+                    "type": "integer"
+                },
+                "dry_run": {
+                    "type": "boolean"
+                },
+                "failed": {
+                    "type": "integer"
+                },
+                "rows": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.BikeImportRowResponse"
+                    }
+                },
+                "updated": {
+                    "type": "integer"
+                }
+            }
+        },
+        "response.BikeImportRowResponse": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "bike_id": {
+                    "type": "integer"
+                },
+                "errors": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "row": {
+                    "type": "integer"
+                }
+            }
+        },
+        "response.BikeRatingHistogramResponse": {
+            "type": "object",
+            "properties": {
+                "1": {
+                    "type": "integer"
+                },
+                "2": {
+                    "type": "integer"
+                },
+                "3": {
+                    "type": "integer"
+                },
+                "4": {
+                    "type": "integer"
+                },
+                "5": {
+                    "type": "integer"
+                }
+            }
+        },
+        "response.BikeResponse": {
+            "type": "object",
+            "properties": {
+                "archived_at": {
+                    "type": "string"
+                },
+                "average_rating": {
+                    "type": "number"
+                },
+                "brand": {
+                    "type": "string"
+                },
+                "brand_id": {
+                    "type": "integer"
+                },
+                "category_id": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "discount": {
+                    "$ref": "#/definitions/response.DiscountResponse"
+                },
+                "discounts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.DiscountResponse"
+                    }
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "image_url": {
+                    "type": "string"
+                },
+                "images": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.BikeImageResponse"
+                    }
+                },
+                "is_available": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "integer"
+                },
+                "price_history": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.PriceHistoryResponse"
+                    }
+                },
+                "rating": {
+                    "type": "integer"
+                },
+                "rating_histogram": {
+                    "$ref": "#/definitions/response.BikeRatingHistogramResponse"
+                },
+                "reviewers": {
+                    "type": "integer"
+                },
+                "sale_price": {
+                    "type": "integer"
+                },
+                "specs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.BikeSpecResponse"
+                    }
+                },
+                "stock": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "variants": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.BikeVariantResponse"
+                    }
+                },
+                "year": {
+                    "type": "integer"
+                }
+            }
+        },
+        "response.BikeSpecResponse": {
+            "type": "object",
+            "properties": {
+                "key": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "unit": {
+                    "type": "string"
+                },
+                "value": {}
+            }
+        },
+        "response.BikeSuggestionResponse": {
+            "type": "object",
+            "properties": {
+                "highlights": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.HighlightResponse"
+                    }
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "score": {
+                    "type": "number"
+                },
+                "slug": {
+                    "type": "string"
+                },
+                "text": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "response.BikeVariantResponse": {
+            "type": "object",
+            "properties": {
+                "auto_availability": {
+                    "type": "boolean"
+                },
+                "bike_id": {
+                    "type": "integer"
+                },
+                "color": {
+                    "type": "string"
+                },
+                "discount_id": {
+                    "type": "integer"
+                },
+                "frame_size": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "is_available": {
+                    "type": "boolean"
+                },
+                "low_stock_threshold": {
+                    "type": "integer"
+                },
+                "price": {
+                    "type": "integer"
+                },
+                "price_override": {
+                    "type": "integer"
+                },
+                "sale_price": {
+                    "type": "integer"
+                },
+                "sku": {
+                    "type": "string"
+                },
+                "stock": {
+                    "type": "integer"
+                }
+            }
+        },
+        "response.BrandResponse": {
+            "type": "object",
+            "properties": {
+                "bike_count": {
+                    "type": "integer"
+                },
+                "country": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "logo_url": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "slug": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "response.CartItemResponse": {
+            "type": "object",
+            "properties": {
+                "bike_id": {
+                    "type": "integer"
+                },
+                "cart_id": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "price": {
+                    "type": "number"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "variant_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "response.CartResponse": {
+            "type": "object",
+            "properties": {
+                "cart_items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.CartItemResponse"
+                    }
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "response.CategoryResponse": {
+            "type": "object",
+            "properties": {
+                "archivedAt": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "imageUrl": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "parentID": {
+                    "type": "integer"
+                },
+                "slug": {
+                    "type": "string"
+                }
+            }
+        },
+        "response.CategoryTreeResponse": {
+            "type": "object",
+            "properties": {
+                "bike_count": {
+                    "type": "integer"
+                },
+                "children": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.CategoryTreeResponse"
+                    }
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "image_url": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "parent_id": {
+                    "type": "integer"
+                },
+                "slug": {
+                    "type": "string"
+                },
+                "total_bike_count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "response.CreateTransactionResponse": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "type": "string"
+                },
+                "payment_link": {
+                    "type": "string"
+                },
+                "total_price": {
+                    "type": "integer"
+                },
+                "transaction_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "response.DiscountResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "ends_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "starts_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "target_id": {
+                    "type": "integer"
+                },
+                "target_type": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "integer"
+                }
+            }
+        },
+        "response.ForgotPasswordResponse": {
+            "type": "object",
+            "properties": {
+                "forgot_password_token": {
+                    "type": "string",
+                    "example": "token"
+                }
+            }
+        },
+        "response.GetUserCartItemBikeResponse": {
+            "type": "object",
+            "properties": {
+                "brand": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "image_url": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "integer"
+                },
+                "stock": {
+                    "type": "integer"
+                }
+            }
+        },
+        "response.GetUserCartItemResponse": {
+            "type": "object",
+            "properties": {
+                "bike": {
+                    "$ref": "#/definitions/response.GetUserCartItemBikeResponse"
+                },
+                "cart_id": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "price": {
+                    "type": "number"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "variant": {
+                    "$ref": "#/definitions/response.BikeVariantResponse"
+                }
+            }
+        },
+        "response.GetUserCartResponse": {
+            "type": "object",
+            "properties": {
+                "cart_items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.GetUserCartItemResponse"
+                    }
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "response.GetUserCurrentResponse": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer",
+                    "x-order": "0",
+                    "example": 1
+                },
+                "username": {
+                    "type": "string",
+                    "x-order": "2",
+                    "example": "luigi"
+                },
+                "email": {
+                    "type": "string",
+                    "x-order": "3",
+                    "example": "luigi@sam.com"
+                },
+                "role": {
+                    "type": "string",
+                    "x-order": "4",
+                    "example": "USER"
+                }
+            }
+        },
+        "response.HighlightResponse": {
+            "type": "object",
+            "properties": {
+                "end": {
+                    "type": "integer"
+                },
+                "start": {
+                    "type": "integer"
+                }
+            }
+        },
+        "response.LoginResponse": {
+            "type": "object",
+            "properties": {
+                "username": {
+                    "type": "string",
+                    "x-order": "0",
+                    "example": "luigi"
+                },
+                "email": {
+                    "type": "string",
+                    "x-order": "1",
+                    "example": "luigi@sam.com"
+                },
+                "role": {
+                    "type": "string",
+                    "x-order": "2",
+                    "example": "USER"
+                },
+                "token": {
+                    "type": "string",
+                    "x-order": "3",
+                    "example": "token"
+                }
+            }
+        },
+        "response.OrderResponse": {
+            "type": "object",
+            "properties": {
+                "bike_brand": {
+                    "type": "string"
+                },
+                "bike_id": {
+                    "type": "integer"
+                },
+                "bike_name": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "sku": {
+                    "type": "string"
+                },
+                "total_price": {
+                    "type": "integer"
+                },
+                "unit_price": {
+                    "type": "integer"
+                },
+                "variant_id": {
+                    "type": "integer"
+                },
+                "variant_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "response.PriceHistoryResponse": {
+            "type": "object",
+            "properties": {
+                "actor_id": {
+                    "type": "integer"
+                },
+                "bike_variant_id": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "new_price": {
+                    "type": "integer"
+                },
+                "old_price": {
+                    "type": "integer"
+                },
+                "source": {
+                    "type": "string"
+                }
+            }
+        },
+        "response.ProfileResponse": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer",
+                    "x-order": "0",
+                    "example": 1
+                },
+                "username": {
+                    "type": "string",
+                    "x-order": "1",
+                    "example": "luigi"
+                },
+                "email": {
+                    "type": "string",
+                    "x-order": "2",
+                    "example": "luigi@sam.com"
+                },
+                "name": {
+                    "type": "string",
+                    "x-order": "3",
+                    "example": "Luigi Di Caprio"
+                },
+                "bio": {
+                    "type": "string",
+                    "x-order": "4",
+                    "example": "I am Luigi"
+                },
+                "age": {
+                    "type": "integer",
+                    "x-order": "5",
+                    "example": 18
+                },
+                "gender": {
+                    "type": "string",
+                    "x-order": "6",
+                    "example": "MALE"
+                }
+            }
+        },
+        "response.RecomputeBikeRatingsResponse": {
+            "type": "object",
+            "properties": {
+                "corrected": {
+                    "type": "integer"
+                }
+            }
+        },
+        "response.RecomputeRelatedBikesResponse": {
+            "type": "object",
+            "properties": {
+                "relations": {
+                    "type": "integer"
+                }
+            }
         },
-        "request.ForgotPasswordRequest": {
+        "response.RefundItemResponse": {
             "type": "object",
-            "required": [
-                "email",
-                "username"
-            ],
             "properties": {
-                "username": {
-                    "type": "string",
-                    "maxLength": 20,
-                    "minLength": 3,
-                    "x-order": "0"
+                "amount": {
+                    "type": "integer"
                 },
-                "email": {
-                    "type": "string"
+                "order_id": {
+                    "type": "integer"
+                },
+                "quantity": {
+                    "type": "integer"
                 }
             }
         },
-        "request.LoginRequest": {
+        "response.RefundResponse": {
             "type": "object",
-            "required": [
-                "email",
-                "password"
-            ],
             "properties": {
-                "email": {
-                    "type": "string",
-                    "x-order": "0"
+                "amount": {
+                    "type": "integer"
                 },
-                "password": {
-                    "type": "string",
-                    "x-order": "1",
-                    "example": "password"
+                "created_at": {
+                    "type": "string"
+                },
+                "gateway_reference": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.RefundItemResponse"
+                    }
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "restock": {
+                    "type": "boolean"
+                },
+                "status": {
+                    "type": "string"
                 }
             }
         },
-        "request.ProfileUpdateRequest": {
+        "response.RegisterResponse": {
             "type": "object",
             "properties": {
                 "username": {
                     "type": "string",
-                    "maxLength": 20,
-                    "minLength": 3,
-                    "x-order": "0"
+                    "x-order": "0",
+                    "example": "luigi"
                 },
                 "email": {
                     "type": "string",
-                    "x-order": "1"
+                    "x-order": "1",
+                    "example": "luigi@sam.com"
                 },
-                "name": {
+                "role": {
                     "type": "string",
-                    "maxLength": 150,
-                    "minLength": 3,
-                    "x-order": "2"
+                    "x-order": "2",
+                    "example": "USER"
+                }
+            }
+        },
+        "response.RelatedBikeResponse": {
+            "type": "object",
+            "properties": {
+                "archived_at": {
+                    "type": "string"
                 },
-                "bio": {
-                    "type": "string",
-                    "maxLength": 700,
-                    "x-order": "3"
+                "average_rating": {
+                    "type": "number"
                 },
-                "gender": {
-                    "type": "string",
-                    "enum": [
-                        "MALE",
-                        "FEMALE"
-                    ],
-                    "x-order": "5"
+                "bought_together": {
+                    "type": "integer"
                 },
-                "age": {
-                    "type": "integer",
-                    "minimum": 0
+                "brand": {
+                    "type": "string"
+                },
+                "brand_id": {
+                    "type": "integer"
+                },
+                "category_id": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "discount": {
+                    "$ref": "#/definitions/response.DiscountResponse"
+                },
+                "discounts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.DiscountResponse"
+                    }
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "image_url": {
+                    "type": "string"
+                },
+                "images": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.BikeImageResponse"
+                    }
+                },
+                "is_available": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "integer"
+                },
+                "price_history": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.PriceHistoryResponse"
+                    }
+                },
+                "rating": {
+                    "type": "integer"
+                },
+                "rating_histogram": {
+                    "$ref": "#/definitions/response.BikeRatingHistogramResponse"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "reviewers": {
+                    "type": "integer"
+                },
+                "sale_price": {
+                    "type": "integer"
+                },
+                "specs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.BikeSpecResponse"
+                    }
+                },
+                "stock": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "variants": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.BikeVariantResponse"
+                    }
+                },
+                "year": {
+                    "type": "integer"
                 }
             }
         },
-        "request.RegisterRequest": {
+        "response.ReviewResponse": {
             "type": "object",
-            "required": [
-                "email",
-                "password",
-                "username"
-            ],
             "properties": {
-                "username": {
-                    "type": "string",
-                    "maxLength": 20,
-                    "minLength": 3,
-                    "x-order": "0"
+                "bike_id": {
+                    "type": "integer"
                 },
-                "email": {
-                    "type": "string",
-                    "x-order": "1"
+                "comment": {
+                    "type": "string"
                 },
-                "password": {
-                    "type": "string",
-                    "minLength": 8,
-                    "x-order": "2",
-                    "example": "password"
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "rating": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
                 }
             }
         },
-        "request.ResetPasswordRequest": {
+        "response.RoleResponse": {
             "type": "object",
-            "required": [
-                "new_password"
-            ],
             "properties": {
-                "new_password": {
-                    "type": "string",
-                    "minLength": 8,
-                    "x-order": "0",
-                    "example": "new_password"
+                "id": {
+                    "type": "integer"
+                },
+                "role": {
+                    "type": "integer"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "response.SpecAttributeResponse": {
+            "type": "object",
+            "properties": {
+                "category_id": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "key": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "options": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "type": {
+                    "type": "string"
+                },
+                "unit": {
+                    "type": "string"
+                }
+            }
+        },
+        "response.StockAlertResponse": {
+            "type": "object",
+            "properties": {
+                "acknowledged_at": {
+                    "type": "string"
+                },
+                "acknowledged_by": {
+                    "type": "integer"
+                },
+                "bike_id": {
+                    "type": "integer"
+                },
+                "bike_name": {
+                    "type": "string"
+                },
+                "bike_variant_id": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "notified_at": {
+                    "type": "string"
+                },
+                "resolved_at": {
+                    "type": "string"
+                },
+                "sku": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "stock": {
+                    "type": "integer"
+                },
+                "threshold": {
+                    "type": "integer"
+                },
+                "type": {
+                    "type": "string"
                 }
             }
         },
-        "request.TransactionCreate": {
+        "response.StockDiscrepancyResponse": {
             "type": "object",
             "properties": {
                 "bike_id": {
                     "type": "integer"
                 },
-                "quantity": {
+                "bike_variant_id": {
                     "type": "integer"
                 },
-                "total_price": {
+                "ledger_stock": {
+                    "type": "integer"
+                },
+                "sku": {
+                    "type": "string"
+                },
+                "stock": {
                     "type": "integer"
                 }
             }
         },
-        "request.TransactionUpdate": {
+        "response.StockMovementResponse": {
             "type": "object",
             "properties": {
+                "actor_id": {
+                    "type": "integer"
+                },
                 "bike_id": {
                     "type": "integer"
                 },
+                "bike_variant_id": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
                 "id": {
                     "type": "integer"
                 },
+                "note": {
+                    "type": "string"
+                },
                 "quantity": {
                     "type": "integer"
                 },
-                "total_price": {
-                    "type": "integer"
+                "reference": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
                 }
             }
         },
-        "request.UpdateBikeRequest": {
+        "response.TransactionResponse": {
             "type": "object",
             "properties": {
-                "brand": {
+                "created_at": {
                     "type": "string"
                 },
-                "category_id": {
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
                     "type": "integer"
                 },
-                "description": {
-                    "type": "string"
+                "orders": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.OrderResponse"
+                    }
                 },
-                "image_url": {
+                "payment_link": {
                     "type": "string"
                 },
-                "is_available": {
-                    "type": "boolean"
+                "refunds": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.RefundResponse"
+                    }
                 },
-                "name": {
+                "status": {
                     "type": "string"
                 },
-                "price": {
-                    "type": "integer"
+                "status_histories": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.TransactionStatusHistoryResponse"
+                    }
                 },
-                "stock": {
+                "total_price": {
                     "type": "integer"
                 },
-                "year": {
+                "upodated_at": {
+                    "type": "string"
+                },
+                "user_id": {
                     "type": "integer"
                 }
             }
         },
-        "request.UpdateCategoryRequest": {
+        "response.TransactionStatusHistoryResponse": {
             "type": "object",
-            "required": [
-                "name"
-            ],
             "properties": {
-                "name": {
+                "actor_id": {
+                    "type": "integer"
+                },
+                "actor_type": {
                     "type": "string"
-                }
-            }
-        },
-        "request.UpdateReviewRequest": {
-            "type": "object",
-            "required": [
-                "comment",
-                "rating"
-            ],
-            "properties": {
-                "comment": {
+                },
+                "created_at": {
                     "type": "string"
                 },
-                "rating": {
-                    "type": "integer",
-                    "maximum": 5,
-                    "minimum": 1
+                "from_status": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "to_status": {
+                    "type": "string"
                 }
             }
         },
-        "request.UpdateRoleRequest": {
+        "response.UserResponse": {
             "type": "object",
-            "required": [
-                "role",
-                "user_id"
-            ],
             "properties": {
+                "id": {
+                    "type": "integer"
+                },
                 "role": {
-                    "type": "integer",
-                    "maximum": 2,
-                    "minimum": 1
+                    "type": "string"
                 },
-                "user_id": {
-                    "type": "integer"
+                "username": {
+                    "type": "string"
                 }
             }
         },
-        "response.BikeResponse": {
+        "response.UserTransactionResponse": {
             "type": "object",
             "properties": {
-                "brand": {
-                    "type": "string"
-                },
-                "category_id": {
-                    "type": "integer"
-                },
-                "created_at": {
-                    "type": "string"
-                },
-                "description": {
-                    "type": "string"
-                },
                 "id": {
                     "type": "integer"
                 },
-                "image_url": {
-                    "type": "string"
-                },
-                "is_available": {
-                    "type": "boolean"
+                "transaction": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.TransactionResponse"
+                    }
                 },
-                "name": {
+                "username": {
                     "type": "string"
+                }
+            }
+        },
+        "web.Metadata": {
+            "type": "object",
+            "properties": {
+                "page": {
+                    "type": "integer",
+                    "x-order": "0"
                 },
-                "price": {
-                    "type": "integer"
-                },
-                "rating": {
-                    "type": "integer"
+                "limit": {
+                    "type": "integer",
+                    "x-order": "1"
                 },
-                "reviewers": {
-                    "type": "integer"
+                "total_pages": {
+                    "type": "integer",
+                    "x-order": "2"
                 },
-                "stock": {
-                    "type": "integer"
+                "total_data": {
+                    "type": "integer",
+                    "x-order": "3"
                 },
-                "updated_at": {
-                    "type": "string"
+                "facets": {
+                    "x-order": "4"
+                }
+            }
+        },
+        "web.WebBadRequestError": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "example": 400
                 },
-                "year": {
-                    "type": "integer"
+                "errors": {
+                    "type": "string",
+                    "example": "Bad Request"
                 }
             }
         },
-        "response.CartItemResponse": {
+        "web.WebError": {
             "type": "object",
             "properties": {
-                "bike_id": {
+                "code": {
                     "type": "integer"
                 },
-                "cart_id": {
-                    "type": "integer"
+                "errors": {}
+            }
+        },
+        "web.WebForbiddenError": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "example": 403
                 },
-                "created_at": {
-                    "type": "string"
+                "errors": {
+                    "type": "string",
+                    "example": "Forbidden"
+                }
+            }
+        },
+        "web.WebInternalServerError": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "example": 500
                 },
-                "id": {
-                    "type": "integer"
+                "errors": {
+                    "type": "string",
+                    "example": "Internal Server Error"
+                }
+            }
+        },
+        "web.WebNotFoundError": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "example": 404
                 },
-                "price": {
-                    "type": "number"
+                "errors": {
+                    "type": "string",
+                    "example": "Not Found"
+                }
+            }
+        },
+        "web.WebSuccess-array_response_BikeImageResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "x-order": "0",
+                    "example": 200
                 },
-                "quantity": {
-                    "type": "integer"
+                "message": {
+                    "type": "string",
+                    "x-order": "1",
+                    "example": "success"
                 },
-                "updated_at": {
-                    "type": "string"
+                "payload": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.BikeImageResponse"
+                    },
+                    "x-order": "2"
+                },
+                "metadata": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/web.Metadata"
+                        }
+                    ],
+                    "x-order": "3"
                 }
             }
         },
-        "response.CartResponse": {
+        "web.WebSuccess-array_response_BikeResponse": {
             "type": "object",
             "properties": {
-                "cart_items": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/response.CartItemResponse"
-                    }
-                },
-                "created_at": {
-                    "type": "string"
+                "code": {
+                    "type": "integer",
+                    "x-order": "0",
+                    "example": 200
                 },
-                "id": {
-                    "type": "integer"
+                "message": {
+                    "type": "string",
+                    "x-order": "1",
+                    "example": "success"
                 },
-                "updated_at": {
-                    "type": "string"
+                "payload": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.BikeResponse"
+                    },
+                    "x-order": "2"
                 },
-                "user_id": {
-                    "type": "integer"
+                "metadata": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/web.Metadata"
+                        }
+                    ],
+                    "x-order": "3"
                 }
             }
         },
-        "response.CategoryResponse": {
+        "web.WebSuccess-array_response_BikeSuggestionResponse": {
             "type": "object",
             "properties": {
-                "id": {
-                    "type": "integer"
+                "code": {
+                    "type": "integer",
+                    "x-order": "0",
+                    "example": 200
                 },
-                "name": {
-                    "type": "string"
+                "message": {
+                    "type": "string",
+                    "x-order": "1",
+                    "example": "success"
+                },
+                "payload": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.BikeSuggestionResponse"
+                    },
+                    "x-order": "2"
+                },
+                "metadata": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/web.Metadata"
+                        }
+                    ],
+                    "x-order": "3"
                 }
             }
         },
-        "response.CreateTransactionResponse": {
+        "web.WebSuccess-array_response_BrandResponse": {
             "type": "object",
             "properties": {
-                "transaction_id": {
-                    "type": "integer"
+                "code": {
+                    "type": "integer",
+                    "x-order": "0",
+                    "example": 200
+                },
+                "message": {
+                    "type": "string",
+                    "x-order": "1",
+                    "example": "success"
+                },
+                "payload": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.BrandResponse"
+                    },
+                    "x-order": "2"
+                },
+                "metadata": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/web.Metadata"
+                        }
+                    ],
+                    "x-order": "3"
                 }
             }
         },
-        "response.ForgotPasswordResponse": {
+        "web.WebSuccess-array_response_CategoryResponse": {
             "type": "object",
             "properties": {
-                "forgot_password_token": {
+                "code": {
+                    "type": "integer",
+                    "x-order": "0",
+                    "example": 200
+                },
+                "message": {
                     "type": "string",
-                    "example": "token"
+                    "x-order": "1",
+                    "example": "success"
+                },
+                "payload": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.CategoryResponse"
+                    },
+                    "x-order": "2"
+                },
+                "metadata": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/web.Metadata"
+                        }
+                    ],
+                    "x-order": "3"
                 }
             }
         },
-        "response.GetUserCartItemBikeResponse": {
+        "web.WebSuccess-array_response_CategoryTreeResponse": {
             "type": "object",
             "properties": {
-                "brand": {
-                    "type": "string"
-                },
-                "description": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "integer"
-                },
-                "image_url": {
-                    "type": "string"
+                "code": {
+                    "type": "integer",
+                    "x-order": "0",
+                    "example": 200
                 },
-                "name": {
-                    "type": "string"
+                "message": {
+                    "type": "string",
+                    "x-order": "1",
+                    "example": "success"
                 },
-                "price": {
-                    "type": "integer"
+                "payload": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.CategoryTreeResponse"
+                    },
+                    "x-order": "2"
                 },
-                "stock": {
-                    "type": "integer"
+                "metadata": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/web.Metadata"
+                        }
+                    ],
+                    "x-order": "3"
                 }
             }
         },
-        "response.GetUserCartItemResponse": {
+        "web.WebSuccess-array_response_DiscountResponse": {
             "type": "object",
             "properties": {
-                "bike": {
-                    "$ref": "#/definitions/response.GetUserCartItemBikeResponse"
-                },
-                "cart_id": {
-                    "type": "integer"
-                },
-                "created_at": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "integer"
+                "code": {
+                    "type": "integer",
+                    "x-order": "0",
+                    "example": 200
                 },
-                "price": {
-                    "type": "number"
+                "message": {
+                    "type": "string",
+                    "x-order": "1",
+                    "example": "success"
                 },
-                "quantity": {
-                    "type": "integer"
+                "payload": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.DiscountResponse"
+                    },
+                    "x-order": "2"
                 },
-                "updated_at": {
-                    "type": "string"
+                "metadata": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/web.Metadata"
+                        }
+                    ],
+                    "x-order": "3"
                 }
             }
         },
-        "response.GetUserCartResponse": {
+        "web.WebSuccess-array_response_RelatedBikeResponse": {
             "type": "object",
             "properties": {
-                "cart_items": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/response.GetUserCartItemResponse"
-                    }
-                },
-                "created_at": {
-                    "type": "string"
+                "code": {
+                    "type": "integer",
+                    "x-order": "0",
+                    "example": 200
                 },
-                "id": {
-                    "type": "integer"
+                "message": {
+                    "type": "string",
+                    "x-order": "1",
+                    "example": "success"
                 },
-                "updated_at": {
-                    "type": "string"
+                "payload": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.RelatedBikeResponse"
+                    },
+                    "x-order": "2"
                 },
-                "user_id": {
-                    "type": "integer"
+                "metadata": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/web.Metadata"
+                        }
+                    ],
+                    "x-order": "3"
                 }
             }
         },
-        "response.GetUserCurrentResponse": {
+        "web.WebSuccess-array_response_ReviewResponse": {
             "type": "object",
             "properties": {
-                "id": {
+                "code": {
                     "type": "integer",
                     "x-order": "0",
-                    "example": 1
+                    "example": 200
                 },
-                "username": {
+                "message": {
                     "type": "string",
-                    "x-order": "2",
-                    "example": "luigi"
+                    "x-order": "1",
+                    "example": "success"
                 },
-                "email": {
-                    "type": "string",
-                    "x-order": "3",
-                    "example": "luigi@sam.com"
+                "payload": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.ReviewResponse"
+                    },
+                    "x-order": "2"
                 },
-                "role": {
-                    "type": "string",
-                    "x-order": "4",
-                    "example": "USER"
+                "metadata": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/web.Metadata"
+                        }
+                    ],
+                    "x-order": "3"
                 }
             }
         },
-        "response.LoginResponse": {
+        "web.WebSuccess-array_response_SpecAttributeResponse": {
             "type": "object",
             "properties": {
-                "username": {
-                    "type": "string",
+                "code": {
+                    "type": "integer",
                     "x-order": "0",
-                    "example": "luigi"
+                    "example": 200
                 },
-                "email": {
+                "message": {
                     "type": "string",
                     "x-order": "1",
-                    "example": "luigi@sam.com"
+                    "example": "success"
                 },
-                "role": {
-                    "type": "string",
-                    "x-order": "2",
-                    "example": "USER"
+                "payload": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.SpecAttributeResponse"
+                    },
+                    "x-order": "2"
                 },
-                "token": {
-                    "type": "string",
-                    "x-order": "3",
-                    "example": "token"
+                "metadata": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/web.Metadata"
+                        }
+                    ],
+                    "x-order": "3"
                 }
             }
         },
-        "response.OrderResponse": {
+        "web.WebSuccess-array_response_StockAlertResponse": {
             "type": "object",
             "properties": {
-                "bikeID": {
-                    "type": "integer"
+                "code": {
+                    "type": "integer",
+                    "x-order": "0",
+                    "example": 200
                 },
-                "id": {
-                    "type": "integer"
+                "message": {
+                    "type": "string",
+                    "x-order": "1",
+                    "example": "success"
                 },
-                "quantity": {
-                    "type": "integer"
+                "payload": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.StockAlertResponse"
+                    },
+                    "x-order": "2"
                 },
-                "totalPrice": {
-                    "type": "integer"
+                "metadata": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/web.Metadata"
+                        }
+                    ],
+                    "x-order": "3"
                 }
             }
         },
-        "response.ProfileResponse": {
+        "web.WebSuccess-array_response_StockDiscrepancyResponse": {
             "type": "object",
             "properties": {
-                "id": {
+                "code": {
                     "type": "integer",
                     "x-order": "0",
-                    "example": 1
+                    "example": 200
                 },
-                "username": {
+                "message": {
                     "type": "string",
                     "x-order": "1",
-                    "example": "luigi"
-                },
-                "email": {
-                    "type": "string",
-                    "x-order": "2",
-                    "example": "luigi@sam.com"
-                },
-                "name": {
-                    "type": "string",
-                    "x-order": "3",
-                    "example": "Luigi Di Caprio"
-                },
-                "bio": {
-                    "type": "string",
-                    "x-order": "4",
-                    "example": "I am Luigi"
+                    "example": "success"
                 },
-                "age": {
-                    "type": "integer",
-                    "x-order": "5",
-                    "example": 18
+                "payload": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.StockDiscrepancyResponse"
+                    },
+                    "x-order": "2"
                 },
-                "gender": {
-                    "type": "string",
-                    "x-order": "6",
-                    "example": "MALE"
+                "metadata": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/web.Metadata"
+                        }
+                    ],
+                    "x-order": "3"
                 }
             }
         },
-        "response.RegisterResponse": {
+        "web.WebSuccess-array_response_StockMovementResponse": {
             "type": "object",
             "properties": {
-                "username": {
-                    "type": "string",
+                "code": {
+                    "type": "integer",
                     "x-order": "0",
-                    "example": "luigi"
+                    "example": 200
                 },
-                "email": {
+                "message": {
                     "type": "string",
                     "x-order": "1",
-                    "example": "luigi@sam.com"
+                    "example": "success"
                 },
-                "role": {
-                    "type": "string",
-                    "x-order": "2",
-                    "example": "USER"
+                "payload": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.StockMovementResponse"
+                    },
+                    "x-order": "2"
+                },
+                "metadata": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/web.Metadata"
+                        }
+                    ],
+                    "x-order": "3"
                 }
             }
         },
-        "response.ReviewResponse": {
+        "web.WebSuccess-array_response_TransactionResponse": {
             "type": "object",
             "properties": {
-                "bike_id": {
-                    "type": "integer"
-                },
-                "comment": {
-                    "type": "string"
-                },
-                "created_at": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "integer"
+                "code": {
+                    "type": "integer",
+                    "x-order": "0",
+                    "example": 200
                 },
-                "rating": {
-                    "type": "integer"
+                "message": {
+                    "type": "string",
+                    "x-order": "1",
+                    "example": "success"
                 },
-                "updated_at": {
-                    "type": "string"
+                "payload": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.TransactionResponse"
+                    },
+                    "x-order": "2"
                 },
-                "user_id": {
-                    "type": "integer"
+                "metadata": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/web.Metadata"
+                        }
+                    ],
+                    "x-order": "3"
                 }
             }
         },
-        "response.RoleResponse": {
+        "web.WebSuccess-array_response_UserResponse": {
             "type": "object",
             "properties": {
-                "id": {
-                    "type": "integer"
+                "code": {
+                    "type": "integer",
+                    "x-order": "0",
+                    "example": 200
                 },
-                "role": {
-                    "type": "integer"
+                "message": {
+                    "type": "string",
+                    "x-order": "1",
+                    "example": "success"
                 },
-                "user_id": {
-                    "type": "integer"
+                "payload": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.UserResponse"
+                    },
+                    "x-order": "2"
+                },
+                "metadata": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/web.Metadata"
+                        }
+                    ],
+                    "x-order": "3"
                 }
             }
         },
-        "response.TransactionResponse": {
+        "web.WebSuccess-response_BikeComparisonResponse": {
             "type": "object",
             "properties": {
-                "created_at": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "integer"
-                },
-                "orders": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/response.OrderResponse"
-                    }
-                },
-                "payment_link": {
-                    "type": "string"
-                },
-                "status": {
-                    "type": "string"
+                "code": {
+                    "type": "integer",
+                    "x-order": "0",
+                    "example": 200
                 },
-                "total_price": {
-                    "type": "integer"
+                "message": {
+                    "type": "string",
+                    "x-order": "1",
+                    "example": "success"
                 },
-                "upodated_at": {
-                    "type": "string"
+                "payload": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/response.BikeComparisonResponse"
+                        }
+                    ],
+                    "x-order": "2"
                 },
-                "user_id": {
-                    "type": "integer"
+                "metadata": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/web.Metadata"
+                        }
+                    ],
+                    "x-order": "3"
                 }
             }
         },
-        "response.UserResponse": {
+        "web.WebSuccess-response_BikeImageResponse": {
             "type": "object",
             "properties": {
-                "id": {
-                    "type": "integer"
+                "code": {
+                    "type": "integer",
+                    "x-order": "0",
+                    "example": 200
                 },
-                "role": {
-                    "type": "string"
+                "message": {
+                    "type": "string",
+                    "x-order": "1",
+                    "example": "success"
                 },
-                "username": {
-                    "type": "string"
+                "payload": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/response.BikeImageResponse"
+                        }
+                    ],
+                    "x-order": "2"
+                },
+                "metadata": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/web.Metadata"
+                        }
+                    ],
+                    "x-order": "3"
                 }
             }
         },
-        "response.UserTransactionResponse": {
+        "web.WebSuccess-response_BikeImportResponse": {
             "type": "object",
             "properties": {
-                "id": {
-                    "type": "integer"
+                "code": {
+                    "type": "integer",
+                    "x-order": "0",
+                    "example": 200
                 },
-                "transaction": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/response.TransactionResponse"
-                    }
+                "message": {
+                    "type": "string",
+                    "x-order": "1",
+                    "example": "success"
                 },
-                "username": {
-                    "type": "string"
+                "payload": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/response.BikeImportResponse"
+                        }
+                    ],
+                    "x-order": "2"
+                },
+                "metadata": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/web.Metadata"
+                        }
+                    ],
+                    "x-order": "3"
                 }
             }
         },
-        "web.Metadata": {
+        "web.WebSuccess-response_BikeResponse": {
             "type": "object",
             "properties": {
-                "page": {
+                "code": {
                     "type": "integer",
-                    "x-order": "0"
+                    "x-order": "0",
+                    "example": 200
                 },
-                "limit": {
-                    "type": "integer",
-                    "x-order": "1"
+                "message": {
+                    "type": "string",
+                    "x-order": "1",
+                    "example": "success"
                 },
-                "total_pages": {
-                    "type": "integer",
+                "payload": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/response.BikeResponse"
+                        }
+                    ],
                     "x-order": "2"
                 },
-                "total_data": {
-                    "type": "integer",
+                "metadata": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/web.Metadata"
+                        }
+                    ],
                     "x-order": "3"
                 }
             }
         },
-        "web.WebBadRequestError": {
+        "web.WebSuccess-response_BikeVariantResponse": {
             "type": "object",
             "properties": {
                 "code": {
                     "type": "integer",
-                    "example": 400
+                    "x-order": "0",
+                    "example": 200
                 },
-                "errors": {
+                "message": {
                     "type": "string",
-                    "example": "Bad Request"
+                    "x-order": "1",
+                    "example": "success"
+                },
+                "payload": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/response.BikeVariantResponse"
+                        }
+                    ],
+                    "x-order": "2"
+                },
+                "metadata": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/web.Metadata"
+                        }
+                    ],
+                    "x-order": "3"
                 }
             }
         },
-        "web.WebInternalServerError": {
+        "web.WebSuccess-response_BrandResponse": {
             "type": "object",
             "properties": {
                 "code": {
                     "type": "integer",
-                    "example": 500
+                    "x-order": "0",
+                    "example": 200
                 },
-                "errors": {
+                "message": {
                     "type": "string",
-                    "example": "Internal Server Error"
+                    "x-order": "1",
+                    "example": "success"
+                },
+                "payload": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/response.BrandResponse"
+                        }
+                    ],
+                    "x-order": "2"
+                },
+                "metadata": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/web.Metadata"
+                        }
+                    ],
+                    "x-order": "3"
                 }
             }
         },
-        "web.WebNotFoundError": {
+        "web.WebSuccess-response_CartResponse": {
             "type": "object",
             "properties": {
                 "code": {
                     "type": "integer",
-                    "example": 404
+                    "x-order": "0",
+                    "example": 200
                 },
-                "errors": {
+                "message": {
                     "type": "string",
-                    "example": "Not Found"
+                    "x-order": "1",
+                    "example": "success"
+                },
+                "payload": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/response.CartResponse"
+                        }
+                    ],
+                    "x-order": "2"
+                },
+                "metadata": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/web.Metadata"
+                        }
+                    ],
+                    "x-order": "3"
                 }
             }
         },
-        "web.WebSuccess-array_response_BikeResponse": {
+        "web.WebSuccess-response_CategoryResponse": {
             "type": "object",
             "properties": {
                 "code": {
@@ -2854,10 +7818,11 @@ const docTemplate = `{
                     "example": "success"
                 },
                 "payload": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/response.BikeResponse"
-                    },
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/response.CategoryResponse"
+                        }
+                    ],
                     "x-order": "2"
                 },
                 "metadata": {
@@ -2870,7 +7835,7 @@ const docTemplate = `{
                 }
             }
         },
-        "web.WebSuccess-array_response_CategoryResponse": {
+        "web.WebSuccess-response_CreateTransactionResponse": {
             "type": "object",
             "properties": {
                 "code": {
@@ -2884,10 +7849,11 @@ const docTemplate = `{
                     "example": "success"
                 },
                 "payload": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/response.CategoryResponse"
-                    },
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/response.CreateTransactionResponse"
+                        }
+                    ],
                     "x-order": "2"
                 },
                 "metadata": {
@@ -2900,7 +7866,7 @@ const docTemplate = `{
                 }
             }
         },
-        "web.WebSuccess-array_response_ReviewResponse": {
+        "web.WebSuccess-response_DiscountResponse": {
             "type": "object",
             "properties": {
                 "code": {
@@ -2914,10 +7880,11 @@ const docTemplate = `{
                     "example": "success"
                 },
                 "payload": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/response.ReviewResponse"
-                    },
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/response.DiscountResponse"
+                        }
+                    ],
                     "x-order": "2"
                 },
                 "metadata": {
@@ -2930,7 +7897,7 @@ const docTemplate = `{
                 }
             }
         },
-        "web.WebSuccess-array_response_TransactionResponse": {
+        "web.WebSuccess-response_ForgotPasswordResponse": {
             "type": "object",
             "properties": {
                 "code": {
@@ -2944,10 +7911,11 @@ const docTemplate = `{
                     "example": "success"
                 },
                 "payload": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/response.TransactionResponse"
-                    },
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/response.ForgotPasswordResponse"
+                        }
+                    ],
                     "x-order": "2"
                 },
                 "metadata": {
@@ -2960,7 +7928,7 @@ const docTemplate = `{
                 }
             }
         },
-        "web.WebSuccess-array_response_UserResponse": {
+        "web.WebSuccess-response_GetUserCartResponse": {
             "type": "object",
             "properties": {
                 "code": {
@@ -2974,10 +7942,11 @@ const docTemplate = `{
                     "example": "success"
                 },
                 "payload": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/response.UserResponse"
-                    },
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/response.GetUserCartResponse"
+                        }
+                    ],
                     "x-order": "2"
                 },
                 "metadata": {
@@ -2990,7 +7959,7 @@ const docTemplate = `{
                 }
             }
         },
-        "web.WebSuccess-response_BikeResponse": {
+        "web.WebSuccess-response_GetUserCurrentResponse": {
             "type": "object",
             "properties": {
                 "code": {
@@ -3006,7 +7975,7 @@ const docTemplate = `{
                 "payload": {
                     "allOf": [
                         {
-                            "$ref": "#/definitions/response.BikeResponse"
+                            "$ref": "#/definitions/response.GetUserCurrentResponse"
                         }
                     ],
                     "x-order": "2"
@@ -3021,7 +7990,7 @@ const docTemplate = `{
                 }
             }
         },
-        "web.WebSuccess-response_CartResponse": {
+        "web.WebSuccess-response_LoginResponse": {
             "type": "object",
             "properties": {
                 "code": {
@@ -3037,7 +8006,7 @@ const docTemplate = `{
                 "payload": {
                     "allOf": [
                         {
-                            "$ref": "#/definitions/response.CartResponse"
+                            "$ref": "#/definitions/response.LoginResponse"
                         }
                     ],
                     "x-order": "2"
@@ -3052,7 +8021,7 @@ const docTemplate = `{
                 }
             }
         },
-        "web.WebSuccess-response_CategoryResponse": {
+        "web.WebSuccess-response_ProfileResponse": {
             "type": "object",
             "properties": {
                 "code": {
@@ -3068,7 +8037,7 @@ const docTemplate = `{
                 "payload": {
                     "allOf": [
                         {
-                            "$ref": "#/definitions/response.CategoryResponse"
+                            "$ref": "#/definitions/response.ProfileResponse"
                         }
                     ],
                     "x-order": "2"
@@ -3083,7 +8052,7 @@ const docTemplate = `{
                 }
             }
         },
-        "web.WebSuccess-response_CreateTransactionResponse": {
+        "web.WebSuccess-response_RecomputeBikeRatingsResponse": {
             "type": "object",
             "properties": {
                 "code": {
@@ -3099,7 +8068,7 @@ const docTemplate = `{
                 "payload": {
                     "allOf": [
                         {
-                            "$ref": "#/definitions/response.CreateTransactionResponse"
+                            "$ref": "#/definitions/response.RecomputeBikeRatingsResponse"
                         }
                     ],
                     "x-order": "2"
@@ -3114,7 +8083,7 @@ const docTemplate = `{
                 }
             }
         },
-        "web.WebSuccess-response_ForgotPasswordResponse": {
+        "web.WebSuccess-response_RecomputeRelatedBikesResponse": {
             "type": "object",
             "properties": {
                 "code": {
@@ -3130,7 +8099,7 @@ const docTemplate = `{
                 "payload": {
                     "allOf": [
                         {
-                            "$ref": "#/definitions/response.ForgotPasswordResponse"
+                            "$ref": "#/definitions/response.RecomputeRelatedBikesResponse"
                         }
                     ],
                     "x-order": "2"
@@ -3145,7 +8114,7 @@ const docTemplate = `{
                 }
             }
         },
-        "web.WebSuccess-response_GetUserCartResponse": {
+        "web.WebSuccess-response_RefundResponse": {
             "type": "object",
             "properties": {
                 "code": {
@@ -3161,7 +8130,7 @@ const docTemplate = `{
                 "payload": {
                     "allOf": [
                         {
-                            "$ref": "#/definitions/response.GetUserCartResponse"
+                            "$ref": "#/definitions/response.RefundResponse"
                         }
                     ],
                     "x-order": "2"
@@ -3176,7 +8145,7 @@ const docTemplate = `{
                 }
             }
         },
-        "web.WebSuccess-response_GetUserCurrentResponse": {
+        "web.WebSuccess-response_RegisterResponse": {
             "type": "object",
             "properties": {
                 "code": {
@@ -3192,7 +8161,7 @@ const docTemplate = `{
                 "payload": {
                     "allOf": [
                         {
-                            "$ref": "#/definitions/response.GetUserCurrentResponse"
+                            "$ref": "#/definitions/response.RegisterResponse"
                         }
                     ],
                     "x-order": "2"
@@ -3207,7 +8176,7 @@ const docTemplate = `{
                 }
             }
         },
-        "web.WebSuccess-response_LoginResponse": {
+        "web.WebSuccess-response_ReviewResponse": {
             "type": "object",
             "properties": {
                 "code": {
@@ -3223,7 +8192,7 @@ const docTemplate = `{
                 "payload": {
                     "allOf": [
                         {
-                            "$ref": "#/definitions/response.LoginResponse"
+                            "$ref": "#/definitions/response.ReviewResponse"
                         }
                     ],
                     "x-order": "2"
@@ -3238,7 +8207,7 @@ const docTemplate = `{
                 }
             }
         },
-        "web.WebSuccess-response_ProfileResponse": {
+        "web.WebSuccess-response_RoleResponse": {
             "type": "object",
             "properties": {
                 "code": {
@@ -3254,7 +8223,7 @@ const docTemplate = `{
                 "payload": {
                     "allOf": [
                         {
-                            "$ref": "#/definitions/response.ProfileResponse"
+                            "$ref": "#/definitions/response.RoleResponse"
                         }
                     ],
                     "x-order": "2"
@@ -3269,7 +8238,7 @@ const docTemplate = `{
                 }
             }
         },
-        "web.WebSuccess-response_RegisterResponse": {
+        "web.WebSuccess-response_SpecAttributeResponse": {
             "type": "object",
             "properties": {
                 "code": {
@@ -3285,7 +8254,7 @@ const docTemplate = `{
                 "payload": {
                     "allOf": [
                         {
-                            "$ref": "#/definitions/response.RegisterResponse"
+                            "$ref": "#/definitions/response.SpecAttributeResponse"
                         }
                     ],
                     "x-order": "2"
@@ -3300,7 +8269,7 @@ const docTemplate = `{
                 }
             }
         },
-        "web.WebSuccess-response_ReviewResponse": {
+        "web.WebSuccess-response_StockAlertResponse": {
             "type": "object",
             "properties": {
                 "code": {
@@ -3316,7 +8285,7 @@ const docTemplate = `{
                 "payload": {
                     "allOf": [
                         {
-                            "$ref": "#/definitions/response.ReviewResponse"
+                            "$ref": "#/definitions/response.StockAlertResponse"
                         }
                     ],
                     "x-order": "2"
@@ -3331,7 +8300,7 @@ const docTemplate = `{
                 }
             }
         },
-        "web.WebSuccess-response_RoleResponse": {
+        "web.WebSuccess-response_StockMovementResponse": {
             "type": "object",
             "properties": {
                 "code": {
@@ -3347,7 +8316,7 @@ const docTemplate = `{
                 "payload": {
                     "allOf": [
                         {
-                            "$ref": "#/definitions/response.RoleResponse"
+                            "$ref": "#/definitions/response.StockMovementResponse"
                         }
                     ],
                     "x-order": "2"
@@ -15,3 +15,22 @@ func NewCustomError(code int, errors string) *customError {
 		Errors: errors,
 	}
 }
+
+// detailedError is a customError that carries structured details, e.g. the items that failed a check.
+type detailedError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Details any    `json:"details"`
+}
+
+func (e *detailedError) Error() string {
+	return e.Message
+}
+
+func NewDetailedError(code int, message string, details any) *detailedError {
+	return &detailedError{
+		Code:    code,
+		Message: message,
+		Details: details,
+	}
+}
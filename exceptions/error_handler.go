@@ -22,6 +22,14 @@ func GlobalErrorHandler(c *gin.Context) {
 					Code:   e.Code,
 					Errors: e.Errors,
 				})
+			case *detailedError:
+				c.AbortWithStatusJSON(e.Code, &web.WebError{
+					Code: e.Code,
+					Errors: map[string]any{
+						"message": e.Message,
+						"details": e.Details,
+					},
+				})
 			case validator.ValidationErrors:
 				HandleValidationErrors(c, e)
 			default:
@@ -37,15 +45,20 @@ func GlobalErrorHandler(c *gin.Context) {
 }
 
 func HandleValidationErrors(c *gin.Context, err validator.ValidationErrors) {
+	c.AbortWithStatusJSON(http.StatusBadRequest, &web.WebError{
+		Code:   http.StatusBadRequest,
+		Errors: ValidationErrorMessages(err),
+	})
+}
+
+// ValidationErrorMessages describes every failed field the same way request validation errors are reported.
+func ValidationErrorMessages(err validator.ValidationErrors) map[string]string {
 	errors := make(map[string]string)
 	for _, fe := range err {
 		field := toSnakeCase(fe.Field())
 		errors[field] = "Field validation for '" + field + "' failed on the '" + fe.Tag() + "' tag"
 	}
-	c.AbortWithStatusJSON(http.StatusBadRequest, &web.WebError{
-		Code:   http.StatusBadRequest,
-		Errors: errors,
-	})
+	return errors
 }
 
 func toSnakeCase(s string) string {
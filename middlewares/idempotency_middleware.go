@@ -0,0 +1,134 @@
+package middlewares
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gowesmart/api-gowesmart/model/entity"
+	"github.com/gowesmart/api-gowesmart/model/web"
+	"github.com/gowesmart/api-gowesmart/utils"
+	"go.uber.org/zap"
+	"gorm.io/gorm/clause"
+)
+
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+type idempotencyResponseWriter struct {
+	gin.ResponseWriter
+	body *bytes.Buffer
+}
+
+func (w *idempotencyResponseWriter) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *idempotencyResponseWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// NewIdempotencyMiddleware replays the stored response when an authenticated user repeats a request
+// with the same Idempotency-Key header within ttl. Reusing a key for a different request returns 422.
+// Requests without the header, and responses that failed, are not stored.
+func NewIdempotencyMiddleware(ttl time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if key == "" {
+			c.Next()
+			return
+		}
+
+		if len(key) > 255 {
+			c.AbortWithStatusJSON(http.StatusBadRequest, &web.WebError{Code: http.StatusBadRequest, Errors: "Idempotency-Key must be at most 255 characters"})
+			return
+		}
+
+		claims, err := utils.ExtractTokenClaims(c)
+		if err != nil {
+			// let the handler reject the request
+			c.Next()
+			return
+		}
+
+		db, logger := utils.GetDBAndLogger(c)
+
+		body, err := io.ReadAll(c.Request.Body)
+		utils.PanicIfError(err)
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		hash := sha256.New()
+		hash.Write([]byte(c.Request.Method + " " + c.Request.URL.Path + "\n"))
+		hash.Write(body)
+		requestHash := hex.EncodeToString(hash.Sum(nil))
+
+		now := time.Now()
+		err = db.Where("user_id = ? AND expires_at < ?", claims.UserID, now).Delete(&entity.IdempotencyKey{}).Error
+		utils.PanicIfError(err)
+
+		record := entity.IdempotencyKey{
+			UserID:      claims.UserID,
+			Key:         key,
+			Method:      c.Request.Method,
+			Path:        c.Request.URL.Path,
+			RequestHash: requestHash,
+			ExpiresAt:   now.Add(ttl),
+		}
+
+		result := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&record)
+		utils.PanicIfError(result.Error)
+
+		if result.RowsAffected == 0 {
+			var existing entity.IdempotencyKey
+			err := db.Where("user_id = ? AND key = ?", claims.UserID, key).Take(&existing).Error
+			utils.PanicIfError(err)
+
+			switch {
+			case existing.RequestHash != requestHash:
+				c.AbortWithStatusJSON(http.StatusUnprocessableEntity, &web.WebError{Code: http.StatusUnprocessableEntity, Errors: "Idempotency-Key was already used for a different request"})
+			case existing.ResponseCode == 0:
+				c.AbortWithStatusJSON(http.StatusConflict, &web.WebError{Code: http.StatusConflict, Errors: "A request with this Idempotency-Key is still being processed"})
+			default:
+				logger.Info("replaying idempotent response", zap.Uint("userID", claims.UserID), zap.String("idempotencyKey", key))
+				c.Header("Idempotent-Replayed", "true")
+				c.Data(existing.ResponseCode, "application/json; charset=utf-8", []byte(existing.ResponseBody))
+				c.Abort()
+			}
+			return
+		}
+
+		completed := false
+		defer func() {
+			// the request panicked or failed, release the key so the client can retry
+			if !completed {
+				if err := db.Delete(&record).Error; err != nil {
+					logger.Error("failed to release idempotency key", zap.Uint("id", record.ID), zap.Error(err))
+				}
+			}
+		}()
+
+		writer := &idempotencyResponseWriter{ResponseWriter: c.Writer, body: &bytes.Buffer{}}
+		c.Writer = writer
+
+		c.Next()
+
+		if c.Writer.Status() >= http.StatusInternalServerError || c.IsAborted() {
+			return
+		}
+
+		err = db.Model(&record).Updates(map[string]any{
+			"response_code": c.Writer.Status(),
+			"response_body": writer.body.String(),
+		}).Error
+		if err != nil {
+			logger.Error("failed to store idempotent response", zap.Uint("id", record.ID), zap.Error(err))
+			return
+		}
+		completed = true
+	}
+}
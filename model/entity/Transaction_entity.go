@@ -2,14 +2,89 @@ package entity
 
 import "time"
 
+const (
+	TransactionStatusPending    = "pending"
+	TransactionStatusPaid       = "paid"
+	TransactionStatusProcessing = "processing"
+	TransactionStatusShipped    = "shipped"
+	TransactionStatusDelivered  = "delivered"
+	TransactionStatusCompleted  = "completed"
+	TransactionStatusCancelled  = "cancelled"
+	TransactionStatusExpired    = "expired"
+	TransactionStatusRefunded   = "refunded"
+
+	TransactionStatusPartiallyRefunded = "partially_refunded"
+)
+
+// Stock is reserved when a transaction is created, committed once it is paid
+// and released when it is cancelled or expires. Transactions created before
+// reservations existed have an empty StockReservation.
+const (
+	StockReservationReserved  = "reserved"
+	StockReservationCommitted = "committed"
+	StockReservationReleased  = "released"
+)
+
+// transactionTransitions lists, for every status, the statuses a transaction may move to next.
+// Cancelled, expired and refunded are final. A partially refunded transaction can
+// be refunded again and still goes on through fulfilment, see nextFulfilmentStage.
+var transactionTransitions = map[string][]string{
+	TransactionStatusPending:           {TransactionStatusPaid, TransactionStatusCancelled, TransactionStatusExpired},
+	TransactionStatusPaid:              {TransactionStatusProcessing, TransactionStatusRefunded, TransactionStatusPartiallyRefunded},
+	TransactionStatusProcessing:        {TransactionStatusShipped, TransactionStatusRefunded, TransactionStatusPartiallyRefunded},
+	TransactionStatusShipped:           {TransactionStatusDelivered},
+	TransactionStatusDelivered:         {TransactionStatusCompleted, TransactionStatusRefunded, TransactionStatusPartiallyRefunded},
+	TransactionStatusCompleted:         {TransactionStatusRefunded, TransactionStatusPartiallyRefunded},
+	TransactionStatusPartiallyRefunded: {TransactionStatusRefunded, TransactionStatusPartiallyRefunded},
+}
+
+// A partially refunded transaction keeps the stage it was refunded in as its FulfilmentStatus,
+// nextFulfilmentStage is the only stage it may move on to from there.
+var nextFulfilmentStage = map[string]string{
+	TransactionStatusPaid:       TransactionStatusProcessing,
+	TransactionStatusProcessing: TransactionStatusShipped,
+	TransactionStatusDelivered:  TransactionStatusCompleted,
+}
+
 type Transaction struct {
-	ID          int       `gorm:"primaryKey;autoIncrement"`
-	TotalPrice  int       `gorm:"type:int;not null"`
-	UserID      int       `gorm:"type:int;not null"`
-	Status      string    `gorm:"type:varchar(255); not null"`
-	PaymentLink string    `gorm:"type:varchar(255)"`
-	CreatedAt   time.Time `gorm:"autoCreateTime"`
-	UpdatedAt   time.Time `gorm:"autoUpdateTime"`
-	User        User      `gorm:"foreignKey:UserID"`
-	Order       []Order   `gorm:"constraint:OnDelete:CASCADE"`
+	ID               int                        `gorm:"primaryKey;autoIncrement"`
+	TotalPrice       int                        `gorm:"type:int;not null"`
+	UserID           int                        `gorm:"type:int;not null"`
+	Status           string                     `gorm:"type:varchar(255); not null"`
+	PaymentLink      string                     `gorm:"type:varchar(255)"`
+	ExpiresAt        *time.Time                 `gorm:"index"`
+	StockReservation string                     `gorm:"type:varchar(20)"`
+	FulfilmentStatus string                     `gorm:"type:varchar(20)"`
+	CreatedAt        time.Time                  `gorm:"autoCreateTime"`
+	UpdatedAt        time.Time                  `gorm:"autoUpdateTime"`
+	User             User                       `gorm:"foreignKey:UserID"`
+	Order            []Order                    `gorm:"constraint:OnDelete:CASCADE"`
+	StatusHistory    []TransactionStatusHistory `gorm:"constraint:OnDelete:CASCADE"`
+	Refunds          []Refund                   `gorm:"constraint:OnDelete:RESTRICT"`
+	Invoice          *Invoice                   `gorm:"constraint:OnDelete:RESTRICT"`
+}
+
+func (t *Transaction) CanTransitionTo(status string) bool {
+	for _, next := range transactionTransitions[t.Status] {
+		if next == status {
+			return true
+		}
+	}
+
+	if t.Status == TransactionStatusPartiallyRefunded {
+		next, ok := nextFulfilmentStage[t.FulfilmentStatus]
+		return ok && next == status
+	}
+
+	return false
+}
+
+// IsPaid reports whether the transaction has been paid, including transactions that
+// have since moved on through fulfilment or been refunded.
+func (t *Transaction) IsPaid() bool {
+	switch t.Status {
+	case TransactionStatusPending, TransactionStatusCancelled, TransactionStatusExpired, "":
+		return false
+	}
+	return true
 }
@@ -1,22 +1,40 @@
 package entity
 
-import "time"
+import (
+	"time"
 
+	"gorm.io/gorm"
+)
+
+// Bike keeps aggregates of its reviews: Rating is the sum of the review ratings and Stars1 to
+// Stars5 count the reviews per rating. Deleting a bike archives it, orders and reviews keep pointing at it.
+// Brand holds the name of the brand referenced by BrandID.
 type Bike struct {
-	ID          uint   `gorm:"primaryKey;autoIncrement"`
-	CategoryID  uint   `gorm:"not null"`
-	Name        string `gorm:"unique;not null;type:varchar(50)"`
-	Brand       string `gorm:"not null;type:varchar(20)"`
-	Description string `gorm:"type:varchar(1000)"`
-	Year        int    `gorm:"not null"`
-	Price       int    `gorm:"not null"`
-	ImageUrl    string `gorm:"type:varchar(255)"`
-	Stock       int    `gorm:"not null"`
-	IsAvailable bool   `gorm:"not null;default:true"`
-	Rating      int    `gorm:"not null;default:0"`
-	Reviewers   int    `gorm:"not null;default:0"`
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
-	Category    Category `gorm:"foreignKey:CategoryID"`
-	Review      []Review `gorm:"references:ID"`
+	ID            uint    `gorm:"primaryKey;autoIncrement"`
+	CategoryID    uint    `gorm:"not null"`
+	BrandID       *uint   `gorm:"index"`
+	Name          string  `gorm:"unique;not null;type:varchar(50)"`
+	Brand         string  `gorm:"not null;type:varchar(20)"`
+	Description   string  `gorm:"type:varchar(1000)"`
+	Year          int     `gorm:"not null"`
+	Price         int     `gorm:"not null"`
+	ImageUrl      string  `gorm:"type:varchar(255)"`
+	Stock         int     `gorm:"not null"`
+	IsAvailable   bool    `gorm:"not null;default:true"`
+	Rating        int     `gorm:"not null;default:0"`
+	Reviewers     int     `gorm:"not null;default:0"`
+	AverageRating float64 `gorm:"not null;default:0;type:numeric(3,2)"`
+	Stars1        int     `gorm:"not null;default:0"`
+	Stars2        int     `gorm:"not null;default:0"`
+	Stars3        int     `gorm:"not null;default:0"`
+	Stars4        int     `gorm:"not null;default:0"`
+	Stars5        int     `gorm:"not null;default:0"`
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+	DeletedAt     gorm.DeletedAt `gorm:"index"`
+	Category      Category       `gorm:"foreignKey:CategoryID"`
+	Review        []Review       `gorm:"references:ID"`
+	Variants      []BikeVariant  `gorm:"constraint:OnDelete:CASCADE"`
+	Images        []BikeImage    `gorm:"constraint:OnDelete:CASCADE"`
+	Specs         []BikeSpec     `gorm:"constraint:OnDelete:CASCADE"`
 }
@@ -0,0 +1,22 @@
+package entity
+
+import "time"
+
+// BikeImage is one picture in a bike's gallery. Images are shown by Position and the primary
+// image's URL is also kept in Bike.ImageUrl.
+type BikeImage struct {
+	ID           uint   `gorm:"primaryKey;autoIncrement"`
+	BikeID       uint   `gorm:"not null;index"`
+	Key          string `gorm:"not null;type:varchar(255)"`
+	ThumbnailKey string `gorm:"not null;type:varchar(255)"`
+	URL          string `gorm:"not null;type:varchar(255)"`
+	ThumbnailURL string `gorm:"not null;type:varchar(255)"`
+	ContentType  string `gorm:"not null;type:varchar(50)"`
+	Size         int64  `gorm:"not null"`
+	Width        int    `gorm:"not null"`
+	Height       int    `gorm:"not null"`
+	Position     int    `gorm:"not null"`
+	IsPrimary    bool   `gorm:"not null;default:false"`
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
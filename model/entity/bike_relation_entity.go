@@ -0,0 +1,13 @@
+package entity
+
+import "time"
+
+// BikeRelation is a bike customers bought together with another bike, Score counts the paid
+// transactions that had both. Relations are rebuilt from the orders periodically and kept for
+// both bikes of a pair.
+type BikeRelation struct {
+	BikeID        uint      `gorm:"primaryKey;autoIncrement:false"`
+	RelatedBikeID uint      `gorm:"primaryKey;autoIncrement:false;index"`
+	Score         int       `gorm:"not null"`
+	ComputedAt    time.Time `gorm:"not null"`
+}
@@ -0,0 +1,54 @@
+package entity
+
+import "time"
+
+// BikeVariant is a sellable version of a bike, e.g. an M frame in red. Stock is kept per variant,
+// the bike's own Stock and IsAvailable are derived from its variants. Stock falling to LowStockThreshold
+// or to zero raises a stock alert, and variants with AutoAvailability are only available while they
+// have stock. Discount is the discount the variant currently sells with, it is set when prices are
+// computed and not stored.
+type BikeVariant struct {
+	ID                uint   `gorm:"primaryKey;autoIncrement"`
+	BikeID            uint   `gorm:"not null;uniqueIndex:idx_bike_variant_attributes"`
+	SKU               string `gorm:"unique;not null;type:varchar(50)"`
+	FrameSize         string `gorm:"not null;default:'';type:varchar(10);uniqueIndex:idx_bike_variant_attributes"`
+	Color             string `gorm:"not null;default:'';type:varchar(30);uniqueIndex:idx_bike_variant_attributes"`
+	Price             *int
+	Stock             int  `gorm:"not null;default:0"`
+	IsAvailable       bool `gorm:"not null;default:true"`
+	LowStockThreshold int  `gorm:"not null;default:0"`
+	AutoAvailability  bool `gorm:"not null;default:false"`
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+	Bike              Bike      `gorm:"foreignKey:BikeID"`
+	Discount          *Discount `gorm:"-"`
+}
+
+// ListPrice is the variant's own price, or the bike's price when the variant doesn't override it.
+// Bike must be loaded.
+func (v *BikeVariant) ListPrice() int {
+	if v.Price != nil {
+		return *v.Price
+	}
+	return v.Bike.Price
+}
+
+// EffectivePrice is the price the variant sells for, its list price with Discount applied.
+func (v *BikeVariant) EffectivePrice() int {
+	if v.Discount != nil {
+		return v.Discount.Apply(v.ListPrice())
+	}
+	return v.ListPrice()
+}
+
+// Label describes the variant's attributes, e.g. "M / Red".
+func (v *BikeVariant) Label() string {
+	switch {
+	case v.FrameSize != "" && v.Color != "":
+		return v.FrameSize + " / " + v.Color
+	case v.FrameSize != "":
+		return v.FrameSize
+	default:
+		return v.Color
+	}
+}
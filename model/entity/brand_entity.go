@@ -0,0 +1,17 @@
+package entity
+
+import "time"
+
+// Brand is referenced by bikes through BrandID. Bikes also keep the brand name, which is renamed along
+// with the brand, so orders and exports don't need a join.
+type Brand struct {
+	ID          uint   `gorm:"primaryKey;autoIncrement"`
+	Name        string `gorm:"unique;not null;type:varchar(20)"`
+	Slug        string `gorm:"uniqueIndex;not null;type:varchar(30)"`
+	LogoUrl     string `gorm:"not null;default:'';type:varchar(255)"`
+	Country     string `gorm:"not null;default:'';type:varchar(50)"`
+	Description string `gorm:"not null;default:'';type:varchar(1000)"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	Bike        []Bike `gorm:"constraint:OnDelete:RESTRICT"`
+}
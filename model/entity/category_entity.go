@@ -1,11 +1,23 @@
 package entity
 
-import "time"
+import (
+	"time"
 
+	"gorm.io/gorm"
+)
+
+// Category is archived instead of deleted, together with its bikes. Categories form a tree through
+// ParentID, a category without a parent is a top level category.
 type Category struct {
-	ID          uint   				`gorm:"primaryKey;autoIncrement"`
-	Name    		string 				`gorm:"unique;not null;type:varchar(20)"`
+	ID          uint   `gorm:"primaryKey;autoIncrement"`
+	ParentID    *uint  `gorm:"index"`
+	Name        string `gorm:"unique;not null;type:varchar(50)"`
+	Slug        string `gorm:"uniqueIndex;type:varchar(60)"`
+	Description string `gorm:"not null;default:'';type:varchar(500)"`
+	ImageUrl    string `gorm:"not null;default:'';type:varchar(255)"`
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
-	Bike 				[]Bike 				`gorm:"constraint:OnDelete:CASCADE"`
-}
\ No newline at end of file
+	DeletedAt   gorm.DeletedAt `gorm:"index"`
+	Children    []Category     `gorm:"foreignKey:ParentID;constraint:OnDelete:RESTRICT"`
+	Bike        []Bike         `gorm:"constraint:OnDelete:RESTRICT"`
+}
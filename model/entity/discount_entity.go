@@ -0,0 +1,59 @@
+package entity
+
+import "time"
+
+const (
+	DiscountTypePercentage = "percentage"
+	DiscountTypeFixed      = "fixed"
+)
+
+const (
+	DiscountTargetBike     = "bike"
+	DiscountTargetCategory = "category"
+	DiscountTargetBrand    = "brand"
+)
+
+const (
+	DiscountStatusScheduled = "scheduled"
+	DiscountStatusActive    = "active"
+	DiscountStatusExpired   = "expired"
+)
+
+// Discount lowers the price of a bike, of the bikes of a brand or of the bikes in a category and its
+// subcategories from StartsAt until EndsAt, or for good when EndsAt is empty. Value is a percentage
+// or an amount off depending on Type.
+type Discount struct {
+	ID         uint       `gorm:"primaryKey;autoIncrement"`
+	Name       string     `gorm:"not null;type:varchar(100)"`
+	Type       string     `gorm:"not null;type:varchar(10)"`
+	Value      int        `gorm:"not null"`
+	TargetType string     `gorm:"not null;type:varchar(10);index:idx_discount_target"`
+	TargetID   uint       `gorm:"not null;index:idx_discount_target"`
+	StartsAt   time.Time  `gorm:"not null;index"`
+	EndsAt     *time.Time `gorm:"index"`
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// Apply returns the price after the discount, which never goes below zero.
+func (d *Discount) Apply(price int) int {
+	if d.Type == DiscountTypePercentage {
+		return price - price*d.Value/100
+	}
+	if d.Value > price {
+		return 0
+	}
+	return price - d.Value
+}
+
+// Status tells whether the discount is scheduled, active or expired at the given time.
+func (d *Discount) Status(at time.Time) string {
+	switch {
+	case at.Before(d.StartsAt):
+		return DiscountStatusScheduled
+	case d.EndsAt != nil && !at.Before(*d.EndsAt):
+		return DiscountStatusExpired
+	default:
+		return DiscountStatusActive
+	}
+}
@@ -0,0 +1,16 @@
+package entity
+
+import "time"
+
+type IdempotencyKey struct {
+	ID           uint      `gorm:"primaryKey;autoIncrement"`
+	UserID       uint      `gorm:"not null;uniqueIndex:idx_idempotency_user_key"`
+	Key          string    `gorm:"type:varchar(255);not null;uniqueIndex:idx_idempotency_user_key"`
+	Method       string    `gorm:"type:varchar(10);not null"`
+	Path         string    `gorm:"type:varchar(255);not null"`
+	RequestHash  string    `gorm:"type:varchar(64);not null"`
+	ResponseCode int       `gorm:"not null;default:0"`
+	ResponseBody string    `gorm:"type:text"`
+	CreatedAt    time.Time `gorm:"autoCreateTime"`
+	ExpiresAt    time.Time `gorm:"not null;index"`
+}
@@ -0,0 +1,23 @@
+package entity
+
+import "time"
+
+// Invoice is issued once per transaction when it is paid. Number comes from the
+// InvoiceCounter so invoices are numbered in the order they were paid, without gaps.
+type Invoice struct {
+	ID            uint      `gorm:"primaryKey;autoIncrement"`
+	TransactionID int       `gorm:"type:int;not null;unique"`
+	Number        string    `gorm:"type:varchar(30);not null;unique"`
+	IssuedAt      time.Time `gorm:"not null"`
+	CreatedAt     time.Time `gorm:"autoCreateTime"`
+}
+
+// InvoiceCounter holds the last invoice number handed out. There is a single row, locked by the
+// transaction issuing an invoice, so a number is only used up when its invoice is stored.
+type InvoiceCounter struct {
+	ID         uint      `gorm:"primaryKey"`
+	LastNumber int64     `gorm:"not null;default:0"`
+	UpdatedAt  time.Time `gorm:"autoUpdateTime"`
+}
+
+const InvoiceCounterID = 1
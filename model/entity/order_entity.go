@@ -5,8 +5,15 @@ import "time"
 type Order struct {
 	ID            int         `gorm:"primaryKey;autoIncrement"`
 	BikeID        int         `gorm:"type:int;not null"`
+	BikeVariantID uint        `gorm:"index"`
 	Quantity      int         `gorm:"type:int;not null"`
+	UnitPrice     int         `gorm:"type:int;not null;default:0"`
+	DiscountID    *uint       `gorm:"default:null"`
 	TotalPrice    int         `gorm:"type:int;not null"`
+	BikeName      string      `gorm:"type:varchar(50)"`
+	BikeBrand     string      `gorm:"type:varchar(20)"`
+	SKU           string      `gorm:"type:varchar(50)"`
+	VariantName   string      `gorm:"type:varchar(50)"`
 	UserID        int         `gorm:"type:int;not null"`
 	TransactionID int         `gorm:"type:int; not null"`
 	IsReviewed    bool        `gorm:"not null; default:false"`
@@ -0,0 +1,22 @@
+package entity
+
+import "time"
+
+const (
+	PriceChangeSourceAdmin  = "admin"
+	PriceChangeSourceImport = "import"
+)
+
+// PriceHistory records a change of the list price of a bike, or of the price override of one of its
+// variants when BikeVariantID is set. OldPrice is empty for a new price, and for a variant that used
+// the bike's price before.
+type PriceHistory struct {
+	ID            uint  `gorm:"primaryKey;autoIncrement"`
+	BikeID        uint  `gorm:"not null;index"`
+	BikeVariantID *uint `gorm:"default:null"`
+	OldPrice      *int
+	NewPrice      *int
+	Source        string    `gorm:"type:varchar(20);not null"`
+	ActorID       *uint     `gorm:"default:null"`
+	CreatedAt     time.Time `gorm:"autoCreateTime"`
+}
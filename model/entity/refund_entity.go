@@ -0,0 +1,37 @@
+package entity
+
+import "time"
+
+const (
+	RefundStatusPending   = "pending"
+	RefundStatusSucceeded = "succeeded"
+	RefundStatusFailed    = "failed"
+	// RefundStatusManual marks money the gateway could not return on its own; someone has to refund it by hand.
+	RefundStatusManual = "manual"
+)
+
+// RefundActorSystem is the ActorID of refunds nobody asked for, such as a payment that arrived
+// after its transaction had already expired or been cancelled.
+const RefundActorSystem uint = 0
+
+type Refund struct {
+	ID               uint         `gorm:"primaryKey;autoIncrement"`
+	TransactionID    int          `gorm:"type:int;not null;index"`
+	Amount           int          `gorm:"type:int;not null"`
+	Reason           string       `gorm:"type:varchar(255);not null"`
+	Status           string       `gorm:"type:varchar(20);not null"`
+	GatewayReference string       `gorm:"type:varchar(255)"`
+	Restock          bool         `gorm:"not null;default:false"`
+	ActorID          uint         `gorm:"not null"`
+	CreatedAt        time.Time    `gorm:"autoCreateTime"`
+	UpdatedAt        time.Time    `gorm:"autoUpdateTime"`
+	Items            []RefundItem `gorm:"constraint:OnDelete:CASCADE"`
+}
+
+type RefundItem struct {
+	ID       uint `gorm:"primaryKey;autoIncrement"`
+	RefundID uint `gorm:"not null;index"`
+	OrderID  int  `gorm:"type:int;not null;index"`
+	Quantity int  `gorm:"type:int;not null"`
+	Amount   int  `gorm:"type:int;not null"`
+}
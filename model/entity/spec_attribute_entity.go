@@ -0,0 +1,80 @@
+package entity
+
+import (
+	"slices"
+	"strconv"
+	"time"
+)
+
+const (
+	SpecTypeEnum    = "enum"
+	SpecTypeNumber  = "number"
+	SpecTypeBoolean = "boolean"
+)
+
+// SpecAttribute defines a technical specification of the bikes in a category, e.g. the wheel size of
+// mountain bikes. It applies to the subcategories too. Options lists the allowed values of an enum.
+type SpecAttribute struct {
+	ID         uint     `gorm:"primaryKey;autoIncrement"`
+	CategoryID uint     `gorm:"not null;uniqueIndex:idx_spec_attribute_category_key"`
+	Key        string   `gorm:"not null;type:varchar(50);uniqueIndex:idx_spec_attribute_category_key"`
+	Name       string   `gorm:"not null;type:varchar(50)"`
+	Type       string   `gorm:"not null;type:varchar(10)"`
+	Unit       string   `gorm:"not null;default:'';type:varchar(20)"`
+	Options    []string `gorm:"serializer:json;type:text"`
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	Category   Category   `gorm:"foreignKey:CategoryID;constraint:OnDelete:RESTRICT"`
+	Values     []BikeSpec `gorm:"constraint:OnDelete:CASCADE"`
+}
+
+// BikeSpec is the value of a spec attribute for a bike. Value holds it as text, numbers are also kept
+// in NumberValue so they can be compared.
+type BikeSpec struct {
+	ID              uint     `gorm:"primaryKey;autoIncrement"`
+	BikeID          uint     `gorm:"not null;uniqueIndex:idx_bike_spec_attribute"`
+	SpecAttributeID uint     `gorm:"not null;uniqueIndex:idx_bike_spec_attribute;index"`
+	Value           string   `gorm:"not null;type:varchar(100)"`
+	NumberValue     *float64 `gorm:"index"`
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+	SpecAttribute   SpecAttribute `gorm:"foreignKey:SpecAttributeID"`
+}
+
+// Encode checks a value given in JSON against the attribute's type and returns it as stored in a BikeSpec.
+func (a *SpecAttribute) Encode(value any) (string, *float64, bool) {
+	switch a.Type {
+	case SpecTypeEnum:
+		option, ok := value.(string)
+		if !ok || !slices.Contains(a.Options, option) {
+			return "", nil, false
+		}
+		return option, nil, true
+	case SpecTypeNumber:
+		number, ok := value.(float64)
+		if !ok {
+			return "", nil, false
+		}
+		return strconv.FormatFloat(number, 'f', -1, 64), &number, true
+	case SpecTypeBoolean:
+		boolean, ok := value.(bool)
+		if !ok {
+			return "", nil, false
+		}
+		return strconv.FormatBool(boolean), nil, true
+	}
+	return "", nil, false
+}
+
+// Decode turns a stored value back into its JSON type.
+func (a *SpecAttribute) Decode(spec *BikeSpec) any {
+	switch a.Type {
+	case SpecTypeNumber:
+		if spec.NumberValue != nil {
+			return *spec.NumberValue
+		}
+	case SpecTypeBoolean:
+		return spec.Value == "true"
+	}
+	return spec.Value
+}
@@ -0,0 +1,47 @@
+package entity
+
+import "time"
+
+const (
+	StockAlertLowStock   = "low_stock"
+	StockAlertOutOfStock = "out_of_stock"
+)
+
+const (
+	StockAlertStatusOpen         = "open"
+	StockAlertStatusAcknowledged = "acknowledged"
+	StockAlertStatusResolved     = "resolved"
+)
+
+// StockAlert is raised when a variant's stock falls to its low stock threshold or runs out, Stock is
+// what was left then. NotifiedAt is set once the notifier delivered it, NotifyClaimedAt while a worker
+// is delivering it and NotifyAttempts and NotifyError keep track of failed deliveries. An admin
+// acknowledges the alert, and it resolves itself when the variant is restocked above the level it was raised for.
+type StockAlert struct {
+	ID              uint       `gorm:"primaryKey;autoIncrement"`
+	BikeID          uint       `gorm:"not null;index"`
+	BikeVariantID   uint       `gorm:"not null;index"`
+	Type            string     `gorm:"type:varchar(20);not null"`
+	Stock           int        `gorm:"not null"`
+	Threshold       int        `gorm:"not null"`
+	NotifiedAt      *time.Time `gorm:"index"`
+	NotifyClaimedAt *time.Time
+	NotifyAttempts  int    `gorm:"not null;default:0"`
+	NotifyError     string `gorm:"type:varchar(255);not null;default:''"`
+	AcknowledgedAt  *time.Time
+	AcknowledgedBy  *uint `gorm:"default:null"`
+	ResolvedAt      *time.Time
+	CreatedAt       time.Time `gorm:"autoCreateTime"`
+}
+
+// Status tells whether the alert still needs attention.
+func (a *StockAlert) Status() string {
+	switch {
+	case a.ResolvedAt != nil:
+		return StockAlertStatusResolved
+	case a.AcknowledgedAt != nil:
+		return StockAlertStatusAcknowledged
+	default:
+		return StockAlertStatusOpen
+	}
+}
@@ -0,0 +1,28 @@
+package entity
+
+import "time"
+
+const (
+	StockMovementRestock     = "restock"
+	StockMovementSale        = "sale"
+	StockMovementReturn      = "return"
+	StockMovementAdjustment  = "adjustment"
+	StockMovementReservation = "reservation"
+)
+
+// StockMovement is an entry of the inventory ledger. Quantity is the change of the variant's stock,
+// negative when stock leaves, so a variant's stock is the sum of the quantities of its movements.
+// Reference points at what caused the movement, e.g. "transaction:12", and ActorID is empty for
+// changes made by the system or the payment gateway. Movements outlive their variant, so the history
+// of a bike stays complete after a variant is deleted.
+type StockMovement struct {
+	ID            uint      `gorm:"primaryKey;autoIncrement"`
+	BikeID        uint      `gorm:"not null;index"`
+	BikeVariantID uint      `gorm:"not null;index"`
+	Type          string    `gorm:"type:varchar(20);not null"`
+	Quantity      int       `gorm:"not null"`
+	Reference     string    `gorm:"type:varchar(50);not null;default:''"`
+	Note          string    `gorm:"type:varchar(255);not null;default:''"`
+	ActorID       *uint     `gorm:"default:null"`
+	CreatedAt     time.Time `gorm:"autoCreateTime"`
+}
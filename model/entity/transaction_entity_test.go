@@ -0,0 +1,40 @@
+package entity
+
+import "testing"
+
+func TestPartiallyRefundedTransactionOnlyMovesToNextFulfilmentStage(t *testing.T) {
+	tests := []struct {
+		fulfilmentStatus string
+		allowed          []string
+	}{
+		{TransactionStatusPaid, []string{TransactionStatusProcessing}},
+		{TransactionStatusProcessing, []string{TransactionStatusShipped}},
+		{TransactionStatusDelivered, []string{TransactionStatusCompleted}},
+		{TransactionStatusCompleted, nil},
+	}
+
+	stages := []string{TransactionStatusProcessing, TransactionStatusShipped, TransactionStatusDelivered, TransactionStatusCompleted}
+
+	for _, tt := range tests {
+		t.Run(tt.fulfilmentStatus, func(t *testing.T) {
+			transaction := Transaction{Status: TransactionStatusPartiallyRefunded, FulfilmentStatus: tt.fulfilmentStatus}
+
+			for _, stage := range stages {
+				want := false
+				for _, allowed := range tt.allowed {
+					want = want || allowed == stage
+				}
+
+				if got := transaction.CanTransitionTo(stage); got != want {
+					t.Errorf("CanTransitionTo(%q) = %v, want %v", stage, got, want)
+				}
+			}
+
+			for _, status := range []string{TransactionStatusRefunded, TransactionStatusPartiallyRefunded} {
+				if !transaction.CanTransitionTo(status) {
+					t.Errorf("CanTransitionTo(%q) = false, want true", status)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,21 @@
+package entity
+
+import "time"
+
+const (
+	TransactionActorUser    = "user"
+	TransactionActorAdmin   = "admin"
+	TransactionActorGateway = "gateway"
+	TransactionActorSystem  = "system"
+)
+
+type TransactionStatusHistory struct {
+	ID            uint      `gorm:"primaryKey;autoIncrement"`
+	TransactionID int       `gorm:"type:int;not null;index"`
+	FromStatus    string    `gorm:"type:varchar(20)"`
+	ToStatus      string    `gorm:"type:varchar(20);not null"`
+	ActorType     string    `gorm:"type:varchar(20);not null"`
+	ActorID       *uint     `gorm:"default:null"`
+	Reason        string    `gorm:"type:varchar(255)"`
+	CreatedAt     time.Time `gorm:"autoCreateTime"`
+}
@@ -0,0 +1,6 @@
+package request
+
+// BikeImageReorderRequest lists all of a bike's image ids in their new gallery order.
+type BikeImageReorderRequest struct {
+	ImageIDs []uint `json:"image_ids" binding:"required,min=1,dive,gt=0"`
+}
@@ -0,0 +1,21 @@
+package request
+
+// BikeImportRow is one bike of a catalogue import, in the same shape as database/bike.json.
+// SKU, FrameSize, Color and VariantPrice describe a single variant, which is how CSV rows carry
+// variants: a bike with several variants takes one row per variant.
+type BikeImportRow struct {
+	CreateBikeRequest
+	SKU          string `json:"sku" binding:"omitempty,max=50,no_space"`
+	FrameSize    string `json:"frame_size" binding:"omitempty,max=10"`
+	Color        string `json:"color" binding:"omitempty,max=30"`
+	VariantPrice *int   `json:"variant_price" binding:"omitempty,gt=0"`
+}
+
+type BikeImportQuery struct {
+	Format string `form:"format" binding:"omitempty,oneof=csv json"`
+	DryRun bool   `form:"dry_run"`
+}
+
+type BikeExportQuery struct {
+	Format string `form:"format" binding:"omitempty,oneof=csv json"`
+}
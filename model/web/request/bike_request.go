@@ -2,40 +2,70 @@ package request
 
 import "github.com/gowesmart/api-gowesmart/model/web"
 
+// CreateBikeRequest creates the bike with the given variants, or with a single variant
+// holding Stock and IsAvailable when Variants is empty. The brand is given by id, or by name. Specs maps
+// spec attribute keys of the category to values of the attribute's type.
 type CreateBikeRequest struct {
-	CategoryID  uint   `json:"category_id" binding:"required"`
-	Name        string `json:"name" binding:"required"`
-	Brand       string `json:"brand" binding:"required"`
-	Description string `json:"description"`
-	Year        int    `json:"year" binding:"required"`
-	Price       int    `json:"price" binding:"required"`
-	ImageUrl    string `json:"image_url" binding:"required,url"`
-	Stock       int    `json:"stock" binding:"required"`
-	IsAvailable bool   `json:"is_available"`
+	CategoryID  uint                       `json:"category_id" binding:"required"`
+	Name        string                     `json:"name" binding:"required"`
+	BrandID     uint                       `json:"brand_id" binding:"required_without=Brand"`
+	Brand       string                     `json:"brand" binding:"required_without=BrandID"`
+	Description string                     `json:"description"`
+	Year        int                        `json:"year" binding:"required"`
+	Price       int                        `json:"price" binding:"required,gt=0"`
+	ImageUrl    string                     `json:"image_url" binding:"required,url"`
+	Stock       int                        `json:"stock" binding:"gte=0"`
+	IsAvailable bool                       `json:"is_available"`
+	Variants    []CreateBikeVariantRequest `json:"variants" binding:"omitempty,dive"`
+	Specs       map[string]any             `json:"specs"`
 }
 
 type UpdateBikeRequest struct {
 	CategoryID  uint   `json:"category_id"`
 	Name        string `json:"name"`
+	BrandID     uint   `json:"brand_id"`
 	Brand       string `json:"brand"`
 	Description string `json:"description"`
 	Year        int    `json:"year"`
-	Price       int    `json:"price"`
+	Price       int    `json:"price" binding:"omitempty,gt=0"`
 	ImageUrl    string `json:"image_url" binding:"omitempty,url"`
-	Stock       int    `json:"stock"`
-	IsAvailable bool   `json:"is_available"`
+	// Specs sets the given specs, a null value removes one
+	Specs map[string]any `json:"specs"`
 }
 
 type GetBikeByIDRequest struct {
 	ID uint `json:"id" binding:"required"`
 }
 
+// BikeQueryRequest filters and sorts the catalogue. Brands, given by slug or name, may be repeated or comma
+// separated. A category, given by id or slug, matches its subcategories too.
 type BikeQueryRequest struct {
-	CategoryID uint   `form:"category_id" binding:"omitempty"`
-	Name       string `form:"name" binding:"omitempty"`
-	MinPrice   int    `form:"min_price" binding:"omitempty,gt=0"`
-	MaxPrice   int    `form:"max_price" binding:"omitempty,gt=0"`
-	MinYear    int    `form:"min_year" binding:"omitempty,gt=0"`
-	MaxYear    int    `form:"max_year" binding:"omitempty,gt=0"`
+	CategoryID uint     `form:"category_id" binding:"omitempty"`
+	Category   string   `form:"category" binding:"omitempty,slug"`
+	Name       string   `form:"name" binding:"omitempty"`
+	Brands     []string `form:"brand" binding:"omitempty"`
+	BrandIDs   []uint   `form:"brand_id" binding:"omitempty"`
+	MinPrice   int      `form:"min_price" binding:"omitempty,gt=0"`
+	MaxPrice   int      `form:"max_price" binding:"omitempty,gt=0"`
+	MinYear    int      `form:"min_year" binding:"omitempty,gt=0"`
+	MaxYear    int      `form:"max_year" binding:"omitempty,gt=0"`
+	MinRating  float64  `form:"min_rating" binding:"omitempty,gte=0,lte=5"`
+	InStock    bool     `form:"in_stock"`
+	Sort       string   `form:"sort" binding:"omitempty,oneof=price_asc price_desc newest rating popularity"`
+	// IncludeArchived is only allowed for admins
+	IncludeArchived bool `form:"include_archived"`
+	// Specs filters on spec values, read from spec[key]=value. Enum values may be comma separated,
+	// numbers are also filtered by range with spec[key_min] and spec[key_max].
+	Specs map[string]string `form:"-"`
 	web.PaginationRequest
 }
+
+// BikeCompareRequest takes the ids of the bikes to compare comma separated, e.g. ids=1,2,3.
+type BikeCompareRequest struct {
+	IDs string `form:"ids" binding:"required"`
+}
+
+type BikeSuggestRequest struct {
+	Q     string `form:"q" binding:"required,min=2,max=100"`
+	Limit int    `form:"limit" binding:"omitempty,min=1,max=20"`
+}
@@ -0,0 +1,25 @@
+package request
+
+type CreateBikeVariantRequest struct {
+	SKU               string `json:"sku" binding:"required,max=50,no_space"`
+	FrameSize         string `json:"frame_size" binding:"omitempty,max=10"`
+	Color             string `json:"color" binding:"omitempty,max=30"`
+	Price             *int   `json:"price" binding:"omitempty,gt=0"`
+	Stock             int    `json:"stock" binding:"gte=0"`
+	IsAvailable       *bool  `json:"is_available"`
+	LowStockThreshold int    `json:"low_stock_threshold" binding:"gte=0"`
+	AutoAvailability  bool   `json:"auto_availability"`
+}
+
+// UpdateBikeVariantRequest doesn't change stock, stock moves through stock movements.
+type UpdateBikeVariantRequest struct {
+	SKU       string `json:"sku" binding:"omitempty,max=50,no_space"`
+	FrameSize string `json:"frame_size" binding:"omitempty,max=10"`
+	Color     string `json:"color" binding:"omitempty,max=30"`
+	Price     *int   `json:"price" binding:"omitempty,gt=0"`
+	// ClearPrice drops the variant's own price, so it sells at the bike's price again
+	ClearPrice        bool  `json:"clear_price" binding:"excluded_with=Price"`
+	IsAvailable       *bool `json:"is_available"`
+	LowStockThreshold *int  `json:"low_stock_threshold" binding:"omitempty,gte=0"`
+	AutoAvailability  *bool `json:"auto_availability"`
+}
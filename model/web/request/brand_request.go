@@ -0,0 +1,26 @@
+package request
+
+import "github.com/gowesmart/api-gowesmart/model/web"
+
+// CreateBrandRequest creates a brand, the slug is derived from the name unless given.
+type CreateBrandRequest struct {
+	Name        string `json:"name" binding:"required,max=20"`
+	Slug        string `json:"slug" binding:"omitempty,max=30,slug"`
+	LogoUrl     string `json:"logo_url" binding:"omitempty,url"`
+	Country     string `json:"country" binding:"omitempty,max=50"`
+	Description string `json:"description" binding:"omitempty,max=1000"`
+}
+
+// UpdateBrandRequest only changes the fields that are set. Renaming a brand renames it on its bikes too.
+type UpdateBrandRequest struct {
+	Name        string  `json:"name" binding:"omitempty,max=20"`
+	Slug        string  `json:"slug" binding:"omitempty,max=30,slug"`
+	LogoUrl     *string `json:"logo_url" binding:"omitempty,url"`
+	Country     *string `json:"country" binding:"omitempty,max=50"`
+	Description *string `json:"description" binding:"omitempty,max=1000"`
+}
+
+type BrandQueryRequest struct {
+	Name string `form:"name" binding:"omitempty"`
+	web.PaginationRequest
+}
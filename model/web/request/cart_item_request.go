@@ -1,15 +1,20 @@
 package request
 
 type CartItemCreateRequest struct {
-	BikeID   uint `json:"bike_id" binding:"required"`
-	Quantity int  `json:"quantity" binding:"required"`
+	VariantID uint `json:"variant_id" binding:"required"`
+	Quantity  int  `json:"quantity" binding:"required,gt=0"`
 }
 
 type CartItemUpdateRequest struct {
-	BikeID   uint `json:"bike_id" binding:"required"`
-	Quantity int  `json:"quantity" binding:"required"`
+	VariantID uint `json:"variant_id" binding:"required"`
+	Quantity  int  `json:"quantity" binding:"required,gt=0"`
 }
 
 type CartItemDeleteRequest struct {
-	BikeID uint `json:"bike_id" binding:"required"`
+	VariantID uint `json:"variant_id" binding:"required"`
+}
+
+// CartCheckoutRequest checks out the given cart items, or the whole cart when CartItemIDs is empty.
+type CartCheckoutRequest struct {
+	CartItemIDs []uint `json:"cart_item_ids" binding:"omitempty,dive,gt=0"`
 }
@@ -1,9 +1,29 @@
 package request
 
+import "github.com/gowesmart/api-gowesmart/model/web"
+
+// CreateCategoryRequest creates a category under ParentID, or a top level one when it is empty.
+// The slug is derived from the name unless given.
 type CreateCategoryRequest struct {
-	Name string `json:"name" binding:"required"`
+	Name        string `json:"name" binding:"required,max=50"`
+	Slug        string `json:"slug" binding:"omitempty,max=60,slug"`
+	Description string `json:"description" binding:"omitempty,max=500"`
+	ImageUrl    string `json:"image_url" binding:"omitempty,url"`
+	ParentID    *uint  `json:"parent_id"`
 }
 
+// UpdateCategoryRequest only changes the fields that are set. A ParentID of 0 moves the category
+// to the top level.
 type UpdateCategoryRequest struct {
-	Name string `json:"name" binding:"required"`
+	Name        string  `json:"name" binding:"omitempty,max=50"`
+	Slug        string  `json:"slug" binding:"omitempty,max=60,slug"`
+	Description *string `json:"description" binding:"omitempty,max=500"`
+	ImageUrl    *string `json:"image_url" binding:"omitempty,url"`
+	ParentID    *uint   `json:"parent_id"`
+}
+
+type CategoryQueryRequest struct {
+	// IncludeArchived is only allowed for admins
+	IncludeArchived bool `form:"include_archived"`
+	web.PaginationRequest
 }
@@ -0,0 +1,34 @@
+package request
+
+import (
+	"time"
+
+	"github.com/gowesmart/api-gowesmart/model/web"
+)
+
+// CreateDiscountRequest schedules a discount, starting right away when StartsAt is empty and running
+// until further notice when EndsAt is empty. A percentage Value is at most 100.
+type CreateDiscountRequest struct {
+	Name       string     `json:"name" binding:"required,max=100"`
+	Type       string     `json:"type" binding:"required,oneof=percentage fixed"`
+	Value      int        `json:"value" binding:"required,gt=0"`
+	TargetType string     `json:"target_type" binding:"required,oneof=bike category brand"`
+	TargetID   uint       `json:"target_id" binding:"required"`
+	StartsAt   *time.Time `json:"starts_at"`
+	EndsAt     *time.Time `json:"ends_at"`
+}
+
+// UpdateDiscountRequest only changes the fields that are set, setting EndsAt to now ends a discount.
+type UpdateDiscountRequest struct {
+	Name     string     `json:"name" binding:"omitempty,max=100"`
+	Value    int        `json:"value" binding:"omitempty,gt=0"`
+	StartsAt *time.Time `json:"starts_at"`
+	EndsAt   *time.Time `json:"ends_at"`
+}
+
+type DiscountQueryRequest struct {
+	TargetType string `form:"target_type" binding:"omitempty,oneof=bike category brand"`
+	TargetID   uint   `form:"target_id" binding:"omitempty"`
+	Status     string `form:"status" binding:"omitempty,oneof=scheduled active expired"`
+	web.PaginationRequest
+}
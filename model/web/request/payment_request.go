@@ -0,0 +1,14 @@
+package request
+
+type MidtransNotificationRequest struct {
+	TransactionID     string `json:"transaction_id"`
+	TransactionStatus string `json:"transaction_status" binding:"required"`
+	TransactionTime   string `json:"transaction_time"`
+	SettlementTime    string `json:"settlement_time"`
+	StatusCode        string `json:"status_code" binding:"required"`
+	SignatureKey      string `json:"signature_key" binding:"required"`
+	PaymentType       string `json:"payment_type"`
+	OrderID           string `json:"order_id" binding:"required"`
+	GrossAmount       string `json:"gross_amount" binding:"required"`
+	FraudStatus       string `json:"fraud_status"`
+}
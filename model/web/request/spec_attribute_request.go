@@ -0,0 +1,25 @@
+package request
+
+// CreateSpecAttributeRequest defines a spec of the bikes in a category and its subcategories. Keys are
+// used in search filters, e.g. spec[wheel_size]=29, so they can't end in _min or _max.
+type CreateSpecAttributeRequest struct {
+	CategoryID uint     `json:"category_id" binding:"required"`
+	Key        string   `json:"key" binding:"required,max=50,spec_key"`
+	Name       string   `json:"name" binding:"required,max=50"`
+	Type       string   `json:"type" binding:"required,oneof=enum number boolean"`
+	Unit       string   `json:"unit" binding:"omitempty,max=20"`
+	Options    []string `json:"options" binding:"required_if=Type enum,unique,dive,required,max=100"`
+}
+
+// UpdateSpecAttributeRequest only changes the fields that are set. The key, type and category can't
+// change once bikes may use the attribute.
+type UpdateSpecAttributeRequest struct {
+	Name    string   `json:"name" binding:"omitempty,max=50"`
+	Unit    *string  `json:"unit" binding:"omitempty,max=20"`
+	Options []string `json:"options" binding:"omitempty,unique,dive,required,max=100"`
+}
+
+// SpecAttributeQueryRequest lists the attributes that apply to a category, those of its parents included.
+type SpecAttributeQueryRequest struct {
+	CategoryID uint `form:"category_id" binding:"omitempty"`
+}
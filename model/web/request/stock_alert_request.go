@@ -0,0 +1,10 @@
+package request
+
+import "github.com/gowesmart/api-gowesmart/model/web"
+
+type StockAlertQueryRequest struct {
+	BikeID uint   `form:"bike_id" binding:"omitempty"`
+	Type   string `form:"type" binding:"omitempty,oneof=low_stock out_of_stock"`
+	Status string `form:"status" binding:"omitempty,oneof=open acknowledged resolved"`
+	web.PaginationRequest
+}
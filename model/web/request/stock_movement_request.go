@@ -0,0 +1,18 @@
+package request
+
+import "github.com/gowesmart/api-gowesmart/model/web"
+
+// CreateStockMovementRequest posts a movement to a variant's stock. Restocks and returns add stock,
+// adjustments correct it either way, e.g. after a stock count.
+type CreateStockMovementRequest struct {
+	Type      string `json:"type" binding:"required,oneof=restock return adjustment"`
+	Quantity  int    `json:"quantity" binding:"required"`
+	Reference string `json:"reference" binding:"omitempty,max=50"`
+	Note      string `json:"note" binding:"omitempty,max=255"`
+}
+
+type StockMovementQueryRequest struct {
+	VariantID uint   `form:"variant_id" binding:"omitempty"`
+	Type      string `form:"type" binding:"omitempty,oneof=restock sale return adjustment reservation"`
+	web.PaginationRequest
+}
@@ -1,14 +1,32 @@
 package request
 
+// TransactionCreate only carries what to buy, prices are always taken from the bike variant.
 type TransactionCreate struct {
-	BikeID     int `json:"bike_id" bind:"required"`
-	Quantity   int `json:"quantity" bind:"required"`
-	TotalPrice int `json:"total_price" bind:"required"`
+	VariantID uint `json:"variant_id" binding:"required"`
+	Quantity  int  `json:"quantity" binding:"required,gt=0"`
 }
 
 type TransactionUpdate struct {
-	ID         int `json:"id" bind:"required"`
-	BikeID     int `json:"bike_id" bind:"required"`
-	Quantity   int `json:"quantity" bind:"required"`
-	TotalPrice int `json:"total_price" bind:"required"`
+	ID        int  `json:"id" binding:"required"`
+	VariantID uint `json:"variant_id" binding:"required"`
+	Quantity  int  `json:"quantity" binding:"required,gt=0"`
+}
+
+type TransactionStatusUpdate struct {
+	Status string `json:"status" binding:"required,oneof=processing shipped delivered completed cancelled"`
+	Reason string `json:"reason" binding:"omitempty,max=255"`
+}
+
+// RefundCreate refunds the given order lines, or everything not refunded yet when Items is empty.
+type RefundCreate struct {
+	Items   []RefundItemCreate `json:"items" binding:"omitempty,dive"`
+	Reason  string             `json:"reason" binding:"required,max=255"`
+	Restock bool               `json:"restock"`
+}
+
+// RefundItemCreate refunds quantity units of an order, Amount defaults to the unit price times quantity.
+type RefundItemCreate struct {
+	OrderID  int `json:"order_id" binding:"required"`
+	Quantity int `json:"quantity" binding:"required,gt=0"`
+	Amount   int `json:"amount" binding:"omitempty,gt=0"`
 }
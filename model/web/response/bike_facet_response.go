@@ -0,0 +1,30 @@
+package response
+
+// BikeFacetsResponse counts the bikes matching a search per filter value. Each facet ignores its own
+// filter, so the counts of the other values stay visible while one is selected.
+type BikeFacetsResponse struct {
+	Brands     []BikeBrandFacetResponse    `json:"brands"`
+	Categories []BikeCategoryFacetResponse `json:"categories"`
+	Prices     []BikePriceFacetResponse    `json:"prices"`
+}
+
+type BikeBrandFacetResponse struct {
+	BrandID uint   `json:"brand_id"`
+	Brand   string `json:"brand"`
+	Slug    string `json:"slug"`
+	Count   int64  `json:"count"`
+}
+
+type BikeCategoryFacetResponse struct {
+	CategoryID uint   `json:"category_id"`
+	Name       string `json:"name"`
+	Slug       string `json:"slug"`
+	Count      int64  `json:"count"`
+}
+
+// BikePriceFacetResponse counts the bikes priced from Min up to but excluding Max, the last bucket has no Max.
+type BikePriceFacetResponse struct {
+	Min   int   `json:"min"`
+	Max   *int  `json:"max"`
+	Count int64 `json:"count"`
+}
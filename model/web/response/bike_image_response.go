@@ -0,0 +1,14 @@
+package response
+
+type BikeImageResponse struct {
+	ID           uint   `json:"id"`
+	BikeID       uint   `json:"bike_id"`
+	URL          string `json:"url"`
+	ThumbnailURL string `json:"thumbnail_url"`
+	ContentType  string `json:"content_type"`
+	Size         int64  `json:"size"`
+	Width        int    `json:"width"`
+	Height       int    `json:"height"`
+	Position     int    `json:"position"`
+	IsPrimary    bool   `json:"is_primary"`
+}
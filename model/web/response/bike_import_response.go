@@ -0,0 +1,19 @@
+package response
+
+type BikeImportResponse struct {
+	DryRun  bool                    `json:"dry_run"`
+	Created int                     `json:"created"`
+	Updated int                     `json:"updated"`
+	Failed  int                     `json:"failed"`
+	Rows    []BikeImportRowResponse `json:"rows"`
+}
+
+// BikeImportRowResponse reports what happened to a row, Row counts data rows from 1 and
+// Action is create or update, or empty when the row has errors.
+type BikeImportRowResponse struct {
+	Row    int               `json:"row"`
+	Name   string            `json:"name"`
+	Action string            `json:"action"`
+	BikeID uint              `json:"bike_id,omitempty"`
+	Errors map[string]string `json:"errors,omitempty"`
+}
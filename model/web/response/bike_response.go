@@ -2,23 +2,98 @@ package response
 
 import "time"
 
+// BikeResponse has the list price in Price and the price after the best active discount in SalePrice.
+// PriceHistory and Discounts are only filled for admins.
 type BikeResponse struct {
-	ID          uint      `json:"id"`
-	CategoryID  uint      `json:"category_id"`
-	Name        string    `json:"name"`
-	Brand       string    `json:"brand"`
-	Description string    `json:"description"`
-	Year        int       `json:"year"`
-	Price       int       `json:"price"`
-	ImageUrl    string    `json:"image_url"`
-	Stock       int       `json:"stock"`
-	IsAvailable bool      `json:"is_available"`
-	Rating      int       `json:"rating"`
-	Reviewers   int       `json:"reviewers"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID              uint                        `json:"id"`
+	CategoryID      uint                        `json:"category_id"`
+	Name            string                      `json:"name"`
+	BrandID         *uint                       `json:"brand_id"`
+	Brand           string                      `json:"brand"`
+	Description     string                      `json:"description"`
+	Year            int                         `json:"year"`
+	Price           int                         `json:"price"`
+	SalePrice       int                         `json:"sale_price" gorm:"-"`
+	Discount        *DiscountResponse           `json:"discount" gorm:"-"`
+	ImageUrl        string                      `json:"image_url"`
+	Stock           int                         `json:"stock"`
+	IsAvailable     bool                        `json:"is_available"`
+	Rating          int                         `json:"rating"`
+	Reviewers       int                         `json:"reviewers"`
+	AverageRating   float64                     `json:"average_rating"`
+	RatingHistogram BikeRatingHistogramResponse `json:"rating_histogram" gorm:"embedded"`
+	CreatedAt       time.Time                   `json:"created_at"`
+	UpdatedAt       time.Time                   `json:"updated_at"`
+	ArchivedAt      *time.Time                  `json:"archived_at" gorm:"column:deleted_at"`
+	Variants        []BikeVariantResponse       `json:"variants" gorm:"-"`
+	Images          []BikeImageResponse         `json:"images,omitempty" gorm:"-"`
+	Specs           []BikeSpecResponse          `json:"specs,omitempty" gorm:"-"`
+	PriceHistory    []PriceHistoryResponse      `json:"price_history,omitempty" gorm:"-"`
+	Discounts       []DiscountResponse          `json:"discounts,omitempty" gorm:"-"`
+}
+
+// BikeRatingHistogramResponse counts the reviews of a bike per star rating.
+type BikeRatingHistogramResponse struct {
+	Stars1 int `json:"1"`
+	Stars2 int `json:"2"`
+	Stars3 int `json:"3"`
+	Stars4 int `json:"4"`
+	Stars5 int `json:"5"`
 }
 
 type BikeListResponse struct {
 	Bikes []BikeResponse `json:"bikes"`
 }
+
+// RelatedBikeResponse is a bike recommended next to another one. Reason is "bought_together" for bikes
+// bought in the same transactions, BoughtTogether times, and "similar" for bikes of the same category
+// at a similar price.
+type RelatedBikeResponse struct {
+	BikeResponse
+	Reason         string `json:"reason"`
+	BoughtTogether int    `json:"bought_together"`
+}
+
+type RecomputeRelatedBikesResponse struct {
+	Relations int64 `json:"relations"`
+}
+
+// BikeComparisonResponse lines bikes up attribute by attribute. Every attribute has one value per bike,
+// in the order of Bikes.
+type BikeComparisonResponse struct {
+	Bikes      []BikeComparisonBikeResponse      `json:"bikes"`
+	Attributes []BikeComparisonAttributeResponse `json:"attributes"`
+}
+
+type BikeComparisonBikeResponse struct {
+	ID       uint   `json:"id"`
+	Name     string `json:"name"`
+	ImageUrl string `json:"image_url"`
+}
+
+// BikeComparisonAttributeResponse is one row of a comparison. A value is null for a bike without the
+// attribute, e.g. a spec of another category, and Differs tells whether the bikes' values aren't all the same.
+type BikeComparisonAttributeResponse struct {
+	Key     string `json:"key"`
+	Name    string `json:"name"`
+	Unit    string `json:"unit,omitempty"`
+	Values  []any  `json:"values"`
+	Differs bool   `json:"differs"`
+}
+
+// BikeSuggestionResponse is a search suggestion, a bike, brand or category whose name looks like what
+// was typed. Slug is set for brands and categories.
+type BikeSuggestionResponse struct {
+	Type       string              `json:"type"`
+	ID         uint                `json:"id"`
+	Text       string              `json:"text"`
+	Slug       string              `json:"slug,omitempty"`
+	Score      float64             `json:"score"`
+	Highlights []HighlightResponse `json:"highlights" gorm:"-"`
+}
+
+// HighlightResponse marks the characters of a text from Start up to but excluding End.
+type HighlightResponse struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
@@ -0,0 +1,17 @@
+package response
+
+type BikeVariantResponse struct {
+	ID                uint   `json:"id"`
+	BikeID            uint   `json:"bike_id"`
+	SKU               string `json:"sku"`
+	FrameSize         string `json:"frame_size"`
+	Color             string `json:"color"`
+	Price             int    `json:"price"`
+	SalePrice         int    `json:"sale_price"`
+	DiscountID        *uint  `json:"discount_id"`
+	PriceOverride     *int   `json:"price_override"`
+	Stock             int    `json:"stock"`
+	IsAvailable       bool   `json:"is_available"`
+	LowStockThreshold int    `json:"low_stock_threshold"`
+	AutoAvailability  bool   `json:"auto_availability"`
+}
@@ -0,0 +1,15 @@
+package response
+
+import "time"
+
+type BrandResponse struct {
+	ID          uint      `json:"id"`
+	Name        string    `json:"name"`
+	Slug        string    `json:"slug"`
+	LogoUrl     string    `json:"logo_url"`
+	Country     string    `json:"country"`
+	Description string    `json:"description"`
+	BikeCount   int64     `json:"bike_count"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
@@ -6,6 +6,7 @@ type CartItemResponse struct {
 	ID        uint      `json:"id"`
 	CartID    uint      `json:"cart_id"`
 	BikeID    uint      `json:"bike_id"`
+	VariantID uint      `json:"variant_id"`
 	Quantity  int       `json:"quantity"`
 	Price     float64   `json:"price,omitempty"`
 	CreatedAt time.Time `json:"created_at,omitempty"`
@@ -16,6 +17,7 @@ type GetUserCartItemResponse struct {
 	ID        uint                        `json:"id"`
 	CartID    uint                        `json:"cart_id"`
 	Bike      GetUserCartItemBikeResponse `json:"bike"`
+	Variant   BikeVariantResponse         `json:"variant"`
 	Quantity  int                         `json:"quantity"`
 	Price     float64                     `json:"price,omitempty"`
 	CreatedAt time.Time                   `json:"created_at,omitempty"`
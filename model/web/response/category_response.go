@@ -1,6 +1,27 @@
 package response
 
+import "time"
+
 type CategoryResponse struct {
-	ID   int    `gorm:"primaryKey;autoIncrement"`
-	Name string `gorm:"unique;not null;type:varchar(20)"`
-}
\ No newline at end of file
+	ID          int `gorm:"primaryKey;autoIncrement"`
+	ParentID    *uint
+	Name        string `gorm:"unique;not null;type:varchar(20)"`
+	Slug        string
+	Description string
+	ImageUrl    string
+	ArchivedAt  *time.Time `gorm:"column:deleted_at"`
+}
+
+// CategoryTreeResponse is a category with its subcategories. BikeCount counts the bikes directly in
+// the category, TotalBikeCount also those in its subcategories.
+type CategoryTreeResponse struct {
+	ID             uint                   `json:"id"`
+	ParentID       *uint                  `json:"parent_id"`
+	Name           string                 `json:"name"`
+	Slug           string                 `json:"slug"`
+	Description    string                 `json:"description"`
+	ImageUrl       string                 `json:"image_url"`
+	BikeCount      int64                  `json:"bike_count"`
+	TotalBikeCount int64                  `json:"total_bike_count"`
+	Children       []CategoryTreeResponse `json:"children"`
+}
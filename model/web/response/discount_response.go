@@ -0,0 +1,27 @@
+package response
+
+import "time"
+
+type DiscountResponse struct {
+	ID         uint       `json:"id"`
+	Name       string     `json:"name"`
+	Type       string     `json:"type"`
+	Value      int        `json:"value"`
+	TargetType string     `json:"target_type"`
+	TargetID   uint       `json:"target_id"`
+	StartsAt   time.Time  `json:"starts_at"`
+	EndsAt     *time.Time `json:"ends_at"`
+	Status     string     `json:"status"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+type PriceHistoryResponse struct {
+	ID            uint      `json:"id"`
+	BikeVariantID *uint     `json:"bike_variant_id"`
+	OldPrice      *int      `json:"old_price"`
+	NewPrice      *int      `json:"new_price"`
+	Source        string    `json:"source"`
+	ActorID       *uint     `json:"actor_id"`
+	CreatedAt     time.Time `json:"created_at"`
+}
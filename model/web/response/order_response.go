@@ -1,22 +1,37 @@
 package response
 
 type OrderResponse struct {
-	ID         int `gorm:"primaryKey;autoIncrement"`
-	BikeID     int `gorm:"type:int;not null"`
-	Quantity   int `gorm:"type:int;not null"`
-	TotalPrice int `gorm:"type:int;not null"`
+	ID          int    `json:"id"`
+	BikeID      int    `json:"bike_id"`
+	VariantID   uint   `json:"variant_id"`
+	BikeName    string `json:"bike_name"`
+	BikeBrand   string `json:"bike_brand"`
+	SKU         string `json:"sku"`
+	VariantName string `json:"variant_name"`
+	Quantity    int    `json:"quantity"`
+	UnitPrice   int    `json:"unit_price"`
+	TotalPrice  int    `json:"total_price"`
 }
 
 type GetAllOrderResponse struct {
-	ID         int                     `json:"id"`
-	Bike       GetAllOrderBikeResponse `json:"bike"`
-	Quantity   int                     `json:"quantity"`
-	TotalPrice int                     `json:"total_price"`
-	IsReviewed bool                    `json:"is_reviewed"`
+	ID         int                        `json:"id"`
+	Bike       GetAllOrderBikeResponse    `json:"bike"`
+	Variant    GetAllOrderVariantResponse `json:"variant"`
+	Quantity   int                        `json:"quantity"`
+	UnitPrice  int                        `json:"unit_price"`
+	TotalPrice int                        `json:"total_price"`
+	IsReviewed bool                       `json:"is_reviewed"`
 }
 
 type GetAllOrderBikeResponse struct {
 	ID       uint   `json:"id"`
 	Name     string `json:"name"`
+	Brand    string `json:"brand"`
 	ImageUrl string `json:"image_url"`
 }
+
+type GetAllOrderVariantResponse struct {
+	ID   uint   `json:"id"`
+	SKU  string `json:"sku"`
+	Name string `json:"name"`
+}
@@ -23,3 +23,7 @@ type GetAllReviewResponse struct {
 	BikeName     string    `json:"bike_name"`
 	UserUsername string    `json:"user_username"`
 }
+
+type RecomputeBikeRatingsResponse struct {
+	Corrected int64 `json:"corrected"`
+}
@@ -0,0 +1,20 @@
+package response
+
+type SpecAttributeResponse struct {
+	ID         uint     `json:"id"`
+	CategoryID uint     `json:"category_id"`
+	Key        string   `json:"key"`
+	Name       string   `json:"name"`
+	Type       string   `json:"type"`
+	Unit       string   `json:"unit"`
+	Options    []string `json:"options"`
+}
+
+// BikeSpecResponse is a spec of a bike, Value is a string, a number or a boolean depending on Type.
+type BikeSpecResponse struct {
+	Key   string `json:"key"`
+	Name  string `json:"name"`
+	Type  string `json:"type"`
+	Unit  string `json:"unit"`
+	Value any    `json:"value"`
+}
@@ -0,0 +1,20 @@
+package response
+
+import "time"
+
+type StockAlertResponse struct {
+	ID             uint       `json:"id"`
+	BikeID         uint       `json:"bike_id"`
+	BikeName       string     `json:"bike_name"`
+	BikeVariantID  uint       `json:"bike_variant_id"`
+	SKU            string     `json:"sku"`
+	Type           string     `json:"type"`
+	Stock          int        `json:"stock"`
+	Threshold      int        `json:"threshold"`
+	Status         string     `json:"status"`
+	NotifiedAt     *time.Time `json:"notified_at"`
+	AcknowledgedAt *time.Time `json:"acknowledged_at"`
+	AcknowledgedBy *uint      `json:"acknowledged_by"`
+	ResolvedAt     *time.Time `json:"resolved_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
@@ -0,0 +1,25 @@
+package response
+
+import "time"
+
+type StockMovementResponse struct {
+	ID            uint      `json:"id"`
+	BikeID        uint      `json:"bike_id"`
+	BikeVariantID uint      `json:"bike_variant_id"`
+	Type          string    `json:"type"`
+	Quantity      int       `json:"quantity"`
+	Reference     string    `json:"reference"`
+	Note          string    `json:"note"`
+	ActorID       *uint     `json:"actor_id"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// StockDiscrepancyResponse is a variant whose stock didn't match its ledger, Stock is what it was
+// before being reconciled to LedgerStock.
+type StockDiscrepancyResponse struct {
+	BikeID        uint   `json:"bike_id"`
+	BikeVariantID uint   `json:"bike_variant_id"`
+	SKU           string `json:"sku"`
+	Stock         int    `json:"stock"`
+	LedgerStock   int    `json:"ledger_stock"`
+}
@@ -3,14 +3,26 @@ package response
 import "time"
 
 type TransactionResponse struct {
-	ID          int             `json:"id"`
-	TotalPrice  int             `json:"total_price"`
-	UserID      int             `json:"user_id"`
-	Status      string          `json:"status"`
-	PaymentLink string          `json:"payment_link"`
-	Orders      []OrderResponse `json:"orders"`
-	CreatedAt   string          `json:"created_at"`
-	UpdatedAt   string          `json:"upodated_at"`
+	ID              int                                `json:"id"`
+	TotalPrice      int                                `json:"total_price"`
+	UserID          int                                `json:"user_id"`
+	Status          string                             `json:"status"`
+	PaymentLink     string                             `json:"payment_link"`
+	ExpiresAt       *time.Time                         `json:"expires_at"`
+	Orders          []OrderResponse                    `json:"orders"`
+	StatusHistories []TransactionStatusHistoryResponse `json:"status_histories"`
+	Refunds         []RefundResponse                   `json:"refunds"`
+	CreatedAt       string                             `json:"created_at"`
+	UpdatedAt       string                             `json:"upodated_at"`
+}
+
+type TransactionStatusHistoryResponse struct {
+	FromStatus string    `json:"from_status"`
+	ToStatus   string    `json:"to_status"`
+	ActorType  string    `json:"actor_type"`
+	ActorID    *uint     `json:"actor_id"`
+	Reason     string    `json:"reason"`
+	CreatedAt  time.Time `json:"created_at"`
 }
 
 type UserTransactionResponse struct {
@@ -20,7 +32,10 @@ type UserTransactionResponse struct {
 }
 
 type CreateTransactionResponse struct {
-	TransactionID int `json:"transaction_id"`
+	TransactionID int        `json:"transaction_id"`
+	TotalPrice    int        `json:"total_price"`
+	PaymentLink   string     `json:"payment_link"`
+	ExpiresAt     *time.Time `json:"expires_at"`
 }
 
 type GetAllTransactionResponse struct {
@@ -28,11 +43,38 @@ type GetAllTransactionResponse struct {
 	TotalPrice int                           `json:"total_price"`
 	User       GetAllTransactionUserResponse `json:"user"`
 	Status     string                        `json:"status"`
+	ExpiresAt  *time.Time                    `json:"expires_at"`
 	Orders     []GetAllOrderResponse         `json:"orders"`
 	CreatedAt  time.Time                     `json:"created_at"`
 	UpdatedAt  time.Time                     `json:"upodated_at"`
 }
 
+type RefundResponse struct {
+	ID               uint                 `json:"id"`
+	Amount           int                  `json:"amount"`
+	Reason           string               `json:"reason"`
+	Status           string               `json:"status"`
+	GatewayReference string               `json:"gateway_reference"`
+	Restock          bool                 `json:"restock"`
+	Items            []RefundItemResponse `json:"items"`
+	CreatedAt        time.Time            `json:"created_at"`
+}
+
+type RefundItemResponse struct {
+	OrderID  int `json:"order_id"`
+	Quantity int `json:"quantity"`
+	Amount   int `json:"amount"`
+}
+
+type InsufficientStockResponse struct {
+	BikeID    int    `json:"bike_id"`
+	VariantID uint   `json:"variant_id"`
+	SKU       string `json:"sku"`
+	Name      string `json:"name"`
+	Requested int    `json:"requested"`
+	Available int    `json:"available"`
+}
+
 type GetAllTransactionUserResponse struct {
 	ID       uint   `json:"id"`
 	Username string `json:"username"`
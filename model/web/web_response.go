@@ -12,6 +12,7 @@ type Metadata struct {
 	Limit      *int   `json:"limit" form:"page" extensions:"x-order=1"`
 	TotalPages *int   `json:"total_pages" extensions:"x-order=2"`
 	TotalData  *int64 `json:"total_data" extensions:"x-order=3"`
+	Facets     any    `json:"facets,omitempty" extensions:"x-order=4"`
 }
 
 type WebError struct {
@@ -0,0 +1,780 @@
+package services
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"github.com/gowesmart/api-gowesmart/exceptions"
+	"github.com/gowesmart/api-gowesmart/model/entity"
+	"github.com/gowesmart/api-gowesmart/model/web/request"
+	"github.com/gowesmart/api-gowesmart/model/web/response"
+	"github.com/gowesmart/api-gowesmart/utils"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	bikeImportActionCreate = "create"
+	bikeImportActionUpdate = "update"
+	bikeImportMaxSize      = 10 << 20
+	bikeExportBatchSize    = 100
+)
+
+// bikeCSVColumns are the columns of a catalogue CSV, which holds one row per variant.
+var bikeCSVColumns = []string{"name", "brand", "category_id", "description", "year", "price", "image_url", "stock", "is_available", "sku", "frame_size", "color", "variant_price"}
+
+type BikeCatalogService struct{}
+
+func NewBikeCatalogService() *BikeCatalogService {
+	return &BikeCatalogService{}
+}
+
+// bikeImportPlan is an import row together with the bike it resolves to.
+type bikeImportPlan struct {
+	row      request.BikeImportRow
+	result   response.BikeImportRowResponse
+	variants []request.CreateBikeVariantRequest
+	// key identifies the bike across the rows of an import, including bikes the import creates
+	key string
+}
+
+func (plan *bikeImportPlan) fail(field, message string) {
+	if plan.result.Errors == nil {
+		plan.result.Errors = make(map[string]string)
+	}
+	// keep the first problem of a field, e.g. a parse error over the validation error it causes
+	if _, ok := plan.result.Errors[field]; !ok {
+		plan.result.Errors[field] = message
+	}
+}
+
+// ImportBikes upserts bikes by SKU or name. Rows are validated and resolved up front, so either every
+// row is imported in a single transaction or nothing is and the invalid rows are reported. A dry run
+// only reports what would happen.
+func (service *BikeCatalogService) ImportBikes(c *gin.Context, format string, dryRun bool) (*response.BikeImportResponse, error) {
+	db, logger := utils.GetDBAndLogger(c)
+
+	body := http.MaxBytesReader(c.Writer, c.Request.Body, bikeImportMaxSize)
+
+	var plans []*bikeImportPlan
+	var err error
+	if format == "csv" {
+		plans, err = parseBikeCSV(body)
+	} else {
+		plans, err = parseBikeJSON(body)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(plans) == 0 {
+		return nil, exceptions.NewCustomError(http.StatusBadRequest, "Import has no rows")
+	}
+
+	for _, plan := range plans {
+		// rows that could not be read at all have nothing to validate
+		if _, unreadable := plan.result.Errors["row"]; !unreadable {
+			validateBikeImportRow(plan)
+		}
+	}
+
+	if err := resolveBikeImport(db, plans); err != nil {
+		return nil, err
+	}
+
+	res := response.BikeImportResponse{DryRun: dryRun}
+	var failed []response.BikeImportRowResponse
+	for _, plan := range plans {
+		if plan.result.Errors != nil {
+			failed = append(failed, plan.result)
+		}
+	}
+
+	if len(failed) > 0 && !dryRun {
+		return nil, exceptions.NewDetailedError(http.StatusBadRequest, "Import has invalid rows, nothing was imported", failed)
+	}
+
+	if !dryRun {
+		actor := newPriceChangeActor(c, entity.PriceChangeSourceImport)
+		err = db.Transaction(func(tx *gorm.DB) error {
+			bikeIDs := make(map[string]uint)
+			for _, plan := range plans {
+				if err := applyBikeImport(tx, actor, plan, bikeIDs); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+
+		if err != nil {
+			logger.Error("failed to import bikes", zap.Error(err))
+			return nil, err
+		}
+	}
+
+	res.Rows = make([]response.BikeImportRowResponse, 0, len(plans))
+	for _, plan := range plans {
+		switch plan.result.Action {
+		case bikeImportActionCreate:
+			res.Created++
+		case bikeImportActionUpdate:
+			res.Updated++
+		default:
+			res.Failed++
+		}
+		res.Rows = append(res.Rows, plan.result)
+	}
+
+	logger.Info("success importing bikes", zap.Bool("dryRun", dryRun), zap.Int("created", res.Created), zap.Int("updated", res.Updated), zap.Int("failed", res.Failed))
+
+	return &res, nil
+}
+
+// ExportBikes streams the whole catalogue to w as CSV, one row per variant, or as a JSON array that
+// can be imported again.
+func (service *BikeCatalogService) ExportBikes(c *gin.Context, format string, w io.Writer) error {
+	db, logger := utils.GetDBAndLogger(c)
+
+	var write func(bikes []entity.Bike) error
+	var finish func() error
+
+	if format == "csv" {
+		writer := csv.NewWriter(w)
+		if err := writer.Write(bikeCSVColumns); err != nil {
+			return err
+		}
+
+		write = func(bikes []entity.Bike) error {
+			for _, bike := range bikes {
+				for _, variant := range bike.Variants {
+					if err := writer.Write(bikeCSVRecord(bike, variant)); err != nil {
+						return err
+					}
+				}
+			}
+			writer.Flush()
+			return writer.Error()
+		}
+		finish = func() error { return nil }
+	} else {
+		if _, err := io.WriteString(w, "["); err != nil {
+			return err
+		}
+
+		separator := "\n"
+		write = func(bikes []entity.Bike) error {
+			for _, bike := range bikes {
+				data, err := json.Marshal(toBikeExport(bike))
+				if err != nil {
+					return err
+				}
+				if _, err := io.WriteString(w, separator); err != nil {
+					return err
+				}
+				if _, err := w.Write(data); err != nil {
+					return err
+				}
+				separator = ",\n"
+			}
+			return nil
+		}
+		finish = func() error {
+			_, err := io.WriteString(w, "\n]\n")
+			return err
+		}
+	}
+
+	var bikes []entity.Bike
+	exported := 0
+
+	err := db.Preload("Variants", func(db *gorm.DB) *gorm.DB { return db.Order("id") }).
+		Preload("Specs.SpecAttribute").
+		FindInBatches(&bikes, bikeExportBatchSize, func(tx *gorm.DB, batch int) error {
+			if err := write(bikes); err != nil {
+				return err
+			}
+			exported += len(bikes)
+
+			if flusher, ok := w.(http.Flusher); ok {
+				flusher.Flush()
+			}
+			return nil
+		}).Error
+	if err == nil {
+		err = finish()
+	}
+
+	if err != nil {
+		logger.Error("failed to export bikes", zap.Int("exported", exported), zap.Error(err))
+		return err
+	}
+
+	logger.Info("success exporting bikes", zap.String("format", format), zap.Int("exported", exported))
+
+	return nil
+}
+
+func parseBikeJSON(body io.Reader) ([]*bikeImportPlan, error) {
+	var rows []json.RawMessage
+	if err := json.NewDecoder(body).Decode(&rows); err != nil {
+		return nil, bikeImportReadError(err, "Body must be a JSON array of bikes")
+	}
+
+	plans := make([]*bikeImportPlan, 0, len(rows))
+	for i, row := range rows {
+		plan := &bikeImportPlan{result: response.BikeImportRowResponse{Row: i + 1}}
+		if err := json.Unmarshal(row, &plan.row); err != nil {
+			plan.fail("row", "Invalid bike: "+err.Error())
+		}
+		plan.result.Name = plan.row.Name
+
+		plans = append(plans, plan)
+	}
+
+	return plans, nil
+}
+
+func parseBikeCSV(body io.Reader) ([]*bikeImportPlan, error) {
+	reader := csv.NewReader(body)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, exceptions.NewCustomError(http.StatusBadRequest, "CSV must start with a header row")
+	}
+	if err != nil {
+		return nil, bikeImportReadError(err, "Invalid CSV")
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, column := range header {
+		column = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(column, "\ufeff")))
+		if !slices.Contains(bikeCSVColumns, column) {
+			return nil, exceptions.NewCustomError(http.StatusBadRequest, fmt.Sprintf("Unknown CSV column %q, columns are %s", column, strings.Join(bikeCSVColumns, ", ")))
+		}
+		columns[column] = i
+	}
+
+	var plans []*bikeImportPlan
+	for row := 1; ; row++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, bikeImportReadError(err, "Invalid CSV")
+		}
+
+		plan := &bikeImportPlan{result: response.BikeImportRowResponse{Row: row}}
+		if len(record) != len(header) {
+			plan.fail("row", fmt.Sprintf("Expected %d columns, got %d", len(header), len(record)))
+		} else {
+			parseBikeCSVRecord(plan, columns, record)
+		}
+		plan.result.Name = plan.row.Name
+
+		plans = append(plans, plan)
+	}
+
+	return plans, nil
+}
+
+func parseBikeCSVRecord(plan *bikeImportPlan, columns map[string]int, record []string) {
+	value := func(column string) string {
+		if i, ok := columns[column]; ok {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+	intValue := func(column string) int {
+		v := value(column)
+		if v == "" {
+			return 0
+		}
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			plan.fail(column, column+" must be an integer")
+		}
+		return n
+	}
+
+	row := &plan.row
+	row.Name = value("name")
+	row.Brand = value("brand")
+	row.Description = value("description")
+	row.ImageUrl = value("image_url")
+	row.Year = intValue("year")
+	row.Price = intValue("price")
+	row.Stock = intValue("stock")
+	row.SKU = value("sku")
+	row.FrameSize = value("frame_size")
+	row.Color = value("color")
+
+	if categoryID := intValue("category_id"); categoryID > 0 {
+		row.CategoryID = uint(categoryID)
+	}
+
+	// an empty is_available means available, the same as a variant created without it
+	row.IsAvailable = true
+	if v := value("is_available"); v != "" {
+		isAvailable, err := strconv.ParseBool(v)
+		if err != nil {
+			plan.fail("is_available", "is_available must be true or false")
+		}
+		row.IsAvailable = isAvailable
+	}
+
+	if value("variant_price") != "" {
+		price := intValue("variant_price")
+		row.VariantPrice = &price
+	}
+}
+
+func bikeImportReadError(err error, message string) error {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return exceptions.NewCustomError(http.StatusRequestEntityTooLarge, fmt.Sprintf("Import must not be larger than %d bytes", maxBytesErr.Limit))
+	}
+
+	return exceptions.NewCustomError(http.StatusBadRequest, message+": "+err.Error())
+}
+
+// validateBikeImportRow applies the same rules as creating a bike through the API.
+func validateBikeImportRow(plan *bikeImportPlan) {
+	if err := binding.Validator.ValidateStruct(&plan.row); err != nil {
+		var validationErrs validator.ValidationErrors
+		if !errors.As(err, &validationErrs) {
+			plan.fail("row", err.Error())
+			return
+		}
+		for field, message := range exceptions.ValidationErrorMessages(validationErrs) {
+			plan.fail(field, message)
+		}
+	}
+
+	if plan.result.Errors != nil {
+		return
+	}
+
+	if plan.row.SKU != "" && len(plan.row.Variants) > 0 {
+		plan.fail("sku", "Use either sku or variants")
+		return
+	}
+
+	if plan.row.SKU != "" {
+		isAvailable := plan.row.IsAvailable
+		plan.variants = []request.CreateBikeVariantRequest{{
+			SKU:         plan.row.SKU,
+			FrameSize:   plan.row.FrameSize,
+			Color:       plan.row.Color,
+			Price:       plan.row.VariantPrice,
+			Stock:       plan.row.Stock,
+			IsAvailable: &isAvailable,
+		}}
+	} else {
+		plan.variants = plan.row.Variants
+	}
+
+	skus := make(map[string]bool, len(plan.variants))
+	for _, variant := range plan.variants {
+		if skus[variant.SKU] {
+			plan.fail("sku", fmt.Sprintf("SKU %s is used more than once", variant.SKU))
+			return
+		}
+		skus[variant.SKU] = true
+	}
+}
+
+// resolveBikeImport matches the valid rows to existing bikes, first by SKU and then by name, and decides
+// whether each row creates or updates a bike. Rows for the same bike update what earlier rows created.
+func resolveBikeImport(db *gorm.DB, plans []*bikeImportPlan) error {
+	var names, skus, brandSlugs []string
+	var categoryIDs, brandIDs []uint
+	for _, plan := range plans {
+		if plan.result.Errors != nil {
+			continue
+		}
+		names = append(names, plan.row.Name)
+		categoryIDs = append(categoryIDs, plan.row.CategoryID)
+		brandIDs = append(brandIDs, plan.row.BrandID)
+		brandSlugs = append(brandSlugs, utils.Slugify(plan.row.Brand))
+		for _, variant := range plan.variants {
+			skus = append(skus, variant.SKU)
+		}
+	}
+
+	var existingCategoryIDs []uint
+	if err := db.Model(&entity.Category{}).Where("id IN ?", categoryIDs).Pluck("id", &existingCategoryIDs).Error; err != nil {
+		return err
+	}
+	categories := make(map[uint]bool, len(existingCategoryIDs))
+	for _, id := range existingCategoryIDs {
+		categories[id] = true
+	}
+
+	var brands []entity.Brand
+	if err := db.Select("id, name, slug").Where("id IN ? OR slug IN ?", brandIDs, brandSlugs).Find(&brands).Error; err != nil {
+		return err
+	}
+	brandsByID := make(map[uint]entity.Brand, len(brands))
+	brandsBySlug := make(map[string]entity.Brand, len(brands))
+	for _, brand := range brands {
+		brandsByID[brand.ID] = brand
+		brandsBySlug[brand.Slug] = brand
+	}
+
+	var variants []entity.BikeVariant
+	if err := db.Select("id, bike_id, sku").Where("sku IN ?", skus).Find(&variants).Error; err != nil {
+		return err
+	}
+	bikeIDBySKU := make(map[string]uint, len(variants))
+	for _, variant := range variants {
+		bikeIDBySKU[variant.SKU] = variant.BikeID
+	}
+
+	// archived bikes keep their names and SKUs, so they are matched too and reported
+	var bikes []entity.Bike
+	if err := db.Unscoped().Select("id, name, deleted_at").
+		Where("name IN ? OR id IN ?", names, bikeIDsOf(variants)).
+		Find(&bikes).Error; err != nil {
+		return err
+	}
+	bikeIDByName := make(map[string]uint, len(bikes))
+	archivedBikes := make(map[uint]bool)
+	bikeIDs := make([]uint, 0, len(bikes))
+	for _, bike := range bikes {
+		bikeIDByName[bike.Name] = bike.ID
+		archivedBikes[bike.ID] = bike.DeletedAt.Valid
+		bikeIDs = append(bikeIDs, bike.ID)
+	}
+
+	var counts []struct {
+		BikeID uint
+		Count  int
+	}
+	if err := db.Model(&entity.BikeVariant{}).
+		Select("bike_id, COUNT(*) AS count").
+		Where("bike_id IN ?", bikeIDs).
+		Group("bike_id").
+		Scan(&counts).Error; err != nil {
+		return err
+	}
+	existingVariantCounts := make(map[uint]int, len(counts))
+	for _, count := range counts {
+		existingVariantCounts[count.BikeID] = count.Count
+	}
+
+	specAttributes := make(map[uint][]entity.SpecAttribute)
+	nameOwners := make(map[string]string)
+	skuOwners := make(map[string]string)
+	variantCounts := make(map[string]int)
+
+	for _, plan := range plans {
+		if plan.result.Errors != nil {
+			continue
+		}
+
+		if !categories[plan.row.CategoryID] {
+			plan.fail("category_id", "Category not found")
+		}
+
+		brand, ok := brandsBySlug[utils.Slugify(plan.row.Brand)]
+		if plan.row.BrandID != 0 {
+			brand, ok = brandsByID[plan.row.BrandID]
+		}
+		if ok {
+			plan.row.BrandID = brand.ID
+			plan.row.Brand = brand.Name
+		} else {
+			plan.fail("brand", "Brand not found")
+		}
+
+		if len(plan.row.Specs) > 0 && categories[plan.row.CategoryID] {
+			attributes, ok := specAttributes[plan.row.CategoryID]
+			if !ok {
+				var err error
+				if attributes, err = findCategorySpecAttributes(db, plan.row.CategoryID); err != nil {
+					return err
+				}
+				specAttributes[plan.row.CategoryID] = attributes
+			}
+
+			_, _, invalid := checkBikeSpecs(attributes, plan.row.Specs)
+			for key, message := range invalid {
+				plan.fail("specs."+key, message)
+			}
+		}
+
+		var bikeID uint
+		for _, variant := range plan.variants {
+			id, ok := bikeIDBySKU[variant.SKU]
+			if !ok {
+				continue
+			}
+			if bikeID != 0 && bikeID != id {
+				plan.fail("sku", "SKUs belong to different bikes")
+				break
+			}
+			bikeID = id
+		}
+
+		if id, ok := bikeIDByName[plan.row.Name]; ok {
+			if bikeID != 0 && bikeID != id {
+				plan.fail("name", fmt.Sprintf("Name belongs to bike %d but the SKU belongs to bike %d", id, bikeID))
+			}
+			bikeID = id
+		}
+
+		if archivedBikes[bikeID] {
+			plan.fail("name", fmt.Sprintf("Bike %d is archived, restore it before importing it", bikeID))
+		}
+
+		if plan.result.Errors != nil {
+			continue
+		}
+
+		key := "name:" + plan.row.Name
+		if bikeID != 0 {
+			key = fmt.Sprintf("id:%d", bikeID)
+		}
+
+		if owner, ok := nameOwners[plan.row.Name]; ok && owner != key {
+			plan.fail("name", "Name is used by another bike in this import")
+		}
+
+		newVariants := 0
+		for _, variant := range plan.variants {
+			if owner, ok := skuOwners[variant.SKU]; ok && owner != key {
+				plan.fail("sku", fmt.Sprintf("SKU %s is used by another bike in this import", variant.SKU))
+			}
+			if _, ok := bikeIDBySKU[variant.SKU]; !ok && skuOwners[variant.SKU] == "" {
+				newVariants++
+			}
+		}
+
+		variantCount, seen := variantCounts[key]
+		if !seen {
+			variantCount = existingVariantCounts[bikeID]
+		}
+
+		exists := seen || bikeID != 0
+		if exists && len(plan.variants) == 0 && variantCount > 1 {
+			plan.fail("sku", "SKU is required to update the stock of a bike with several variants")
+		}
+
+		if plan.result.Errors != nil {
+			continue
+		}
+
+		plan.key = key
+		plan.result.BikeID = bikeID
+		plan.result.Action = bikeImportActionCreate
+		if exists {
+			plan.result.Action = bikeImportActionUpdate
+		}
+
+		// a new bike without variants gets a default one
+		if !exists && len(plan.variants) == 0 {
+			newVariants = 1
+		}
+		variantCounts[key] = variantCount + newVariants
+
+		nameOwners[plan.row.Name] = key
+		for _, variant := range plan.variants {
+			skuOwners[variant.SKU] = key
+		}
+	}
+
+	return nil
+}
+
+func bikeIDsOf(variants []entity.BikeVariant) []uint {
+	bikeIDs := make([]uint, 0, len(variants))
+	for _, variant := range variants {
+		bikeIDs = append(bikeIDs, variant.BikeID)
+	}
+	return bikeIDs
+}
+
+// applyBikeImport writes a resolved row, bikeIDs tracks the bikes created by earlier rows of the import.
+func applyBikeImport(tx *gorm.DB, actor priceChangeActor, plan *bikeImportPlan, bikeIDs map[string]uint) error {
+	bikeID := plan.result.BikeID
+	if bikeID == 0 {
+		bikeID = bikeIDs[plan.key]
+	}
+
+	if bikeID == 0 {
+		bikeReq := plan.row.CreateBikeRequest
+		bikeReq.Variants = plan.variants
+
+		bike, err := createBike(tx, actor, &bikeReq)
+		if err != nil {
+			return err
+		}
+
+		bikeIDs[plan.key] = bike.ID
+		plan.result.BikeID = bike.ID
+		return nil
+	}
+
+	plan.result.BikeID = bikeID
+
+	var bike entity.Bike
+	if err := tx.Select("id, price").Take(&bike, bikeID).Error; err != nil {
+		return err
+	}
+
+	if err := recordPriceChange(tx, actor, bikeID, nil, &bike.Price, &plan.row.Price); err != nil {
+		return err
+	}
+
+	if err := tx.Model(&entity.Bike{}).Where("id = ?", bikeID).Updates(map[string]any{
+		"category_id": plan.row.CategoryID,
+		"name":        plan.row.Name,
+		"brand_id":    plan.row.BrandID,
+		"brand":       plan.row.Brand,
+		"description": plan.row.Description,
+		"year":        plan.row.Year,
+		"price":       plan.row.Price,
+		"image_url":   plan.row.ImageUrl,
+	}).Error; err != nil {
+		return err
+	}
+
+	if err := dropInapplicableBikeSpecs(tx, bikeID, plan.row.CategoryID); err != nil {
+		return err
+	}
+
+	if err := saveBikeSpecs(tx, bikeID, plan.row.CategoryID, plan.row.Specs); err != nil {
+		return err
+	}
+
+	// the imported stock is a count, the ledger gets the difference as an adjustment
+	stockCount := stockChange{Type: entity.StockMovementAdjustment, Note: "import", ActorID: actor.ID}
+
+	// resolveBikeImport only lets rows without variants through for bikes with a single variant
+	if len(plan.variants) == 0 {
+		var variant entity.BikeVariant
+		if err := tx.Select("id").Where("bike_id = ?", bikeID).Take(&variant).Error; err != nil {
+			return err
+		}
+
+		if err := setVariantStock(tx, variant.ID, plan.row.Stock, stockCount); err != nil {
+			return err
+		}
+
+		if err := tx.Model(&variant).Update("is_available", plan.row.IsAvailable).Error; err != nil {
+			return err
+		}
+	}
+
+	for _, variantReq := range plan.variants {
+		var variant entity.BikeVariant
+		err := tx.Select("id, price").Where("sku = ?", variantReq.SKU).Take(&variant).Error
+		if err == gorm.ErrRecordNotFound {
+			if _, err := createBikeVariants(tx, actor, bikeID, []request.CreateBikeVariantRequest{variantReq}); err != nil {
+				return err
+			}
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		isAvailable := true
+		if variantReq.IsAvailable != nil {
+			isAvailable = *variantReq.IsAvailable
+		}
+
+		if err := recordPriceChange(tx, actor, bikeID, &variant.ID, variant.Price, variantReq.Price); err != nil {
+			return err
+		}
+
+		if err := tx.Model(&variant).Updates(map[string]any{
+			"frame_size":   variantReq.FrameSize,
+			"color":        variantReq.Color,
+			"price":        variantReq.Price,
+			"is_available": isAvailable,
+		}).Error; err != nil {
+			return err
+		}
+
+		if err := setVariantStock(tx, variant.ID, variantReq.Stock, stockCount); err != nil {
+			return err
+		}
+	}
+
+	return syncBikeStock(tx, bikeID)
+}
+
+// toBikeExport describes a bike the way it is imported, so an export can be imported again.
+func toBikeExport(bike entity.Bike) request.CreateBikeRequest {
+	bikeExport := request.CreateBikeRequest{
+		CategoryID:  bike.CategoryID,
+		Name:        bike.Name,
+		Brand:       bike.Brand,
+		Description: bike.Description,
+		Year:        bike.Year,
+		Price:       bike.Price,
+		ImageUrl:    bike.ImageUrl,
+		Stock:       bike.Stock,
+		IsAvailable: bike.IsAvailable,
+		Variants:    make([]request.CreateBikeVariantRequest, 0, len(bike.Variants)),
+	}
+
+	if len(bike.Specs) > 0 {
+		bikeExport.Specs = make(map[string]any, len(bike.Specs))
+		for _, spec := range bike.Specs {
+			bikeExport.Specs[spec.SpecAttribute.Key] = spec.SpecAttribute.Decode(&spec)
+		}
+	}
+
+	for _, variant := range bike.Variants {
+		isAvailable := variant.IsAvailable
+		bikeExport.Variants = append(bikeExport.Variants, request.CreateBikeVariantRequest{
+			SKU:         variant.SKU,
+			FrameSize:   variant.FrameSize,
+			Color:       variant.Color,
+			Price:       variant.Price,
+			Stock:       variant.Stock,
+			IsAvailable: &isAvailable,
+		})
+	}
+
+	return bikeExport
+}
+
+// bikeCSVRecord is the CSV row of a variant, in the order of bikeCSVColumns.
+func bikeCSVRecord(bike entity.Bike, variant entity.BikeVariant) []string {
+	variantPrice := ""
+	if variant.Price != nil {
+		variantPrice = strconv.Itoa(*variant.Price)
+	}
+
+	return []string{
+		bike.Name,
+		bike.Brand,
+		strconv.FormatUint(uint64(bike.CategoryID), 10),
+		bike.Description,
+		strconv.Itoa(bike.Year),
+		strconv.Itoa(bike.Price),
+		bike.ImageUrl,
+		strconv.Itoa(variant.Stock),
+		strconv.FormatBool(variant.IsAvailable),
+		variant.SKU,
+		variant.FrameSize,
+		variant.Color,
+		variantPrice,
+	}
+}
@@ -0,0 +1,356 @@
+package services
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"image"
+	"io"
+	"mime/multipart"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gowesmart/api-gowesmart/exceptions"
+	"github.com/gowesmart/api-gowesmart/model/entity"
+	"github.com/gowesmart/api-gowesmart/model/web/request"
+	"github.com/gowesmart/api-gowesmart/model/web/response"
+	"github.com/gowesmart/api-gowesmart/utils"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	bikeThumbnailSize = 320
+	// decoding an image takes about 4 bytes per pixel, refuse anything that would need more than ~160MB
+	bikeImageMaxPixels = 40_000_000
+)
+
+type BikeImageService struct {
+	storage utils.Storage
+	maxSize int64
+}
+
+func NewBikeImageService(storage utils.Storage, maxSize int64) *BikeImageService {
+	return &BikeImageService{
+		storage: storage,
+		maxSize: maxSize,
+	}
+}
+
+// UploadImage stores an image and its thumbnail and appends it to the bike's gallery. The first image
+// of a bike, or one uploaded with isPrimary, becomes the primary image.
+func (service *BikeImageService) UploadImage(c *gin.Context, bikeID uint, file *multipart.FileHeader, isPrimary bool) (*response.BikeImageResponse, error) {
+	db, logger := utils.GetDBAndLogger(c)
+
+	if file.Size > service.maxSize {
+		return nil, exceptions.NewCustomError(http.StatusRequestEntityTooLarge, fmt.Sprintf("Image must not be larger than %d bytes", service.maxSize))
+	}
+
+	data, err := readUpload(file, service.maxSize)
+	if err != nil {
+		return nil, err
+	}
+
+	// the declared content type comes from the client, trust the bytes instead
+	contentType := http.DetectContentType(data)
+	extension, ok := utils.ImageExtensions[contentType]
+	if !ok {
+		return nil, exceptions.NewCustomError(http.StatusUnsupportedMediaType, "Image must be a JPEG, PNG or WebP file")
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, exceptions.NewCustomError(http.StatusBadRequest, "Invalid image")
+	}
+	if config.Width*config.Height > bikeImageMaxPixels {
+		return nil, exceptions.NewCustomError(http.StatusBadRequest, "Image dimensions are too large")
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, exceptions.NewCustomError(http.StatusBadRequest, "Invalid image")
+	}
+
+	thumbnail, err := utils.Thumbnail(img, bikeThumbnailSize)
+	if err != nil {
+		return nil, err
+	}
+
+	var bike entity.Bike
+	if err := db.Select("id").Take(&bike, bikeID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, exceptions.NewCustomError(http.StatusNotFound, "Bike not found")
+		}
+		return nil, err
+	}
+
+	name, err := randomHex(16)
+	if err != nil {
+		return nil, err
+	}
+
+	bikeImage := entity.BikeImage{
+		BikeID:       bikeID,
+		Key:          fmt.Sprintf("bikes/%d/%s%s", bikeID, name, extension),
+		ThumbnailKey: fmt.Sprintf("bikes/%d/%s_thumb.jpg", bikeID, name),
+		ContentType:  contentType,
+		Size:         int64(len(data)),
+		Width:        config.Width,
+		Height:       config.Height,
+	}
+	bikeImage.URL = service.storage.URL(bikeImage.Key)
+	bikeImage.ThumbnailURL = service.storage.URL(bikeImage.ThumbnailKey)
+
+	if err := service.storage.Put(c.Request.Context(), bikeImage.Key, data, contentType); err != nil {
+		logger.Error("failed to store bike image", zap.Uint("bikeID", bikeID), zap.Error(err))
+		return nil, err
+	}
+	if err := service.storage.Put(c.Request.Context(), bikeImage.ThumbnailKey, thumbnail, "image/jpeg"); err != nil {
+		logger.Error("failed to store bike image thumbnail", zap.Uint("bikeID", bikeID), zap.Error(err))
+		service.deleteFiles(c, bikeImage.Key)
+		return nil, err
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		// serialises uploads to the same bike so positions and the primary image stay consistent
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").Take(&bike, bikeID).Error; err != nil {
+			return err
+		}
+
+		var last struct {
+			Count    int64
+			Position int
+		}
+		if err := tx.Model(&entity.BikeImage{}).
+			Select("COUNT(*) AS count, COALESCE(MAX(position), 0) AS position").
+			Where("bike_id = ?", bikeID).
+			Scan(&last).Error; err != nil {
+			return err
+		}
+
+		bikeImage.Position = last.Position + 1
+		if err := tx.Create(&bikeImage).Error; err != nil {
+			return err
+		}
+
+		if isPrimary || last.Count == 0 {
+			return setPrimaryBikeImage(tx, &bikeImage)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		service.deleteFiles(c, bikeImage.Key, bikeImage.ThumbnailKey)
+		return nil, err
+	}
+
+	logger.Info("success uploading bike image", zap.Uint("bikeID", bikeID), zap.Uint("imageID", bikeImage.ID))
+
+	res := toBikeImageResponse(bikeImage)
+	return &res, nil
+}
+
+func (service *BikeImageService) GetImages(c *gin.Context, bikeID uint) ([]response.BikeImageResponse, error) {
+	db, _ := utils.GetDBAndLogger(c)
+
+	return findBikeImages(db, bikeID)
+}
+
+func (service *BikeImageService) SetPrimaryImage(c *gin.Context, bikeID, imageID uint) (*response.BikeImageResponse, error) {
+	db, logger := utils.GetDBAndLogger(c)
+
+	var bikeImage entity.BikeImage
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("bike_id = ?", bikeID).Take(&bikeImage, imageID).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return exceptions.NewCustomError(http.StatusNotFound, "Bike image not found")
+			}
+			return err
+		}
+
+		return setPrimaryBikeImage(tx, &bikeImage)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("success setting primary bike image", zap.Uint("bikeID", bikeID), zap.Uint("imageID", imageID))
+
+	res := toBikeImageResponse(bikeImage)
+	return &res, nil
+}
+
+// ReorderImages puts the bike's gallery in the given order, which must list every image of the bike.
+func (service *BikeImageService) ReorderImages(c *gin.Context, bikeID uint, reorderReq *request.BikeImageReorderRequest) ([]response.BikeImageResponse, error) {
+	db, logger := utils.GetDBAndLogger(c)
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var imageIDs []uint
+		if err := tx.Model(&entity.BikeImage{}).Where("bike_id = ?", bikeID).Pluck("id", &imageIDs).Error; err != nil {
+			return err
+		}
+
+		current := make(map[uint]bool, len(imageIDs))
+		for _, id := range imageIDs {
+			current[id] = true
+		}
+
+		seen := make(map[uint]bool, len(reorderReq.ImageIDs))
+		for _, id := range reorderReq.ImageIDs {
+			if !current[id] || seen[id] {
+				return exceptions.NewCustomError(http.StatusBadRequest, fmt.Sprintf("Image %d is not in the bike's gallery or is listed twice", id))
+			}
+			seen[id] = true
+		}
+
+		if len(seen) != len(current) {
+			return exceptions.NewCustomError(http.StatusBadRequest, "Image ids must list every image of the bike")
+		}
+
+		for i, id := range reorderReq.ImageIDs {
+			if err := tx.Model(&entity.BikeImage{}).Where("id = ?", id).Update("position", i+1).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("success reordering bike images", zap.Uint("bikeID", bikeID))
+
+	return findBikeImages(db, bikeID)
+}
+
+// DeleteImage removes an image from the gallery, promoting the next image when it was the primary one.
+func (service *BikeImageService) DeleteImage(c *gin.Context, bikeID, imageID uint) error {
+	db, logger := utils.GetDBAndLogger(c)
+
+	var bikeImage entity.BikeImage
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("bike_id = ?", bikeID).Take(&bikeImage, imageID).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return exceptions.NewCustomError(http.StatusNotFound, "Bike image not found")
+			}
+			return err
+		}
+
+		if err := tx.Delete(&bikeImage).Error; err != nil {
+			return err
+		}
+
+		if !bikeImage.IsPrimary {
+			return nil
+		}
+
+		var next entity.BikeImage
+		err := tx.Where("bike_id = ?", bikeID).Order("position, id").First(&next).Error
+		if err == gorm.ErrRecordNotFound {
+			return tx.Model(&entity.Bike{}).Where("id = ? AND image_url = ?", bikeID, bikeImage.URL).Update("image_url", "").Error
+		}
+		if err != nil {
+			return err
+		}
+
+		return setPrimaryBikeImage(tx, &next)
+	})
+
+	if err != nil {
+		return err
+	}
+
+	service.deleteFiles(c, bikeImage.Key, bikeImage.ThumbnailKey)
+
+	logger.Info("success deleting bike image", zap.Uint("bikeID", bikeID), zap.Uint("imageID", imageID))
+
+	return nil
+}
+
+// deleteFiles removes stored files on a best effort basis, a leftover file is only wasted space.
+func (service *BikeImageService) deleteFiles(c *gin.Context, keys ...string) {
+	_, logger := utils.GetDBAndLogger(c)
+
+	for _, key := range keys {
+		if err := service.storage.Delete(c.Request.Context(), key); err != nil {
+			logger.Warn("failed to delete stored file", zap.String("key", key), zap.Error(err))
+		}
+	}
+}
+
+func setPrimaryBikeImage(tx *gorm.DB, bikeImage *entity.BikeImage) error {
+	if err := tx.Model(&entity.BikeImage{}).Where("bike_id = ? AND id <> ?", bikeImage.BikeID, bikeImage.ID).Update("is_primary", false).Error; err != nil {
+		return err
+	}
+
+	bikeImage.IsPrimary = true
+	if err := tx.Model(bikeImage).Update("is_primary", true).Error; err != nil {
+		return err
+	}
+
+	return tx.Model(&entity.Bike{}).Where("id = ?", bikeImage.BikeID).Update("image_url", bikeImage.URL).Error
+}
+
+func findBikeImages(db *gorm.DB, bikeID uint) ([]response.BikeImageResponse, error) {
+	var bikeImages []entity.BikeImage
+	if err := db.Where("bike_id = ?", bikeID).Order("position, id").Find(&bikeImages).Error; err != nil {
+		return nil, err
+	}
+
+	res := make([]response.BikeImageResponse, 0, len(bikeImages))
+	for _, bikeImage := range bikeImages {
+		res = append(res, toBikeImageResponse(bikeImage))
+	}
+
+	return res, nil
+}
+
+func toBikeImageResponse(bikeImage entity.BikeImage) response.BikeImageResponse {
+	return response.BikeImageResponse{
+		ID:           bikeImage.ID,
+		BikeID:       bikeImage.BikeID,
+		URL:          bikeImage.URL,
+		ThumbnailURL: bikeImage.ThumbnailURL,
+		ContentType:  bikeImage.ContentType,
+		Size:         bikeImage.Size,
+		Width:        bikeImage.Width,
+		Height:       bikeImage.Height,
+		Position:     bikeImage.Position,
+		IsPrimary:    bikeImage.IsPrimary,
+	}
+}
+
+func readUpload(file *multipart.FileHeader, maxSize int64) ([]byte, error) {
+	f, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	data, err := io.ReadAll(io.LimitReader(f, maxSize+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if int64(len(data)) > maxSize {
+		return nil, exceptions.NewCustomError(http.StatusRequestEntityTooLarge, fmt.Sprintf("Image must not be larger than %d bytes", maxSize))
+	}
+
+	return data, nil
+}
+
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
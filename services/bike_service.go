@@ -1,7 +1,16 @@
 package services
 
 import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
 	"github.com/gin-gonic/gin"
+	"github.com/gowesmart/api-gowesmart/exceptions"
 	"github.com/gowesmart/api-gowesmart/model/entity"
 	"github.com/gowesmart/api-gowesmart/model/web"
 	"github.com/gowesmart/api-gowesmart/model/web/request"
@@ -9,8 +18,12 @@ import (
 	"github.com/gowesmart/api-gowesmart/utils"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// bikeResponseColumns are the bike columns scanned into response.BikeResponse.
+const bikeResponseColumns = "id, category_id, name, brand_id, brand, description, year, price, image_url, stock, is_available, rating, reviewers, average_rating, stars1, stars2, stars3, stars4, stars5, created_at, updated_at, deleted_at"
+
 type BikeService struct{}
 
 func NewBikeService() *BikeService {
@@ -21,29 +34,38 @@ func (service *BikeService) CreateBike(c *gin.Context, bikeReq *request.CreateBi
 	db, logger := utils.GetDBAndLogger(c)
 
 	var res response.BikeResponse
-
-	bike := entity.Bike{
-		CategoryID:  bikeReq.CategoryID,
-		Name:        bikeReq.Name,
-		Brand:       bikeReq.Brand,
-		Description: bikeReq.Description,
-		Year:        bikeReq.Year,
-		Price:       bikeReq.Price,
-		ImageUrl:    bikeReq.ImageUrl,
-		Stock:       bikeReq.Stock,
-		IsAvailable: bikeReq.IsAvailable,
-	}
+	var bike *entity.Bike
 
 	err := db.Transaction(func(tx *gorm.DB) error {
-		if err := tx.Create(&bike).Error; err != nil {
+		var err error
+		bike, err = createBike(tx, newPriceChangeActor(c, entity.PriceChangeSourceAdmin), bikeReq)
+		if err != nil {
 			return err
 		}
 
 		if err := tx.Model(&entity.Bike{}).
-			Select("id, category_id, name, brand, description, year, price, image_url, stock, is_available, created_at, updated_at").
+			Select(bikeResponseColumns).
 			Take(&res, bike.ID).Error; err != nil {
 			return err
 		}
+
+		variants, err := findBikeVariants(tx, []uint{bike.ID})
+		if err != nil {
+			return err
+		}
+		res.Variants = variants[bike.ID]
+
+		res.Specs, err = findBikeSpecs(tx, bike.ID)
+		if err != nil {
+			return err
+		}
+
+		pricer, err := newBikePricer(tx)
+		if err != nil {
+			return err
+		}
+		pricer.applyToBike(&res)
+
 		return nil
 	})
 
@@ -56,6 +78,57 @@ func (service *BikeService) CreateBike(c *gin.Context, bikeReq *request.CreateBi
 	return &res, nil
 }
 
+// createBike stores a bike with the requested variants, or with a default variant holding the
+// request's stock when it has none.
+func createBike(tx *gorm.DB, actor priceChangeActor, bikeReq *request.CreateBikeRequest) (*entity.Bike, error) {
+	brand, err := findBikeBrand(tx, bikeReq.BrandID, bikeReq.Brand)
+	if err != nil {
+		return nil, err
+	}
+
+	bike := entity.Bike{
+		CategoryID:  bikeReq.CategoryID,
+		Name:        bikeReq.Name,
+		BrandID:     &brand.ID,
+		Brand:       brand.Name,
+		Description: bikeReq.Description,
+		Year:        bikeReq.Year,
+		Price:       bikeReq.Price,
+		ImageUrl:    bikeReq.ImageUrl,
+	}
+
+	if err := tx.Create(&bike).Error; err != nil {
+		return nil, err
+	}
+
+	variantReqs := bikeReq.Variants
+	if len(variantReqs) == 0 {
+		variantReqs = []request.CreateBikeVariantRequest{{
+			SKU:         fmt.Sprintf("BIKE-%d", bike.ID),
+			Stock:       bikeReq.Stock,
+			IsAvailable: &bikeReq.IsAvailable,
+		}}
+	}
+
+	if err := recordPriceChange(tx, actor, bike.ID, nil, nil, &bike.Price); err != nil {
+		return nil, err
+	}
+
+	if _, err := createBikeVariants(tx, actor, bike.ID, variantReqs); err != nil {
+		return nil, err
+	}
+
+	if err := saveBikeSpecs(tx, bike.ID, bike.CategoryID, bikeReq.Specs); err != nil {
+		return nil, err
+	}
+
+	if err := syncBikeStock(tx, bike.ID); err != nil {
+		return nil, err
+	}
+
+	return &bike, nil
+}
+
 func (service *BikeService) UpdateBike(c *gin.Context, id uint, bikeReq *request.UpdateBikeRequest) (*response.BikeResponse, error) {
 	db, logger := utils.GetDBAndLogger(c)
 
@@ -67,14 +140,20 @@ func (service *BikeService) UpdateBike(c *gin.Context, id uint, bikeReq *request
 			return err
 		}
 
+		movedCategory := bikeReq.CategoryID != 0 && bikeReq.CategoryID != bike.CategoryID
 		if bikeReq.CategoryID != 0 {
 			bike.CategoryID = bikeReq.CategoryID
 		}
 		if bikeReq.Name != "" {
 			bike.Name = bikeReq.Name
 		}
-		if bikeReq.Brand != "" {
-			bike.Brand = bikeReq.Brand
+		if bikeReq.BrandID != 0 || bikeReq.Brand != "" {
+			brand, err := findBikeBrand(tx, bikeReq.BrandID, bikeReq.Brand)
+			if err != nil {
+				return err
+			}
+			bike.BrandID = &brand.ID
+			bike.Brand = brand.Name
 		}
 		if bikeReq.Description != "" {
 			bike.Description = bikeReq.Description
@@ -83,25 +162,53 @@ func (service *BikeService) UpdateBike(c *gin.Context, id uint, bikeReq *request
 			bike.Year = bikeReq.Year
 		}
 		if bikeReq.Price != 0 {
+			if err := recordPriceChange(tx, newPriceChangeActor(c, entity.PriceChangeSourceAdmin), bike.ID, nil, &bike.Price, &bikeReq.Price); err != nil {
+				return err
+			}
 			bike.Price = bikeReq.Price
 		}
 		if bikeReq.ImageUrl != "" {
 			bike.ImageUrl = bikeReq.ImageUrl
 		}
-		if bikeReq.Stock != 0 {
-			bike.Stock = bikeReq.Stock
+
+		// stock and availability are derived from the variants
+		if err := tx.Omit("stock", "is_available").Save(&bike).Error; err != nil {
+			return err
+		}
+
+		if movedCategory {
+			if err := dropInapplicableBikeSpecs(tx, bike.ID, bike.CategoryID); err != nil {
+				return err
+			}
 		}
-		bike.IsAvailable = bikeReq.IsAvailable
 
-		if err := tx.Save(&bike).Error; err != nil {
+		if err := saveBikeSpecs(tx, bike.ID, bike.CategoryID, bikeReq.Specs); err != nil {
 			return err
 		}
 
 		if err := tx.Model(&entity.Bike{}).
-			Select("id, category_id, name, brand, description, year, price, image_url, stock, is_available, created_at, updated_at").
+			Select(bikeResponseColumns).
 			Take(&res, bike.ID).Error; err != nil {
 			return err
 		}
+
+		variants, err := findBikeVariants(tx, []uint{bike.ID})
+		if err != nil {
+			return err
+		}
+		res.Variants = variants[bike.ID]
+
+		res.Specs, err = findBikeSpecs(tx, bike.ID)
+		if err != nil {
+			return err
+		}
+
+		pricer, err := newBikePricer(tx)
+		if err != nil {
+			return err
+		}
+		pricer.applyToBike(&res)
+
 		return nil
 	})
 
@@ -114,13 +221,19 @@ func (service *BikeService) UpdateBike(c *gin.Context, id uint, bikeReq *request
 	return &res, nil
 }
 
+// DeleteBike archives a bike. It disappears from listings and carts, but orders and reviews still resolve it.
 func (service *BikeService) DeleteBike(c *gin.Context, id uint) error {
 	db, logger := utils.GetDBAndLogger(c)
 
 	err := db.Transaction(func(tx *gorm.DB) error {
-		if err := tx.Delete(&entity.Bike{}, id).Error; err != nil {
+		archived, err := archiveBikes(tx, time.Now(), "id = ?", id)
+		if err != nil {
 			return err
 		}
+
+		if archived == 0 {
+			return exceptions.NewCustomError(http.StatusNotFound, "Bike not found")
+		}
 		return nil
 	})
 
@@ -128,38 +241,78 @@ func (service *BikeService) DeleteBike(c *gin.Context, id uint) error {
 		return err
 	}
 
-	logger.Info("success deleting bike", zap.Uint("bikeID", id))
+	logger.Info("success archiving bike", zap.Uint("bikeID", id))
 
 	return nil
 }
 
-func (service *BikeService) GetAllBikes(c *gin.Context, bikeQueryReq *request.BikeQueryRequest) ([]response.BikeResponse, *web.Metadata, error) {
+func (service *BikeService) RestoreBike(c *gin.Context, id uint) (*response.BikeResponse, error) {
 	db, logger := utils.GetDBAndLogger(c)
 
-	var bikes []response.BikeResponse
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var bike entity.Bike
+		if err := tx.Unscoped().Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id, category_id").
+			Where("deleted_at IS NOT NULL").
+			Take(&bike, id).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return exceptions.NewCustomError(http.StatusNotFound, "Archived bike not found")
+			}
+			return err
+		}
 
-	query := db.Model(&entity.Bike{}).
-		Select("id, category_id, name, brand, description, year, price, image_url, stock, is_available, rating, reviewers, created_at, updated_at")
+		var category entity.Category
+		if err := tx.Select("id").Take(&category, bike.CategoryID).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return exceptions.NewCustomError(http.StatusConflict, "The bike's category is archived, restore it first")
+			}
+			return err
+		}
 
-	if bikeQueryReq.CategoryID != 0 {
-		query = query.Where("category_id = ?", bikeQueryReq.CategoryID)
-	}
-	if bikeQueryReq.Name != "" {
-		query = query.Where("to_tsvector('english', name) @@ plainto_tsquery('english', ?)", bikeQueryReq.Name)
-	}
-	if bikeQueryReq.MinPrice > 0 {
-		query = query.Where("price >= ?", bikeQueryReq.MinPrice)
+		return tx.Unscoped().Model(&entity.Bike{}).Where("id = ?", id).Update("deleted_at", nil).Error
+	})
+
+	if err != nil {
+		return nil, err
 	}
-	if bikeQueryReq.MaxPrice > 0 {
-		query = query.Where("price <= ?", bikeQueryReq.MaxPrice)
+
+	logger.Info("success restoring bike", zap.Uint("bikeID", id))
+
+	return service.GetBikeByID(c, id, true)
+}
+
+// archiveBikes archives the active bikes matching the query and takes them out of every cart.
+func archiveBikes(tx *gorm.DB, archivedAt time.Time, query string, args ...any) (int64, error) {
+	bikeIDs := tx.Model(&entity.Bike{}).Select("id").Where(query, args...)
+	if err := tx.Where("bike_id IN (?)", bikeIDs).Delete(&entity.CartItem{}).Error; err != nil {
+		return 0, err
 	}
-	if bikeQueryReq.MinYear > 0 {
-		query = query.Where("year >= ?", bikeQueryReq.MinYear)
+
+	result := tx.Model(&entity.Bike{}).Where(query, args...).Update("deleted_at", archivedAt)
+	return result.RowsAffected, result.Error
+}
+
+func (service *BikeService) GetAllBikes(c *gin.Context, bikeQueryReq *request.BikeQueryRequest) ([]response.BikeResponse, *web.Metadata, error) {
+	db, logger := utils.GetDBAndLogger(c)
+
+	var bikes []response.BikeResponse
+
+	bikeQueryReq.Brands = splitBrands(bikeQueryReq.Brands)
+
+	specs, err := normalizeSpecFilters(bikeQueryReq.Specs)
+	if err != nil {
+		return nil, nil, err
 	}
-	if bikeQueryReq.MaxYear > 0 {
-		query = query.Where("year <= ?", bikeQueryReq.MaxYear)
+	bikeQueryReq.Specs = specs
+
+	// archived bikes are left out unless an admin asks for them
+	if bikeQueryReq.IncludeArchived {
+		db = db.Unscoped()
 	}
 
+	query := filterBikes(db.Model(&entity.Bike{}), bikeQueryReq, "").
+		Select(bikeResponseColumns)
+
 	var totalData int64
 	if err := query.Count(&totalData).Error; err != nil {
 		logger.Error("failed to count bikes", zap.Error(err))
@@ -169,11 +322,39 @@ func (service *BikeService) GetAllBikes(c *gin.Context, bikeQueryReq *request.Bi
 
 	offset := bikeQueryReq.GetOffset()
 	limit := bikeQueryReq.GetLimit()
-	if err := query.Offset(offset).Limit(limit).Find(&bikes).Error; err != nil {
+	if err := sortBikes(query, bikeQueryReq.Sort).Offset(offset).Limit(limit).Find(&bikes).Error; err != nil {
 		logger.Error("failed to fetch bikes", zap.Error(err))
 		return nil, nil, err
 	}
 
+	bikeIDs := make([]uint, 0, len(bikes))
+	for _, bike := range bikes {
+		bikeIDs = append(bikeIDs, bike.ID)
+	}
+
+	variants, err := findBikeVariants(db, bikeIDs)
+	if err != nil {
+		logger.Error("failed to fetch bike variants", zap.Error(err))
+		return nil, nil, err
+	}
+
+	pricer, err := newBikePricer(db)
+	if err != nil {
+		logger.Error("failed to fetch discounts", zap.Error(err))
+		return nil, nil, err
+	}
+
+	for i := range bikes {
+		bikes[i].Variants = variants[bikes[i].ID]
+		pricer.applyToBike(&bikes[i])
+	}
+
+	facets, err := findBikeFacets(db, bikeQueryReq)
+	if err != nil {
+		logger.Error("failed to count bike facets", zap.Error(err))
+		return nil, nil, err
+	}
+
 	bikeQueryReq.TotalPages = int((totalData + int64(limit) - 1) / int64(limit))
 
 	metadata := &web.Metadata{
@@ -181,6 +362,7 @@ func (service *BikeService) GetAllBikes(c *gin.Context, bikeQueryReq *request.Bi
 		Limit:      &bikeQueryReq.Limit,
 		TotalPages: &bikeQueryReq.TotalPages,
 		TotalData:  &bikeQueryReq.TotalData,
+		Facets:     facets,
 	}
 
 	logger.Info("success fetching all bikes", zap.Int("total_data", int(totalData)), zap.Int("total_pages", bikeQueryReq.TotalPages))
@@ -188,13 +370,158 @@ func (service *BikeService) GetAllBikes(c *gin.Context, bikeQueryReq *request.Bi
 	return bikes, metadata, nil
 }
 
-func (service *BikeService) GetBikeByID(c *gin.Context, id uint) (*response.BikeResponse, error) {
+const (
+	bikeFacetBrand    = "brand"
+	bikeFacetCategory = "category"
+	bikeFacetPrice    = "price"
+)
+
+// bikePriceBuckets are the lower bounds of the price facet buckets in Rupiah.
+var bikePriceBuckets = []int{0, 5_000_000, 10_000_000, 20_000_000, 50_000_000}
+
+// bikeSoldUnitsExpr counts the units of a bike in transactions that were paid.
+var bikeSoldUnitsExpr = fmt.Sprintf(
+	"(SELECT COALESCE(SUM(orders.quantity), 0) FROM orders JOIN transactions ON transactions.id = orders.transaction_id WHERE orders.bike_id = bikes.id AND transactions.status NOT IN ('%s', '%s', '%s'))",
+	entity.TransactionStatusPending, entity.TransactionStatusCancelled, entity.TransactionStatusExpired,
+)
+
+// filterBikes applies the search filters, except the one of the facet being counted.
+func filterBikes(query *gorm.DB, bikeQueryReq *request.BikeQueryRequest, facet string) *gorm.DB {
+	if bikeQueryReq.CategoryID != 0 && facet != bikeFacetCategory {
+		query = query.Where("bikes.category_id IN ("+categorySubtreeSQL("id = ?", !bikeQueryReq.IncludeArchived)+")", bikeQueryReq.CategoryID)
+	}
+	if bikeQueryReq.Category != "" && facet != bikeFacetCategory {
+		query = query.Where("bikes.category_id IN ("+categorySubtreeSQL("slug = ?", !bikeQueryReq.IncludeArchived)+")", bikeQueryReq.Category)
+	}
+	if bikeQueryReq.Name != "" {
+		query = query.Where("to_tsvector('english', bikes.name) @@ plainto_tsquery('english', ?)", bikeQueryReq.Name)
+	}
+	if len(bikeQueryReq.Brands) > 0 && facet != bikeFacetBrand {
+		query = query.Where("bikes.brand_id IN (SELECT id FROM brands WHERE slug IN ?)", bikeQueryReq.Brands)
+	}
+	if len(bikeQueryReq.BrandIDs) > 0 && facet != bikeFacetBrand {
+		query = query.Where("bikes.brand_id IN ?", bikeQueryReq.BrandIDs)
+	}
+	if bikeQueryReq.MinPrice > 0 && facet != bikeFacetPrice {
+		query = query.Where(bikeSalePriceExpr+" >= ?", bikeQueryReq.MinPrice)
+	}
+	if bikeQueryReq.MaxPrice > 0 && facet != bikeFacetPrice {
+		query = query.Where(bikeSalePriceExpr+" <= ?", bikeQueryReq.MaxPrice)
+	}
+	if bikeQueryReq.MinYear > 0 {
+		query = query.Where("bikes.year >= ?", bikeQueryReq.MinYear)
+	}
+	if bikeQueryReq.MaxYear > 0 {
+		query = query.Where("bikes.year <= ?", bikeQueryReq.MaxYear)
+	}
+	if bikeQueryReq.MinRating > 0 {
+		query = query.Where("bikes.average_rating >= ?", bikeQueryReq.MinRating)
+	}
+	if bikeQueryReq.InStock {
+		query = query.Where("bikes.is_available AND bikes.stock > 0")
+	}
+
+	return filterBikeSpecs(query, bikeQueryReq.Specs)
+}
+
+func sortBikes(query *gorm.DB, sort string) *gorm.DB {
+	switch sort {
+	case "price_asc":
+		return query.Order(bikeSalePriceExpr + ", bikes.id")
+	case "price_desc":
+		return query.Order(bikeSalePriceExpr + " DESC, bikes.id")
+	case "newest":
+		return query.Order("bikes.created_at DESC, bikes.id DESC")
+	case "rating":
+		return query.Order("bikes.average_rating DESC, bikes.reviewers DESC, bikes.id")
+	case "popularity":
+		return query.Order(bikeSoldUnitsExpr + " DESC, bikes.id")
+	default:
+		return query.Order("bikes.id")
+	}
+}
+
+// splitBrands accepts brands both as repeated query values and as comma separated lists, and turns
+// them into slugs so brand names match regardless of case and spacing.
+func splitBrands(values []string) []string {
+	var brands []string
+	for _, value := range values {
+		for _, brand := range strings.Split(value, ",") {
+			if brand = utils.Slugify(brand); brand != "" {
+				brands = append(brands, brand)
+			}
+		}
+	}
+	return brands
+}
+
+func findBikeFacets(db *gorm.DB, bikeQueryReq *request.BikeQueryRequest) (*response.BikeFacetsResponse, error) {
+	facets := response.BikeFacetsResponse{
+		Brands:     []response.BikeBrandFacetResponse{},
+		Categories: []response.BikeCategoryFacetResponse{},
+		Prices:     make([]response.BikePriceFacetResponse, len(bikePriceBuckets)),
+	}
+
+	if err := filterBikes(db.Model(&entity.Bike{}), bikeQueryReq, bikeFacetBrand).
+		Joins("JOIN brands ON brands.id = bikes.brand_id").
+		Select("brands.id AS brand_id, brands.name AS brand, brands.slug AS slug, COUNT(*) AS count").
+		Group("brands.id, brands.name, brands.slug").
+		Order("count DESC, brand").
+		Scan(&facets.Brands).Error; err != nil {
+		return nil, err
+	}
+
+	if err := filterBikes(db.Model(&entity.Bike{}), bikeQueryReq, bikeFacetCategory).
+		Joins("JOIN categories ON categories.id = bikes.category_id").
+		Select("bikes.category_id AS category_id, categories.name AS name, categories.slug AS slug, COUNT(*) AS count").
+		Group("bikes.category_id, categories.name, categories.slug").
+		Order("count DESC, name").
+		Scan(&facets.Categories).Error; err != nil {
+		return nil, err
+	}
+
+	lowers := make([]string, 0, len(bikePriceBuckets))
+	for _, lower := range bikePriceBuckets {
+		lowers = append(lowers, strconv.Itoa(lower))
+	}
+	// width_bucket counts from 1 for prices from the first lower bound on, anything below it falls in the first bucket
+	bucketExpr := fmt.Sprintf("GREATEST(width_bucket(%s::int, ARRAY[%s]) - 1, 0)", bikeSalePriceExpr, strings.Join(lowers, ", "))
+
+	var buckets []struct {
+		Bucket int
+		Count  int64
+	}
+	if err := filterBikes(db.Model(&entity.Bike{}), bikeQueryReq, bikeFacetPrice).
+		Select(bucketExpr + " AS bucket, COUNT(*) AS count").
+		Group("bucket").
+		Scan(&buckets).Error; err != nil {
+		return nil, err
+	}
+
+	for i, lower := range bikePriceBuckets {
+		facets.Prices[i].Min = lower
+		if i+1 < len(bikePriceBuckets) {
+			upper := bikePriceBuckets[i+1]
+			facets.Prices[i].Max = &upper
+		}
+	}
+	for _, bucket := range buckets {
+		facets.Prices[bucket.Bucket].Count = bucket.Count
+	}
+
+	return &facets, nil
+}
+
+// GetBikeByID returns a bike with its variants, images and specs. withPriceHistory adds the price
+// history and the discounts that target the bike, for admins.
+func (service *BikeService) GetBikeByID(c *gin.Context, id uint, withPriceHistory bool) (*response.BikeResponse, error) {
 	db, logger := utils.GetDBAndLogger(c)
 
 	var res response.BikeResponse
 
-	if err := db.Model(&entity.Bike{}).
-		Select("id, category_id, name, brand, description, year, price, image_url, stock, is_available, rating, reviewers, created_at, updated_at").
+	// archived bikes still resolve, e.g. when followed from an order or a review
+	if err := db.Unscoped().Model(&entity.Bike{}).
+		Select(bikeResponseColumns).
 		Take(&res, id).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			logger.Warn("bike not found", zap.Uint("bikeID", id))
@@ -205,7 +532,292 @@ func (service *BikeService) GetBikeByID(c *gin.Context, id uint) (*response.Bike
 		return nil, err
 	}
 
+	variants, err := findBikeVariants(db, []uint{id})
+	if err != nil {
+		logger.Error("failed to fetch bike variants", zap.Error(err))
+		return nil, err
+	}
+	res.Variants = variants[id]
+
+	res.Images, err = findBikeImages(db, id)
+	if err != nil {
+		logger.Error("failed to fetch bike images", zap.Error(err))
+		return nil, err
+	}
+
+	res.Specs, err = findBikeSpecs(db, id)
+	if err != nil {
+		logger.Error("failed to fetch bike specs", zap.Error(err))
+		return nil, err
+	}
+
+	pricer, err := newBikePricer(db)
+	if err != nil {
+		logger.Error("failed to fetch discounts", zap.Error(err))
+		return nil, err
+	}
+	pricer.applyToBike(&res)
+
+	if withPriceHistory {
+		res.PriceHistory, err = findPriceHistory(db, id)
+		if err != nil {
+			logger.Error("failed to fetch bike price history", zap.Error(err))
+			return nil, err
+		}
+
+		res.Discounts, err = findBikeDiscounts(db, id, res.CategoryID, res.BrandID)
+		if err != nil {
+			logger.Error("failed to fetch bike discounts", zap.Error(err))
+			return nil, err
+		}
+	}
+
 	logger.Info("success fetching bike", zap.Uint("bikeID", id))
 
 	return &res, nil
 }
+
+// priceChangeActor is who changes prices and how, for the price history.
+type priceChangeActor struct {
+	ID     *uint
+	Source string
+}
+
+func newPriceChangeActor(c *gin.Context, source string) priceChangeActor {
+	actor := priceChangeActor{Source: source}
+	if claims, err := utils.ExtractTokenClaims(c); err == nil {
+		actor.ID = &claims.UserID
+	}
+	return actor
+}
+
+// recordPriceChange adds an entry to the price history of a bike, or of one of its variants when
+// variantID is set, unless the price stays the same.
+func recordPriceChange(tx *gorm.DB, actor priceChangeActor, bikeID uint, variantID *uint, oldPrice, newPrice *int) error {
+	if (oldPrice == nil && newPrice == nil) || (oldPrice != nil && newPrice != nil && *oldPrice == *newPrice) {
+		return nil
+	}
+
+	return tx.Create(&entity.PriceHistory{
+		BikeID:        bikeID,
+		BikeVariantID: variantID,
+		OldPrice:      oldPrice,
+		NewPrice:      newPrice,
+		Source:        actor.Source,
+		ActorID:       actor.ID,
+	}).Error
+}
+
+func findPriceHistory(db *gorm.DB, bikeID uint) ([]response.PriceHistoryResponse, error) {
+	var history []entity.PriceHistory
+	if err := db.Where("bike_id = ?", bikeID).Order("created_at DESC, id DESC").Find(&history).Error; err != nil {
+		return nil, err
+	}
+
+	res := make([]response.PriceHistoryResponse, 0, len(history))
+	for _, change := range history {
+		res = append(res, response.PriceHistoryResponse{
+			ID:            change.ID,
+			BikeVariantID: change.BikeVariantID,
+			OldPrice:      change.OldPrice,
+			NewPrice:      change.NewPrice,
+			Source:        change.Source,
+			ActorID:       change.ActorID,
+			CreatedAt:     change.CreatedAt,
+		})
+	}
+	return res, nil
+}
+
+// maxComparedBikes is how many bikes can be compared side by side.
+const maxComparedBikes = 4
+
+// CompareBikes lines up 2 to maxComparedBikes active bikes attribute by attribute, loading them all at
+// once. Unknown or archived ids are reported together in a single 400.
+func (service *BikeService) CompareBikes(c *gin.Context, compareReq *request.BikeCompareRequest) (*response.BikeComparisonResponse, error) {
+	db, logger := utils.GetDBAndLogger(c)
+
+	var ids []uint
+	seen := map[uint]bool{}
+	for _, value := range strings.Split(compareReq.IDs, ",") {
+		id, err := strconv.ParseUint(strings.TrimSpace(value), 10, 32)
+		if err != nil {
+			return nil, exceptions.NewCustomError(http.StatusBadRequest, "ids must be a comma separated list of bike ids")
+		}
+		if !seen[uint(id)] {
+			seen[uint(id)] = true
+			ids = append(ids, uint(id))
+		}
+	}
+
+	if len(ids) < 2 || len(ids) > maxComparedBikes {
+		return nil, exceptions.NewCustomError(http.StatusBadRequest, fmt.Sprintf("Compare between 2 and %d different bikes", maxComparedBikes))
+	}
+
+	var bikes []response.BikeResponse
+	if err := db.Model(&entity.Bike{}).Select(bikeResponseColumns).Where("id IN ?", ids).Find(&bikes).Error; err != nil {
+		logger.Error("failed to fetch bikes", zap.Error(err))
+		return nil, err
+	}
+
+	bikesByID := make(map[uint]response.BikeResponse, len(bikes))
+	categoryIDs := make([]uint, 0, len(bikes))
+	for _, bike := range bikes {
+		bikesByID[bike.ID] = bike
+		categoryIDs = append(categoryIDs, bike.CategoryID)
+	}
+
+	var unknown []uint
+	for _, id := range ids {
+		if _, ok := bikesByID[id]; !ok {
+			unknown = append(unknown, id)
+		}
+	}
+	if len(unknown) > 0 {
+		return nil, exceptions.NewDetailedError(http.StatusBadRequest, "Some bikes don't exist", unknown)
+	}
+
+	var categories []entity.Category
+	if err := db.Unscoped().Select("id, name").Where("id IN ?", categoryIDs).Find(&categories).Error; err != nil {
+		logger.Error("failed to fetch categories", zap.Error(err))
+		return nil, err
+	}
+	categoryNames := make(map[uint]string, len(categories))
+	for _, category := range categories {
+		categoryNames[category.ID] = category.Name
+	}
+
+	specs, err := findBikesSpecs(db, ids)
+	if err != nil {
+		logger.Error("failed to fetch bike specs", zap.Error(err))
+		return nil, err
+	}
+
+	pricer, err := newBikePricer(db)
+	if err != nil {
+		logger.Error("failed to fetch discounts", zap.Error(err))
+		return nil, err
+	}
+
+	res := &response.BikeComparisonResponse{}
+	ordered := make([]response.BikeResponse, 0, len(ids))
+	for _, id := range ids {
+		bike := bikesByID[id]
+		pricer.applyToBike(&bike)
+		ordered = append(ordered, bike)
+
+		res.Bikes = append(res.Bikes, response.BikeComparisonBikeResponse{ID: bike.ID, Name: bike.Name, ImageUrl: bike.ImageUrl})
+	}
+
+	row := func(key, name, unit string, value func(bike response.BikeResponse) any) {
+		values := make([]any, 0, len(ordered))
+		for _, bike := range ordered {
+			values = append(values, value(bike))
+		}
+		res.Attributes = append(res.Attributes, toBikeComparisonAttribute(key, name, unit, values))
+	}
+
+	row("price", "Price", "", func(bike response.BikeResponse) any { return bike.Price })
+	row("sale_price", "Sale price", "", func(bike response.BikeResponse) any { return bike.SalePrice })
+	row("year", "Year", "", func(bike response.BikeResponse) any { return bike.Year })
+	row("brand", "Brand", "", func(bike response.BikeResponse) any { return bike.Brand })
+	row("category", "Category", "", func(bike response.BikeResponse) any { return categoryNames[bike.CategoryID] })
+	row("average_rating", "Average rating", "", func(bike response.BikeResponse) any { return bike.AverageRating })
+	row("stock_status", "Stock status", "", func(bike response.BikeResponse) any {
+		if bike.IsAvailable && bike.Stock > 0 {
+			return "in_stock"
+		}
+		return "out_of_stock"
+	})
+
+	// specs of every bike, a bike without one, e.g. from another category, gets null
+	var specKeys []response.BikeSpecResponse
+	seenSpecs := map[string]bool{}
+	specValues := make(map[uint]map[string]any, len(ids))
+	for _, id := range ids {
+		specValues[id] = map[string]any{}
+		for _, spec := range specs[id] {
+			if !seenSpecs[spec.Key] {
+				seenSpecs[spec.Key] = true
+				specKeys = append(specKeys, spec)
+			}
+			specValues[id][spec.Key] = spec.Value
+		}
+	}
+	sort.SliceStable(specKeys, func(i, j int) bool { return specKeys[i].Name < specKeys[j].Name })
+
+	for _, spec := range specKeys {
+		row("specs."+spec.Key, spec.Name, spec.Unit, func(bike response.BikeResponse) any { return specValues[bike.ID][spec.Key] })
+	}
+
+	logger.Info("success comparing bikes", zap.Any("bikeIDs", ids))
+
+	return res, nil
+}
+
+func toBikeComparisonAttribute(key, name, unit string, values []any) response.BikeComparisonAttributeResponse {
+	attribute := response.BikeComparisonAttributeResponse{Key: key, Name: name, Unit: unit, Values: values}
+	for _, value := range values[1:] {
+		if !reflect.DeepEqual(value, values[0]) {
+			attribute.Differs = true
+			break
+		}
+	}
+	return attribute
+}
+
+const (
+	defaultBikeSuggestions = 8
+	// bikeSuggestionThreshold is the word similarity a name needs to be suggested, low enough for a
+	// partial or misspelled word, e.g. "marl" or "marlni" for "Marlin".
+	bikeSuggestionThreshold = 0.3
+)
+
+// bikeSuggestionsSQL ranks the bikes, brands and categories by how well a word of their name matches
+// what was typed. The <% operator uses the trigram indexes, with the threshold set for the transaction.
+const bikeSuggestionsSQL = `SELECT type, id, text, slug, score FROM (
+		SELECT 'bike' AS type, id, name AS text, '' AS slug, word_similarity(@q, name) AS score FROM bikes WHERE deleted_at IS NULL AND @q <% name
+		UNION ALL
+		SELECT 'brand', id, name, slug, word_similarity(@q, name) FROM brands WHERE @q <% name
+		UNION ALL
+		SELECT 'category', id, name, slug, word_similarity(@q, name) FROM categories WHERE deleted_at IS NULL AND @q <% name
+	) AS suggestions
+	ORDER BY score DESC, length(text), text
+	LIMIT @limit`
+
+// SuggestBikes autocompletes a search, tolerating partial words and typos, with the matched parts of
+// every suggestion highlighted.
+func (service *BikeService) SuggestBikes(c *gin.Context, suggestReq *request.BikeSuggestRequest) ([]response.BikeSuggestionResponse, error) {
+	db, logger := utils.GetDBAndLogger(c)
+
+	limit := suggestReq.Limit
+	if limit == 0 {
+		limit = defaultBikeSuggestions
+	}
+
+	suggestions := []response.BikeSuggestionResponse{}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(fmt.Sprintf("SET LOCAL pg_trgm.word_similarity_threshold = %g", bikeSuggestionThreshold)).Error; err != nil {
+			return err
+		}
+
+		return tx.Raw(bikeSuggestionsSQL, map[string]any{"q": suggestReq.Q, "limit": limit}).Scan(&suggestions).Error
+	})
+
+	if err != nil {
+		logger.Error("failed to fetch bike suggestions", zap.Error(err))
+		return nil, err
+	}
+
+	for i := range suggestions {
+		suggestions[i].Highlights = []response.HighlightResponse{}
+		for _, match := range utils.Highlight(suggestions[i].Text, suggestReq.Q) {
+			suggestions[i].Highlights = append(suggestions[i].Highlights, response.HighlightResponse{Start: match.Start, End: match.End})
+		}
+	}
+
+	logger.Info("success fetching bike suggestions", zap.String("q", suggestReq.Q), zap.Int("total", len(suggestions)))
+
+	return suggestions, nil
+}
@@ -0,0 +1,339 @@
+package services
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gowesmart/api-gowesmart/exceptions"
+	"github.com/gowesmart/api-gowesmart/model/entity"
+	"github.com/gowesmart/api-gowesmart/model/web/request"
+	"github.com/gowesmart/api-gowesmart/model/web/response"
+	"github.com/gowesmart/api-gowesmart/utils"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// variantBikeColumns are the bike columns needed to price its variants.
+const variantBikeColumns = "id, price, category_id, brand_id"
+
+type BikeVariantService struct{}
+
+func NewBikeVariantService() *BikeVariantService {
+	return &BikeVariantService{}
+}
+
+func (service *BikeVariantService) CreateVariant(c *gin.Context, bikeID uint, variantReq *request.CreateBikeVariantRequest) (*response.BikeVariantResponse, error) {
+	db, logger := utils.GetDBAndLogger(c)
+
+	var variant entity.BikeVariant
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var bike entity.Bike
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select(variantBikeColumns).Take(&bike, bikeID).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return exceptions.NewCustomError(http.StatusNotFound, "Bike not found")
+			}
+			return err
+		}
+
+		variants, err := createBikeVariants(tx, newPriceChangeActor(c, entity.PriceChangeSourceAdmin), bike.ID, []request.CreateBikeVariantRequest{*variantReq})
+		if err != nil {
+			return err
+		}
+
+		variant = variants[0]
+		variant.Bike = bike
+
+		pricer, err := newBikePricer(tx)
+		if err != nil {
+			return err
+		}
+		pricer.applyToVariant(&variant)
+
+		return syncBikeStock(tx, bike.ID)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("success creating bike variant", zap.Uint("bikeID", bikeID), zap.Uint("variantID", variant.ID))
+
+	res := toBikeVariantResponse(variant)
+	return &res, nil
+}
+
+func (service *BikeVariantService) UpdateVariant(c *gin.Context, bikeID, variantID uint, variantReq *request.UpdateBikeVariantRequest) (*response.BikeVariantResponse, error) {
+	db, logger := utils.GetDBAndLogger(c)
+
+	var variant entity.BikeVariant
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Preload("Bike", func(db *gorm.DB) *gorm.DB { return db.Unscoped().Select(variantBikeColumns) }).
+			Where("bike_id = ?", bikeID).
+			Take(&variant, variantID).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return exceptions.NewCustomError(http.StatusNotFound, "Bike variant not found")
+			}
+			return err
+		}
+
+		if variantReq.SKU != "" && variantReq.SKU != variant.SKU {
+			if err := ensureSKUsAvailable(tx, []string{variantReq.SKU}); err != nil {
+				return err
+			}
+			variant.SKU = variantReq.SKU
+		}
+		if variantReq.FrameSize != "" {
+			variant.FrameSize = variantReq.FrameSize
+		}
+		if variantReq.Color != "" {
+			variant.Color = variantReq.Color
+		}
+		if variantReq.Price != nil {
+			if err := recordPriceChange(tx, newPriceChangeActor(c, entity.PriceChangeSourceAdmin), bikeID, &variant.ID, variant.Price, variantReq.Price); err != nil {
+				return err
+			}
+			variant.Price = variantReq.Price
+		}
+		if variantReq.ClearPrice {
+			if err := recordPriceChange(tx, newPriceChangeActor(c, entity.PriceChangeSourceAdmin), bikeID, &variant.ID, variant.Price, nil); err != nil {
+				return err
+			}
+			variant.Price = nil
+		}
+		if variantReq.IsAvailable != nil {
+			variant.IsAvailable = *variantReq.IsAvailable
+		}
+		if variantReq.LowStockThreshold != nil {
+			variant.LowStockThreshold = *variantReq.LowStockThreshold
+		}
+		if variantReq.AutoAvailability != nil {
+			variant.AutoAvailability = *variantReq.AutoAvailability
+			if variant.AutoAvailability {
+				variant.IsAvailable = variant.Stock > 0
+			}
+		}
+
+		if err := tx.Omit("Bike").Save(&variant).Error; err != nil {
+			return err
+		}
+
+		pricer, err := newBikePricer(tx)
+		if err != nil {
+			return err
+		}
+		pricer.applyToVariant(&variant)
+
+		return syncBikeStock(tx, bikeID)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("success updating bike variant", zap.Uint("bikeID", bikeID), zap.Uint("variantID", variantID))
+
+	res := toBikeVariantResponse(variant)
+	return &res, nil
+}
+
+// DeleteVariant deletes a variant of a bike. A bike keeps at least one variant, and a variant can't be
+// deleted while a pending transaction still reserves its stock.
+func (service *BikeVariantService) DeleteVariant(c *gin.Context, bikeID, variantID uint) error {
+	db, logger := utils.GetDBAndLogger(c)
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		// locking the variant waits for checkouts reserving its stock to finish
+		var variant entity.BikeVariant
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id").
+			Where("bike_id = ?", bikeID).
+			Take(&variant, variantID).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return exceptions.NewCustomError(http.StatusNotFound, "Bike variant not found")
+			}
+			return err
+		}
+
+		var count int64
+		if err := tx.Model(&entity.BikeVariant{}).Where("bike_id = ?", bikeID).Count(&count).Error; err != nil {
+			return err
+		}
+
+		if count <= 1 {
+			return exceptions.NewCustomError(http.StatusBadRequest, "Bike must keep at least one variant")
+		}
+
+		var reserving int64
+		if err := tx.Model(&entity.Transaction{}).
+			Where("status = ? AND stock_reservation = ?", entity.TransactionStatusPending, entity.StockReservationReserved).
+			Where("EXISTS (SELECT 1 FROM orders WHERE orders.transaction_id = transactions.id AND orders.bike_variant_id = ?)", variantID).
+			Count(&reserving).Error; err != nil {
+			return err
+		}
+
+		if reserving > 0 {
+			return exceptions.NewCustomError(http.StatusConflict, fmt.Sprintf("Bike variant is reserved by %d pending transactions", reserving))
+		}
+
+		if err := tx.Delete(&variant).Error; err != nil {
+			return err
+		}
+
+		return syncBikeStock(tx, bikeID)
+	})
+
+	if err != nil {
+		return err
+	}
+
+	logger.Info("success deleting bike variant", zap.Uint("bikeID", bikeID), zap.Uint("variantID", variantID))
+
+	return nil
+}
+
+// createBikeVariants stores the variants of a bike, failing with a 409 if any of their SKUs is taken.
+func createBikeVariants(tx *gorm.DB, actor priceChangeActor, bikeID uint, variantReqs []request.CreateBikeVariantRequest) ([]entity.BikeVariant, error) {
+	skus := make([]string, 0, len(variantReqs))
+	for _, variantReq := range variantReqs {
+		skus = append(skus, variantReq.SKU)
+	}
+
+	if err := ensureSKUsAvailable(tx, skus); err != nil {
+		return nil, err
+	}
+
+	variants := make([]entity.BikeVariant, 0, len(variantReqs))
+	for _, variantReq := range variantReqs {
+		variant := entity.BikeVariant{
+			BikeID:            bikeID,
+			SKU:               variantReq.SKU,
+			FrameSize:         variantReq.FrameSize,
+			Color:             variantReq.Color,
+			Price:             variantReq.Price,
+			Stock:             variantReq.Stock,
+			IsAvailable:       true,
+			LowStockThreshold: variantReq.LowStockThreshold,
+			AutoAvailability:  variantReq.AutoAvailability,
+		}
+		if variantReq.IsAvailable != nil {
+			variant.IsAvailable = *variantReq.IsAvailable
+		}
+		if variant.AutoAvailability {
+			variant.IsAvailable = variant.Stock > 0
+		}
+
+		variants = append(variants, variant)
+	}
+
+	if err := tx.Omit("Bike").Create(&variants).Error; err != nil {
+		return nil, err
+	}
+
+	for _, variant := range variants {
+		if err := recordPriceChange(tx, actor, bikeID, &variant.ID, nil, variant.Price); err != nil {
+			return nil, err
+		}
+
+		if err := recordStockMovement(tx, variant.BikeID, variant.ID, variant.Stock, stockChange{Type: entity.StockMovementRestock, Note: "initial stock", ActorID: actor.ID}); err != nil {
+			return nil, err
+		}
+	}
+
+	return variants, nil
+}
+
+func ensureSKUsAvailable(tx *gorm.DB, skus []string) error {
+	seen := make(map[string]bool, len(skus))
+	for _, sku := range skus {
+		if seen[sku] {
+			return exceptions.NewCustomError(http.StatusConflict, fmt.Sprintf("SKU %s is used more than once", sku))
+		}
+		seen[sku] = true
+	}
+
+	var taken []string
+	if err := tx.Model(&entity.BikeVariant{}).Where("sku IN ?", skus).Pluck("sku", &taken).Error; err != nil {
+		return err
+	}
+
+	if len(taken) > 0 {
+		return exceptions.NewCustomError(http.StatusConflict, fmt.Sprintf("SKU %s already exists", taken[0]))
+	}
+
+	return nil
+}
+
+// syncBikeStock derives the bikes' stock and availability from their variants: the stock is what
+// the available variants hold, and a bike is available while any of them can still be bought.
+// Archived bikes are kept in sync too, so they come back with the right stock when restored.
+func syncBikeStock(tx *gorm.DB, bikeIDs ...uint) error {
+	return tx.Unscoped().Model(&entity.Bike{}).Where("id IN ?", bikeIDs).Updates(bikeStockAssignments()).Error
+}
+
+func bikeStockAssignments() map[string]any {
+	return map[string]any{
+		"stock":        gorm.Expr("(SELECT COALESCE(SUM(stock), 0) FROM bike_variants WHERE bike_variants.bike_id = bikes.id AND bike_variants.is_available)"),
+		"is_available": gorm.Expr("EXISTS (SELECT 1 FROM bike_variants WHERE bike_variants.bike_id = bikes.id AND bike_variants.is_available AND bike_variants.stock > 0)"),
+	}
+}
+
+// syncVariantBikeStock syncs the stock of the bikes the given variants belong to.
+func syncVariantBikeStock(tx *gorm.DB, variantIDs []uint) error {
+	return tx.Unscoped().Model(&entity.Bike{}).
+		Where("id IN (?)", tx.Model(&entity.BikeVariant{}).Select("bike_id").Where("id IN ?", variantIDs)).
+		Updates(bikeStockAssignments()).Error
+}
+
+// findBikeVariants loads the variants of the given bikes keyed by bike id.
+func findBikeVariants(db *gorm.DB, bikeIDs []uint) (map[uint][]response.BikeVariantResponse, error) {
+	var variants []entity.BikeVariant
+	if err := db.Preload("Bike", func(db *gorm.DB) *gorm.DB { return db.Unscoped().Select(variantBikeColumns) }).
+		Where("bike_id IN ?", bikeIDs).
+		Order("id").
+		Find(&variants).Error; err != nil {
+		return nil, err
+	}
+
+	pricer, err := newBikePricer(db)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[uint][]response.BikeVariantResponse, len(bikeIDs))
+	for _, variant := range variants {
+		pricer.applyToVariant(&variant)
+		result[variant.BikeID] = append(result[variant.BikeID], toBikeVariantResponse(variant))
+	}
+
+	return result, nil
+}
+
+func toBikeVariantResponse(variant entity.BikeVariant) response.BikeVariantResponse {
+	return response.BikeVariantResponse{
+		ID:                variant.ID,
+		BikeID:            variant.BikeID,
+		SKU:               variant.SKU,
+		FrameSize:         variant.FrameSize,
+		Color:             variant.Color,
+		Price:             variant.ListPrice(),
+		SalePrice:         variant.EffectivePrice(),
+		DiscountID:        discountIDOf(variant.Discount),
+		PriceOverride:     variant.Price,
+		Stock:             variant.Stock,
+		IsAvailable:       variant.IsAvailable,
+		LowStockThreshold: variant.LowStockThreshold,
+		AutoAvailability:  variant.AutoAvailability,
+	}
+}
+
+func discountIDOf(discount *entity.Discount) *uint {
+	if discount == nil {
+		return nil
+	}
+	return &discount.ID
+}
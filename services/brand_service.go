@@ -0,0 +1,269 @@
+package services
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gowesmart/api-gowesmart/exceptions"
+	"github.com/gowesmart/api-gowesmart/model/entity"
+	"github.com/gowesmart/api-gowesmart/model/web"
+	"github.com/gowesmart/api-gowesmart/model/web/request"
+	"github.com/gowesmart/api-gowesmart/model/web/response"
+	"github.com/gowesmart/api-gowesmart/utils"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// brandResponseColumns are the brand columns scanned into response.BrandResponse, bike_count only counts active bikes.
+const brandResponseColumns = "brands.id, brands.name, brands.slug, brands.logo_url, brands.country, brands.description, brands.created_at, brands.updated_at, " +
+	"(SELECT COUNT(*) FROM bikes WHERE bikes.brand_id = brands.id AND bikes.deleted_at IS NULL) AS bike_count"
+
+type BrandService struct{}
+
+func NewBrandService() *BrandService {
+	return &BrandService{}
+}
+
+func (service *BrandService) CreateBrand(c *gin.Context, brandReq *request.CreateBrandRequest) (*response.BrandResponse, error) {
+	db, logger := utils.GetDBAndLogger(c)
+
+	var res response.BrandResponse
+
+	brand := entity.Brand{
+		Name:        strings.TrimSpace(brandReq.Name),
+		Slug:        brandReq.Slug,
+		LogoUrl:     brandReq.LogoUrl,
+		Country:     brandReq.Country,
+		Description: brandReq.Description,
+	}
+	if brand.Slug == "" {
+		brand.Slug = utils.Slugify(brand.Name)
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := ensureBrandAvailable(tx, 0, brand.Name, brand.Slug); err != nil {
+			return err
+		}
+
+		if err := tx.Create(&brand).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&entity.Brand{}).Select(brandResponseColumns).Take(&res, brand.ID).Error
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("success creating brand", zap.Uint("brandID", brand.ID))
+
+	return &res, nil
+}
+
+func (service *BrandService) UpdateBrand(c *gin.Context, id uint, brandReq *request.UpdateBrandRequest) (*response.BrandResponse, error) {
+	db, logger := utils.GetDBAndLogger(c)
+
+	var res response.BrandResponse
+	var brand entity.Brand
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&brand, id).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return exceptions.NewCustomError(http.StatusNotFound, "Brand not found")
+			}
+			return err
+		}
+
+		renamed := false
+		if name := strings.TrimSpace(brandReq.Name); name != "" && name != brand.Name {
+			brand.Name = name
+			renamed = true
+		}
+		if brandReq.Slug != "" {
+			brand.Slug = brandReq.Slug
+		}
+		if brandReq.LogoUrl != nil {
+			brand.LogoUrl = *brandReq.LogoUrl
+		}
+		if brandReq.Country != nil {
+			brand.Country = *brandReq.Country
+		}
+		if brandReq.Description != nil {
+			brand.Description = *brandReq.Description
+		}
+
+		if err := ensureBrandAvailable(tx, brand.ID, brand.Name, brand.Slug); err != nil {
+			return err
+		}
+
+		if err := tx.Save(&brand).Error; err != nil {
+			return err
+		}
+
+		// archived bikes are renamed as well, they come back under the current name
+		if renamed {
+			if err := tx.Unscoped().Model(&entity.Bike{}).
+				Where("brand_id = ?", brand.ID).
+				UpdateColumn("brand", brand.Name).Error; err != nil {
+				return err
+			}
+		}
+
+		return tx.Model(&entity.Brand{}).Select(brandResponseColumns).Take(&res, brand.ID).Error
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("success updating brand", zap.Uint("brandID", brand.ID))
+
+	return &res, nil
+}
+
+// DeleteBrand deletes a brand that no bike, archived ones included, refers to.
+func (service *BrandService) DeleteBrand(c *gin.Context, id uint) error {
+	db, logger := utils.GetDBAndLogger(c)
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var bikes int64
+		if err := tx.Unscoped().Model(&entity.Bike{}).Where("brand_id = ?", id).Count(&bikes).Error; err != nil {
+			return err
+		}
+
+		if bikes > 0 {
+			return exceptions.NewCustomError(http.StatusConflict, "Brand still has bikes, move them to another brand first")
+		}
+
+		result := tx.Delete(&entity.Brand{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return exceptions.NewCustomError(http.StatusNotFound, "Brand not found")
+		}
+		return nil
+	})
+
+	if err != nil {
+		return err
+	}
+
+	logger.Info("success deleting brand", zap.Uint("brandID", id))
+
+	return nil
+}
+
+func (service *BrandService) GetAllBrands(c *gin.Context, brandQueryReq *request.BrandQueryRequest) ([]response.BrandResponse, *web.Metadata, error) {
+	db, logger := utils.GetDBAndLogger(c)
+
+	brands := []response.BrandResponse{}
+
+	query := db.Model(&entity.Brand{})
+	if brandQueryReq.Name != "" {
+		query = query.Where("brands.name ILIKE ?", "%"+brandQueryReq.Name+"%")
+	}
+
+	paginationReq := &brandQueryReq.PaginationRequest
+
+	var totalData int64
+	if err := query.Count(&totalData).Error; err != nil {
+		logger.Error("failed to count brands", zap.Error(err))
+		return nil, nil, err
+	}
+	paginationReq.TotalData = totalData
+
+	offset := paginationReq.GetOffset()
+	limit := paginationReq.GetLimit()
+	if err := query.Select(brandResponseColumns).
+		Order("brands.name").
+		Offset(offset).
+		Limit(limit).
+		Find(&brands).Error; err != nil {
+		logger.Error("failed to fetch brands", zap.Error(err))
+		return nil, nil, err
+	}
+
+	paginationReq.TotalPages = int((totalData + int64(limit) - 1) / int64(limit))
+
+	metadata := &web.Metadata{
+		Page:       &paginationReq.Page,
+		Limit:      &paginationReq.Limit,
+		TotalPages: &paginationReq.TotalPages,
+		TotalData:  &paginationReq.TotalData,
+	}
+
+	logger.Info("success fetching all brands", zap.Int("total_data", int(totalData)), zap.Int("total_pages", paginationReq.TotalPages))
+
+	return brands, metadata, nil
+}
+
+func (service *BrandService) GetBrandByID(c *gin.Context, id uint) (*response.BrandResponse, error) {
+	db, logger := utils.GetDBAndLogger(c)
+
+	var res response.BrandResponse
+
+	if err := db.Model(&entity.Brand{}).Select(brandResponseColumns).Take(&res, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			logger.Warn("brand not found", zap.Uint("brandID", id))
+			return nil, exceptions.NewCustomError(http.StatusNotFound, "Brand not found")
+		}
+
+		logger.Error("failed to fetch brand", zap.Error(err))
+		return nil, err
+	}
+
+	logger.Info("success fetching brand", zap.Uint("brandID", id))
+
+	return &res, nil
+}
+
+// ensureBrandAvailable fails with a 409 when another brand already uses the name or slug.
+func ensureBrandAvailable(tx *gorm.DB, id uint, name, slug string) error {
+	if slug == "" {
+		return exceptions.NewCustomError(http.StatusBadRequest, "Slug can't be derived from the name, set it explicitly")
+	}
+
+	var existing entity.Brand
+	err := tx.Select("id, name, slug").
+		Where("(name = ? OR slug = ?) AND id <> ?", name, slug, id).
+		Take(&existing).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if existing.Slug == slug {
+		return exceptions.NewCustomError(http.StatusConflict, fmt.Sprintf("Slug %s already exists", slug))
+	}
+	return exceptions.NewCustomError(http.StatusConflict, fmt.Sprintf("Brand %s already exists", name))
+}
+
+// findBikeBrand resolves the brand of a bike by id, or else by name. Names are compared by slug, so
+// "TREK" and "trek " both find Trek.
+func findBikeBrand(tx *gorm.DB, brandID uint, name string) (*entity.Brand, error) {
+	var brand entity.Brand
+
+	query := tx.Select("id, name")
+	if brandID != 0 {
+		query = query.Where("id = ?", brandID)
+	} else {
+		query = query.Where("slug = ?", utils.Slugify(name))
+	}
+
+	if err := query.Take(&brand).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, exceptions.NewCustomError(http.StatusNotFound, "Brand not found")
+		}
+		return nil, err
+	}
+
+	return &brand, nil
+}
@@ -23,14 +23,21 @@ func (s CartItemService) GetByUserID(c *gin.Context, userID uint) (*response.Get
 	db, _ := utils.GetDBAndLogger(c)
 
 	var cart entity.Cart
-	if err := db.Preload("CartItem.Bike").Preload("User").Find(&cart, "user_id = ?", userID).Error; err != nil {
+	if err := db.Preload("CartItem.Bike", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).Preload("CartItem.Variant").Preload("User").Find(&cart, "user_id = ?", userID).Error; err != nil {
+		return nil, err
+	}
+
+	pricer, err := newBikePricer(db)
+	if err != nil {
 		return nil, err
 	}
 
 	var cartItemResponse []response.GetUserCartItemResponse
 
 	for _, val := range cart.CartItem {
-		totalPrice := float64(val.Quantity) * float64(val.Bike.Price)
+		val.Variant.Bike = val.Bike
+		pricer.applyToVariant(&val.Variant)
+		totalPrice := float64(val.Quantity) * float64(val.Variant.EffectivePrice())
 		cartItemResponse = append(cartItemResponse, response.GetUserCartItemResponse{
 			ID: val.ID,
 			Bike: response.GetUserCartItemBikeResponse{
@@ -42,6 +49,7 @@ func (s CartItemService) GetByUserID(c *gin.Context, userID uint) (*response.Get
 				Stock:       val.Bike.Stock,
 				Description: val.Bike.Description,
 			},
+			Variant:   toBikeVariantResponse(val.Variant),
 			CartID:    val.CartID,
 			Quantity:  val.Quantity,
 			Price:     totalPrice,
@@ -71,12 +79,24 @@ func (s CartItemService) Create(c *gin.Context, req request.CartItemCreateReques
 			return exceptions.NewCustomError(http.StatusBadRequest, "User not found")
 		}
 
-		if err := tx.Where("bike_id = ? AND cart_id = ?", req.BikeID, cart.ID).First(&cartItem).Error; err != nil {
+		// variants of archived bikes can't be added
+		var variant entity.BikeVariant
+		if err := tx.Select("id, bike_id").
+			Where("EXISTS (?)", tx.Model(&entity.Bike{}).Select("1").Where("bikes.id = bike_variants.bike_id")).
+			Take(&variant, req.VariantID).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return exceptions.NewCustomError(http.StatusNotFound, "Bike variant not found")
+			}
+			return err
+		}
+
+		if err := tx.Where("bike_variant_id = ? AND cart_id = ?", req.VariantID, cart.ID).First(&cartItem).Error; err != nil {
 			if err == gorm.ErrRecordNotFound {
 				cartItem = entity.CartItem{
-					CartID:   cart.ID,
-					BikeID:   req.BikeID,
-					Quantity: req.Quantity,
+					CartID:        cart.ID,
+					BikeID:        variant.BikeID,
+					BikeVariantID: variant.ID,
+					Quantity:      req.Quantity,
 				}
 				if err := tx.Create(&cartItem).Error; err != nil {
 					return err
@@ -112,7 +132,7 @@ func (s CartItemService) Update(c *gin.Context, req request.CartItemUpdateReques
 			return exceptions.NewCustomError(http.StatusBadRequest, "User not found")
 		}
 
-		if err := tx.Where("bike_id = ? AND cart_id = ?", req.BikeID, cart.ID).First(&cartItem).Error; err != nil {
+		if err := tx.Where("bike_variant_id = ? AND cart_id = ?", req.VariantID, cart.ID).First(&cartItem).Error; err != nil {
 			return exceptions.NewCustomError(http.StatusNotFound, "Cart item not found")
 		}
 
@@ -129,7 +149,7 @@ func (s CartItemService) Update(c *gin.Context, req request.CartItemUpdateReques
 
 	return s.toCartItemResponse(cartItem), nil
 }
-func (s CartItemService) Delete(c *gin.Context, bikeID, userID uint) error {
+func (s CartItemService) Delete(c *gin.Context, variantID, userID uint) error {
 	db, logger := utils.GetDBAndLogger(c)
 
 	var cart entity.Cart
@@ -140,7 +160,7 @@ func (s CartItemService) Delete(c *gin.Context, bikeID, userID uint) error {
 			return exceptions.NewCustomError(http.StatusBadRequest, "User not found")
 		}
 
-		result := tx.Where("bike_id = ? AND cart_id = ?", bikeID, cart.ID).Delete(&entity.CartItem{})
+		result := tx.Where("bike_variant_id = ? AND cart_id = ?", variantID, cart.ID).Delete(&entity.CartItem{})
 		if result.Error != nil {
 			return err
 		}
@@ -149,7 +169,7 @@ func (s CartItemService) Delete(c *gin.Context, bikeID, userID uint) error {
 			return exceptions.NewCustomError(http.StatusNotFound, "Cart item not found")
 		}
 
-		logger.Info("success deleting cart item", zap.Uint("cartID", cart.ID), zap.Uint("bikeID", bikeID))
+		logger.Info("success deleting cart item", zap.Uint("cartID", cart.ID), zap.Uint("variantID", variantID))
 
 		return nil
 	})
@@ -163,6 +183,7 @@ func (s CartItemService) toCartItemResponse(cartItem entity.CartItem) *response.
 		ID:        cartItem.ID,
 		CartID:    cartItem.CartID,
 		BikeID:    cartItem.BikeID,
+		VariantID: cartItem.BikeVariantID,
 		Quantity:  cartItem.Quantity,
 		CreatedAt: cartItem.CreatedAt,
 		UpdatedAt: cartItem.UpdatedAt,
@@ -1,7 +1,13 @@
 package services
 
 import (
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
 	"github.com/gin-gonic/gin"
+	"github.com/gowesmart/api-gowesmart/exceptions"
 	"github.com/gowesmart/api-gowesmart/model/entity"
 	"github.com/gowesmart/api-gowesmart/model/web"
 	"github.com/gowesmart/api-gowesmart/model/web/request"
@@ -9,8 +15,12 @@ import (
 	"github.com/gowesmart/api-gowesmart/utils"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// categoryResponseColumns are the category columns scanned into response.CategoryResponse.
+const categoryResponseColumns = "id, parent_id, name, slug, description, image_url, deleted_at"
+
 type CategoryService struct{}
 
 func NewCategoryService() *CategoryService {
@@ -23,16 +33,38 @@ func (service *CategoryService) CreateCategory(c *gin.Context, categoryReq *requ
 	var res response.CategoryResponse
 
 	category := entity.Category{
-		Name: categoryReq.Name,
+		Name:        categoryReq.Name,
+		Slug:        categoryReq.Slug,
+		Description: categoryReq.Description,
+		ImageUrl:    categoryReq.ImageUrl,
+	}
+	if category.Slug == "" {
+		category.Slug = utils.Slugify(categoryReq.Name)
+	}
+	if categoryReq.ParentID != nil && *categoryReq.ParentID != 0 {
+		category.ParentID = categoryReq.ParentID
 	}
 
 	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := ensureCategoryAvailable(tx, 0, category.Name, category.Slug); err != nil {
+			return err
+		}
+
+		if category.ParentID != nil {
+			if err := tx.Select("id").Take(&entity.Category{}, *category.ParentID).Error; err != nil {
+				if err == gorm.ErrRecordNotFound {
+					return exceptions.NewCustomError(http.StatusNotFound, "Parent category not found")
+				}
+				return err
+			}
+		}
+
 		if err := tx.Create(&category).Error; err != nil {
 			return err
 		}
 
 		if err := tx.Model(&entity.Category{}).
-			Select("id, name").
+			Select(categoryResponseColumns).
 			Take(&res, category.ID).Error; err != nil {
 			return err
 		}
@@ -55,18 +87,42 @@ func (service *CategoryService) UpdateCategory(c *gin.Context, id uint, category
 	var category entity.Category
 
 	err := db.Transaction(func(tx *gorm.DB) error {
-		if err := tx.First(&category, id).Error; err != nil {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&category, id).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return exceptions.NewCustomError(http.StatusNotFound, "Category not found")
+			}
 			return err
 		}
 
-		category.Name = categoryReq.Name
+		if categoryReq.Name != "" {
+			category.Name = categoryReq.Name
+		}
+		if categoryReq.Slug != "" {
+			category.Slug = categoryReq.Slug
+		}
+		if categoryReq.Description != nil {
+			category.Description = *categoryReq.Description
+		}
+		if categoryReq.ImageUrl != nil {
+			category.ImageUrl = *categoryReq.ImageUrl
+		}
+
+		if err := ensureCategoryAvailable(tx, category.ID, category.Name, category.Slug); err != nil {
+			return err
+		}
+
+		if categoryReq.ParentID != nil {
+			if err := moveCategory(tx, &category, *categoryReq.ParentID); err != nil {
+				return err
+			}
+		}
 
 		if err := tx.Save(&category).Error; err != nil {
 			return err
 		}
 
 		if err := tx.Model(&entity.Category{}).
-			Select("id, name").
+			Select(categoryResponseColumns).
 			Take(&res, category.ID).Error; err != nil {
 			return err
 		}
@@ -82,31 +138,104 @@ func (service *CategoryService) UpdateCategory(c *gin.Context, id uint, category
 	return &res, nil
 }
 
+// DeleteCategory archives a category together with its bikes, which share its archive time so that
+// restoring the category brings back exactly those bikes.
 func (service *CategoryService) DeleteCategory(c *gin.Context, id uint) error {
 	db, logger := utils.GetDBAndLogger(c)
 
+	var archivedBikes int64
+
 	err := db.Transaction(func(tx *gorm.DB) error {
-		if err := tx.Delete(&entity.Category{}, id).Error; err != nil {
+		archivedAt := time.Now()
+
+		var children int64
+		if err := tx.Model(&entity.Category{}).Where("parent_id = ?", id).Count(&children).Error; err != nil {
 			return err
 		}
-		return nil
+
+		if children > 0 {
+			return exceptions.NewCustomError(http.StatusConflict, "Category has subcategories, move or archive them first")
+		}
+
+		result := tx.Model(&entity.Category{}).Where("id = ?", id).Update("deleted_at", archivedAt)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return exceptions.NewCustomError(http.StatusNotFound, "Category not found")
+		}
+
+		var err error
+		archivedBikes, err = archiveBikes(tx, archivedAt, "category_id = ?", id)
+		return err
 	})
 
 	if err != nil {
 		return err
 	}
 
-	logger.Info("success deleting category", zap.Uint("categoryID", id))
+	logger.Info("success archiving category", zap.Uint("categoryID", id), zap.Int64("archivedBikes", archivedBikes))
 
 	return nil
 }
 
-func (service *CategoryService) GetAllCategories(c *gin.Context, paginationReq *web.PaginationRequest) ([]response.CategoryResponse, *web.Metadata, error) {
+func (service *CategoryService) RestoreCategory(c *gin.Context, id uint) (*response.CategoryResponse, error) {
+	db, logger := utils.GetDBAndLogger(c)
+
+	var category entity.Category
+	var restoredBikes int64
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("deleted_at IS NOT NULL").
+			Take(&category, id).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return exceptions.NewCustomError(http.StatusNotFound, "Archived category not found")
+			}
+			return err
+		}
+
+		if category.ParentID != nil {
+			if err := tx.Select("id").Take(&entity.Category{}, *category.ParentID).Error; err != nil {
+				if err == gorm.ErrRecordNotFound {
+					return exceptions.NewCustomError(http.StatusConflict, "The parent category is archived, restore it first")
+				}
+				return err
+			}
+		}
+
+		result := tx.Unscoped().Model(&entity.Bike{}).
+			Where("category_id = ? AND deleted_at = ?", id, category.DeletedAt.Time).
+			Update("deleted_at", nil)
+		if result.Error != nil {
+			return result.Error
+		}
+		restoredBikes = result.RowsAffected
+
+		return tx.Unscoped().Model(&category).Update("deleted_at", nil).Error
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("success restoring category", zap.Uint("categoryID", id), zap.Int64("restoredBikes", restoredBikes))
+
+	return service.GetCategoryByID(c, id)
+}
+
+func (service *CategoryService) GetAllCategories(c *gin.Context, categoryQueryReq *request.CategoryQueryRequest) ([]response.CategoryResponse, *web.Metadata, error) {
 	db, logger := utils.GetDBAndLogger(c)
 
 	var categories []response.CategoryResponse
 
-	query := db.Model(&entity.Category{}).Select("id, name")
+	if categoryQueryReq.IncludeArchived {
+		db = db.Unscoped()
+	}
+
+	paginationReq := &categoryQueryReq.PaginationRequest
+	query := db.Model(&entity.Category{}).Select(categoryResponseColumns)
 
 	// Count total data
 	var totalData int64
@@ -143,8 +272,9 @@ func (service *CategoryService) GetCategoryByID(c *gin.Context, id uint) (*respo
 
 	var res response.CategoryResponse
 
-	if err := db.Model(&entity.Category{}).
-		Select("id, name").
+	// archived categories still resolve for the bikes that point at them
+	if err := db.Unscoped().Model(&entity.Category{}).
+		Select(categoryResponseColumns).
 		Take(&res, id).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			logger.Warn("category not found", zap.Uint("categoryID", id))
@@ -159,3 +289,143 @@ func (service *CategoryService) GetCategoryByID(c *gin.Context, id uint) (*respo
 
 	return &res, nil
 }
+
+// GetCategoryTree returns the active categories as a tree, counting the active bikes in each of them.
+func (service *CategoryService) GetCategoryTree(c *gin.Context) ([]response.CategoryTreeResponse, error) {
+	db, logger := utils.GetDBAndLogger(c)
+
+	var categories []entity.Category
+	if err := db.Order("name").Find(&categories).Error; err != nil {
+		logger.Error("failed to fetch categories", zap.Error(err))
+		return nil, err
+	}
+
+	var counts []struct {
+		CategoryID uint
+		Count      int64
+	}
+	if err := db.Model(&entity.Bike{}).
+		Select("category_id, COUNT(*) AS count").
+		Group("category_id").
+		Scan(&counts).Error; err != nil {
+		logger.Error("failed to count bikes per category", zap.Error(err))
+		return nil, err
+	}
+
+	bikeCounts := make(map[uint]int64, len(counts))
+	for _, count := range counts {
+		bikeCounts[count.CategoryID] = count.Count
+	}
+
+	children := make(map[uint][]entity.Category)
+	var roots []entity.Category
+	for _, category := range categories {
+		if category.ParentID == nil {
+			roots = append(roots, category)
+			continue
+		}
+		children[*category.ParentID] = append(children[*category.ParentID], category)
+	}
+
+	var build func(categories []entity.Category) []response.CategoryTreeResponse
+	build = func(categories []entity.Category) []response.CategoryTreeResponse {
+		nodes := make([]response.CategoryTreeResponse, 0, len(categories))
+		for _, category := range categories {
+			node := response.CategoryTreeResponse{
+				ID:          category.ID,
+				ParentID:    category.ParentID,
+				Name:        category.Name,
+				Slug:        category.Slug,
+				Description: category.Description,
+				ImageUrl:    category.ImageUrl,
+				BikeCount:   bikeCounts[category.ID],
+				Children:    build(children[category.ID]),
+			}
+
+			node.TotalBikeCount = node.BikeCount
+			for _, child := range node.Children {
+				node.TotalBikeCount += child.TotalBikeCount
+			}
+
+			nodes = append(nodes, node)
+		}
+		return nodes
+	}
+
+	logger.Info("success fetching category tree", zap.Int("categories", len(categories)))
+
+	return build(roots), nil
+}
+
+// ensureCategoryAvailable fails with a 409 when another category, archived ones included, already
+// uses the name or slug.
+func ensureCategoryAvailable(tx *gorm.DB, id uint, name, slug string) error {
+	if slug == "" {
+		return exceptions.NewCustomError(http.StatusBadRequest, "Slug can't be derived from the name, set it explicitly")
+	}
+
+	var existing entity.Category
+	err := tx.Unscoped().Select("id, name, slug").
+		Where("(name = ? OR slug = ?) AND id <> ?", name, slug, id).
+		Take(&existing).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if existing.Slug == slug {
+		return exceptions.NewCustomError(http.StatusConflict, fmt.Sprintf("Slug %s already exists", slug))
+	}
+	return exceptions.NewCustomError(http.StatusConflict, fmt.Sprintf("Category %s already exists", name))
+}
+
+// moveCategory puts the category under parentID, or at the top level when parentID is 0, refusing
+// moves that would make the category its own ancestor.
+func moveCategory(tx *gorm.DB, category *entity.Category, parentID uint) error {
+	if parentID == 0 {
+		category.ParentID = nil
+		return nil
+	}
+
+	if err := tx.Select("id").Take(&entity.Category{}, parentID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return exceptions.NewCustomError(http.StatusNotFound, "Parent category not found")
+		}
+		return err
+	}
+
+	var subtree []uint
+	if err := tx.Raw(categorySubtreeSQL("id = ?", false), category.ID).Scan(&subtree).Error; err != nil {
+		return err
+	}
+
+	if slices.Contains(subtree, parentID) {
+		return exceptions.NewCustomError(http.StatusConflict, "A category can't be moved under itself or one of its subcategories")
+	}
+
+	category.ParentID = &parentID
+	return nil
+}
+
+// categorySubtreeSQL selects the ids of the categories matching condition and of everything below them,
+// leaving out archived categories when activeOnly is set.
+func categorySubtreeSQL(condition string, activeOnly bool) string {
+	active := ""
+	if activeOnly {
+		active = " AND categories.deleted_at IS NULL"
+	}
+
+	return "WITH RECURSIVE subtree AS (" +
+		"SELECT categories.id FROM categories WHERE " + condition + active +
+		" UNION ALL SELECT categories.id FROM categories JOIN subtree ON categories.parent_id = subtree.id WHERE TRUE" + active +
+		") SELECT id FROM subtree"
+}
+
+// categoryAncestorsSQL selects the id of the category given as its parameter and the ids of its parents,
+// archived ones included.
+const categoryAncestorsSQL = "WITH RECURSIVE ancestors AS (" +
+	"SELECT categories.id, categories.parent_id FROM categories WHERE categories.id = ?" +
+	" UNION ALL SELECT categories.id, categories.parent_id FROM categories JOIN ancestors ON categories.id = ancestors.parent_id" +
+	") SELECT id FROM ancestors"
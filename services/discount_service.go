@@ -0,0 +1,363 @@
+package services
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gowesmart/api-gowesmart/exceptions"
+	"github.com/gowesmart/api-gowesmart/model/entity"
+	"github.com/gowesmart/api-gowesmart/model/web"
+	"github.com/gowesmart/api-gowesmart/model/web/request"
+	"github.com/gowesmart/api-gowesmart/model/web/response"
+	"github.com/gowesmart/api-gowesmart/utils"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// bikeSalePriceExpr is the list price of a bike after its best active discount, for filtering and sorting
+// in SQL. It has to give the same prices as bikePricer.
+var bikeSalePriceExpr = fmt.Sprintf(
+	"(SELECT COALESCE(MIN(CASE WHEN discounts.type = '%s' THEN bikes.price - bikes.price::bigint * discounts.value / 100 ELSE GREATEST(bikes.price - discounts.value, 0) END), bikes.price) "+
+		"FROM discounts WHERE discounts.starts_at <= NOW() AND (discounts.ends_at IS NULL OR discounts.ends_at > NOW()) AND ("+
+		"(discounts.target_type = '%s' AND discounts.target_id = bikes.id) OR "+
+		"(discounts.target_type = '%s' AND discounts.target_id = bikes.brand_id) OR "+
+		"(discounts.target_type = '%s' AND discounts.target_id IN (%s))))",
+	entity.DiscountTypePercentage, entity.DiscountTargetBike, entity.DiscountTargetBrand, entity.DiscountTargetCategory,
+	"WITH RECURSIVE ancestors AS (SELECT categories.id, categories.parent_id FROM categories WHERE categories.id = bikes.category_id"+
+		" UNION ALL SELECT categories.id, categories.parent_id FROM categories JOIN ancestors ON categories.id = ancestors.parent_id"+
+		") SELECT id FROM ancestors",
+)
+
+type DiscountService struct{}
+
+func NewDiscountService() *DiscountService {
+	return &DiscountService{}
+}
+
+func (service *DiscountService) CreateDiscount(c *gin.Context, discountReq *request.CreateDiscountRequest) (*response.DiscountResponse, error) {
+	db, logger := utils.GetDBAndLogger(c)
+
+	discount := entity.Discount{
+		Name:       discountReq.Name,
+		Type:       discountReq.Type,
+		Value:      discountReq.Value,
+		TargetType: discountReq.TargetType,
+		TargetID:   discountReq.TargetID,
+		StartsAt:   time.Now(),
+		EndsAt:     discountReq.EndsAt,
+	}
+	if discountReq.StartsAt != nil {
+		discount.StartsAt = *discountReq.StartsAt
+	}
+
+	if err := validateDiscount(&discount); err != nil {
+		return nil, err
+	}
+
+	if err := ensureDiscountTargetExists(db, discount.TargetType, discount.TargetID); err != nil {
+		return nil, err
+	}
+
+	if err := db.Create(&discount).Error; err != nil {
+		logger.Error("failed to create discount", zap.Error(err))
+		return nil, err
+	}
+
+	logger.Info("success creating discount", zap.Uint("discountID", discount.ID))
+
+	res := toDiscountResponse(discount, time.Now())
+	return &res, nil
+}
+
+func (service *DiscountService) UpdateDiscount(c *gin.Context, id uint, discountReq *request.UpdateDiscountRequest) (*response.DiscountResponse, error) {
+	db, logger := utils.GetDBAndLogger(c)
+
+	var discount entity.Discount
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&discount, id).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return exceptions.NewCustomError(http.StatusNotFound, "Discount not found")
+			}
+			return err
+		}
+
+		if discountReq.Name != "" {
+			discount.Name = discountReq.Name
+		}
+		if discountReq.Value != 0 {
+			discount.Value = discountReq.Value
+		}
+		if discountReq.StartsAt != nil {
+			discount.StartsAt = *discountReq.StartsAt
+		}
+		if discountReq.EndsAt != nil {
+			discount.EndsAt = discountReq.EndsAt
+		}
+
+		if err := validateDiscount(&discount); err != nil {
+			return err
+		}
+
+		return tx.Save(&discount).Error
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("success updating discount", zap.Uint("discountID", discount.ID))
+
+	res := toDiscountResponse(discount, time.Now())
+	return &res, nil
+}
+
+func (service *DiscountService) DeleteDiscount(c *gin.Context, id uint) error {
+	db, logger := utils.GetDBAndLogger(c)
+
+	result := db.Delete(&entity.Discount{}, id)
+	if result.Error != nil {
+		logger.Error("failed to delete discount", zap.Error(result.Error))
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return exceptions.NewCustomError(http.StatusNotFound, "Discount not found")
+	}
+
+	logger.Info("success deleting discount", zap.Uint("discountID", id))
+
+	return nil
+}
+
+func (service *DiscountService) GetAllDiscounts(c *gin.Context, discountQueryReq *request.DiscountQueryRequest) ([]response.DiscountResponse, *web.Metadata, error) {
+	db, logger := utils.GetDBAndLogger(c)
+
+	now := time.Now()
+	query := db.Model(&entity.Discount{})
+	if discountQueryReq.TargetType != "" {
+		query = query.Where("target_type = ?", discountQueryReq.TargetType)
+	}
+	if discountQueryReq.TargetID != 0 {
+		query = query.Where("target_id = ?", discountQueryReq.TargetID)
+	}
+	switch discountQueryReq.Status {
+	case entity.DiscountStatusScheduled:
+		query = query.Where("starts_at > ?", now)
+	case entity.DiscountStatusActive:
+		query = query.Where("starts_at <= ? AND (ends_at IS NULL OR ends_at > ?)", now, now)
+	case entity.DiscountStatusExpired:
+		query = query.Where("ends_at <= ?", now)
+	}
+
+	paginationReq := &discountQueryReq.PaginationRequest
+
+	var totalData int64
+	if err := query.Count(&totalData).Error; err != nil {
+		logger.Error("failed to count discounts", zap.Error(err))
+		return nil, nil, err
+	}
+	paginationReq.TotalData = totalData
+
+	var discounts []entity.Discount
+	offset := paginationReq.GetOffset()
+	limit := paginationReq.GetLimit()
+	if err := query.Order("starts_at DESC, id DESC").Offset(offset).Limit(limit).Find(&discounts).Error; err != nil {
+		logger.Error("failed to fetch discounts", zap.Error(err))
+		return nil, nil, err
+	}
+
+	paginationReq.TotalPages = int((totalData + int64(limit) - 1) / int64(limit))
+
+	metadata := &web.Metadata{
+		Page:       &paginationReq.Page,
+		Limit:      &paginationReq.Limit,
+		TotalPages: &paginationReq.TotalPages,
+		TotalData:  &paginationReq.TotalData,
+	}
+
+	res := make([]response.DiscountResponse, 0, len(discounts))
+	for _, discount := range discounts {
+		res = append(res, toDiscountResponse(discount, now))
+	}
+
+	logger.Info("success fetching all discounts", zap.Int("total_data", int(totalData)), zap.Int("total_pages", paginationReq.TotalPages))
+
+	return res, metadata, nil
+}
+
+func (service *DiscountService) GetDiscountByID(c *gin.Context, id uint) (*response.DiscountResponse, error) {
+	db, logger := utils.GetDBAndLogger(c)
+
+	var discount entity.Discount
+	if err := db.Take(&discount, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, exceptions.NewCustomError(http.StatusNotFound, "Discount not found")
+		}
+		logger.Error("failed to fetch discount", zap.Error(err))
+		return nil, err
+	}
+
+	logger.Info("success fetching discount", zap.Uint("discountID", id))
+
+	res := toDiscountResponse(discount, time.Now())
+	return &res, nil
+}
+
+func validateDiscount(discount *entity.Discount) error {
+	if discount.Type == entity.DiscountTypePercentage && discount.Value > 100 {
+		return exceptions.NewCustomError(http.StatusBadRequest, "A percentage discount is at most 100")
+	}
+	if discount.EndsAt != nil && !discount.EndsAt.After(discount.StartsAt) {
+		return exceptions.NewCustomError(http.StatusBadRequest, "A discount has to end after it starts")
+	}
+	return nil
+}
+
+func ensureDiscountTargetExists(db *gorm.DB, targetType string, targetID uint) error {
+	var target any
+	var notFound string
+	switch targetType {
+	case entity.DiscountTargetBike:
+		target, notFound = &entity.Bike{}, "Bike not found"
+	case entity.DiscountTargetCategory:
+		target, notFound = &entity.Category{}, "Category not found"
+	default:
+		target, notFound = &entity.Brand{}, "Brand not found"
+	}
+
+	if err := db.Select("id").Take(target, targetID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return exceptions.NewCustomError(http.StatusNotFound, notFound)
+		}
+		return err
+	}
+	return nil
+}
+
+// findBikeDiscounts returns every discount that targets a bike, through the bike itself, its brand or its
+// category and the parents of that category, newest first.
+func findBikeDiscounts(db *gorm.DB, bikeID, categoryID uint, brandID *uint) ([]response.DiscountResponse, error) {
+	query := db.Where("target_type = ? AND target_id = ?", entity.DiscountTargetBike, bikeID).
+		Or("target_type = ? AND target_id IN ("+categoryAncestorsSQL+")", entity.DiscountTargetCategory, categoryID)
+	if brandID != nil {
+		query = query.Or("target_type = ? AND target_id = ?", entity.DiscountTargetBrand, *brandID)
+	}
+
+	var discounts []entity.Discount
+	if err := query.Order("starts_at DESC, id DESC").Find(&discounts).Error; err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	res := make([]response.DiscountResponse, 0, len(discounts))
+	for _, discount := range discounts {
+		res = append(res, toDiscountResponse(discount, now))
+	}
+	return res, nil
+}
+
+// bikePricer applies the discounts active when it was created to bike prices. Discounts don't stack,
+// a price gets the discount that lowers it most.
+type bikePricer struct {
+	at        time.Time
+	discounts []entity.Discount
+	// parents maps each category to its parent, it is only loaded for category discounts
+	parents map[uint]*uint
+}
+
+func newBikePricer(db *gorm.DB) (*bikePricer, error) {
+	pricer := &bikePricer{at: time.Now()}
+
+	if err := db.Where("starts_at <= ? AND (ends_at IS NULL OR ends_at > ?)", pricer.at, pricer.at).
+		Order("id").
+		Find(&pricer.discounts).Error; err != nil {
+		return nil, err
+	}
+
+	for _, discount := range pricer.discounts {
+		if discount.TargetType != entity.DiscountTargetCategory {
+			continue
+		}
+
+		var categories []entity.Category
+		if err := db.Unscoped().Select("id, parent_id").Find(&categories).Error; err != nil {
+			return nil, err
+		}
+
+		pricer.parents = make(map[uint]*uint, len(categories))
+		for _, category := range categories {
+			pricer.parents[category.ID] = category.ParentID
+		}
+		break
+	}
+
+	return pricer, nil
+}
+
+// best returns the discount giving the lowest price for a bike, or nil when no discount applies.
+func (p *bikePricer) best(bikeID, categoryID uint, brandID *uint, price int) *entity.Discount {
+	var best *entity.Discount
+	for i := range p.discounts {
+		discount := &p.discounts[i]
+		if !p.targets(discount, bikeID, categoryID, brandID) {
+			continue
+		}
+		if best == nil || discount.Apply(price) < best.Apply(price) {
+			best = discount
+		}
+	}
+	return best
+}
+
+func (p *bikePricer) targets(discount *entity.Discount, bikeID, categoryID uint, brandID *uint) bool {
+	switch discount.TargetType {
+	case entity.DiscountTargetBike:
+		return discount.TargetID == bikeID
+	case entity.DiscountTargetBrand:
+		return brandID != nil && *brandID == discount.TargetID
+	case entity.DiscountTargetCategory:
+		for id := &categoryID; id != nil; id = p.parents[*id] {
+			if *id == discount.TargetID {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// applyToVariant sets the discount of a variant whose bike is loaded with its price, category and brand.
+func (p *bikePricer) applyToVariant(variant *entity.BikeVariant) {
+	variant.Discount = p.best(variant.Bike.ID, variant.Bike.CategoryID, variant.Bike.BrandID, variant.ListPrice())
+}
+
+// applyToBike fills the sale price and discount of a bike.
+func (p *bikePricer) applyToBike(bike *response.BikeResponse) {
+	bike.SalePrice = bike.Price
+	bike.Discount = nil
+
+	if discount := p.best(bike.ID, bike.CategoryID, bike.BrandID, bike.Price); discount != nil {
+		bike.SalePrice = discount.Apply(bike.Price)
+		res := toDiscountResponse(*discount, p.at)
+		bike.Discount = &res
+	}
+}
+
+func toDiscountResponse(discount entity.Discount, at time.Time) response.DiscountResponse {
+	return response.DiscountResponse{
+		ID:         discount.ID,
+		Name:       discount.Name,
+		Type:       discount.Type,
+		Value:      discount.Value,
+		TargetType: discount.TargetType,
+		TargetID:   discount.TargetID,
+		StartsAt:   discount.StartsAt,
+		EndsAt:     discount.EndsAt,
+		Status:     discount.Status(at),
+		CreatedAt:  discount.CreatedAt,
+		UpdatedAt:  discount.UpdatedAt,
+	}
+}
@@ -0,0 +1,62 @@
+package services
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gowesmart/api-gowesmart/model/entity"
+	"github.com/gowesmart/api-gowesmart/model/web/request"
+)
+
+// MidtransTestServerKey is the server key the notifications in testdata/midtrans were signed with.
+const MidtransTestServerKey = "SB-Mid-server-gowesmart-test"
+
+// LoadMidtransNotification reads a hand-built notification in the format Midtrans sends, for order 1042
+// of 250000.00 and signed with MidtransTestServerKey.
+func LoadMidtransNotification(t testing.TB, name string) request.MidtransNotificationRequest {
+	t.Helper()
+
+	body, err := os.ReadFile(filepath.Join("testdata", "midtrans", name+".json"))
+	if err != nil {
+		t.Fatalf("reading notification %s: %v", name, err)
+	}
+
+	var notification request.MidtransNotificationRequest
+	if err := json.Unmarshal(body, &notification); err != nil {
+		t.Fatalf("decoding notification %s: %v", name, err)
+	}
+
+	return notification
+}
+
+func TestToTransactionStatus(t *testing.T) {
+	tests := []struct {
+		notification string
+		want         string
+		handled      bool
+	}{
+		{"settlement", entity.TransactionStatusPaid, true},
+		{"capture_accept", entity.TransactionStatusPaid, true},
+		{"capture_challenge", entity.TransactionStatusPending, true},
+		{"pending", entity.TransactionStatusPending, true},
+		{"deny", entity.TransactionStatusCancelled, true},
+		{"cancel", entity.TransactionStatusCancelled, true},
+		{"expire", entity.TransactionStatusExpired, true},
+		{"refund", entity.TransactionStatusRefunded, true},
+		{"partial_refund", entity.TransactionStatusPartiallyRefunded, true},
+		{"authorize", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.notification, func(t *testing.T) {
+			notification := LoadMidtransNotification(t, tt.notification)
+
+			got, handled := toTransactionStatus(notification.TransactionStatus, notification.FraudStatus)
+			if got != tt.want || handled != tt.handled {
+				t.Errorf("toTransactionStatus(%q, %q) = %q, %v, want %q, %v", notification.TransactionStatus, notification.FraudStatus, got, handled, tt.want, tt.handled)
+			}
+		})
+	}
+}
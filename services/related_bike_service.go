@@ -0,0 +1,177 @@
+package services
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gowesmart/api-gowesmart/exceptions"
+	"github.com/gowesmart/api-gowesmart/model/entity"
+	"github.com/gowesmart/api-gowesmart/model/web/request"
+	"github.com/gowesmart/api-gowesmart/model/web/response"
+	"github.com/gowesmart/api-gowesmart/utils"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	relatedBikeReasonBoughtTogether = "bought_together"
+	relatedBikeReasonSimilar        = "similar"
+)
+
+const (
+	defaultRelatedBikes = 8
+	// relatedBikesPerBike is how many relations are kept per bike, the most a request can ask for.
+	relatedBikesPerBike = 20
+)
+
+// bikeRelationsSQL ranks, for every bike, the bikes bought in the same paid transactions by how many
+// transactions had both, keeping the best relatedBikesPerBike.
+var bikeRelationsSQL = fmt.Sprintf(`
+	INSERT INTO bike_relations (bike_id, related_bike_id, score, computed_at)
+	SELECT bike_id, related_bike_id, score, NOW() FROM (
+		SELECT pairs.bike_id, pairs.related_bike_id, COUNT(*) AS score,
+			ROW_NUMBER() OVER (PARTITION BY pairs.bike_id ORDER BY COUNT(*) DESC, pairs.related_bike_id) AS rank
+		FROM (
+			SELECT DISTINCT orders.transaction_id, orders.bike_id, others.bike_id AS related_bike_id
+			FROM orders
+			JOIN orders AS others ON others.transaction_id = orders.transaction_id AND others.bike_id <> orders.bike_id
+			JOIN transactions ON transactions.id = orders.transaction_id
+			WHERE transactions.status NOT IN ('%s', '%s', '%s')
+		) AS pairs
+		GROUP BY pairs.bike_id, pairs.related_bike_id
+	) AS ranked
+	WHERE rank <= %d`,
+	entity.TransactionStatusPending, entity.TransactionStatusCancelled, entity.TransactionStatusExpired, relatedBikesPerBike,
+)
+
+type RelatedBikeService struct{}
+
+func NewRelatedBikeService() *RelatedBikeService {
+	return &RelatedBikeService{}
+}
+
+// GetRelatedBikes recommends the bikes most often bought together with a bike, topped up with bikes of
+// the same category at the closest price when there aren't enough.
+func (service *RelatedBikeService) GetRelatedBikes(c *gin.Context, bikeID uint, relatedQueryReq *request.RelatedBikeQueryRequest) ([]response.RelatedBikeResponse, error) {
+	db, logger := utils.GetDBAndLogger(c)
+
+	limit := relatedQueryReq.Limit
+	if limit == 0 {
+		limit = defaultRelatedBikes
+	}
+
+	var bike entity.Bike
+	if err := db.Select("id, category_id, price").Take(&bike, bikeID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, exceptions.NewCustomError(http.StatusNotFound, "Bike not found")
+		}
+		return nil, err
+	}
+
+	var relations []entity.BikeRelation
+	if err := db.Joins("JOIN bikes ON bikes.id = bike_relations.related_bike_id AND bikes.deleted_at IS NULL").
+		Where("bike_relations.bike_id = ?", bikeID).
+		Order("bike_relations.score DESC, bike_relations.related_bike_id").
+		Limit(limit).
+		Find(&relations).Error; err != nil {
+		logger.Error("failed to fetch bike relations", zap.Error(err))
+		return nil, err
+	}
+
+	relatedIDs := make([]uint, 0, limit)
+	scores := make(map[uint]int, len(relations))
+	for _, relation := range relations {
+		relatedIDs = append(relatedIDs, relation.RelatedBikeID)
+		scores[relation.RelatedBikeID] = relation.Score
+	}
+
+	if len(relatedIDs) < limit {
+		query := db.Model(&entity.Bike{}).
+			Where("category_id = ? AND id <> ?", bike.CategoryID, bike.ID)
+		if len(relatedIDs) > 0 {
+			query = query.Where("id NOT IN ?", relatedIDs)
+		}
+
+		var similarIDs []uint
+		if err := query.Order(fmt.Sprintf("is_available DESC, ABS(price - %d), id", bike.Price)).
+			Limit(limit-len(relatedIDs)).
+			Pluck("id", &similarIDs).Error; err != nil {
+			logger.Error("failed to fetch similar bikes", zap.Error(err))
+			return nil, err
+		}
+		relatedIDs = append(relatedIDs, similarIDs...)
+	}
+
+	res := make([]response.RelatedBikeResponse, 0, len(relatedIDs))
+	if len(relatedIDs) == 0 {
+		return res, nil
+	}
+
+	var bikes []response.BikeResponse
+	if err := db.Model(&entity.Bike{}).Select(bikeResponseColumns).Where("id IN ?", relatedIDs).Find(&bikes).Error; err != nil {
+		logger.Error("failed to fetch related bikes", zap.Error(err))
+		return nil, err
+	}
+
+	variants, err := findBikeVariants(db, relatedIDs)
+	if err != nil {
+		logger.Error("failed to fetch bike variants", zap.Error(err))
+		return nil, err
+	}
+
+	pricer, err := newBikePricer(db)
+	if err != nil {
+		logger.Error("failed to fetch discounts", zap.Error(err))
+		return nil, err
+	}
+
+	bikesByID := make(map[uint]response.BikeResponse, len(bikes))
+	for _, related := range bikes {
+		related.Variants = variants[related.ID]
+		pricer.applyToBike(&related)
+		bikesByID[related.ID] = related
+	}
+
+	for _, id := range relatedIDs {
+		related := response.RelatedBikeResponse{BikeResponse: bikesByID[id], Reason: relatedBikeReasonSimilar}
+		if score, ok := scores[id]; ok {
+			related.Reason = relatedBikeReasonBoughtTogether
+			related.BoughtTogether = score
+		}
+		res = append(res, related)
+	}
+
+	logger.Info("success fetching related bikes", zap.Uint("bikeID", bikeID), zap.Int("bought_together", len(relations)), zap.Int("total", len(res)))
+
+	return res, nil
+}
+
+// RecomputeRelatedBikes rebuilds the bike relations from the orders of paid transactions and returns
+// how many were stored. Readers keep seeing the previous relations until the rebuild commits.
+func (service *RelatedBikeService) RecomputeRelatedBikes(db *gorm.DB, logger *zap.Logger) (int64, error) {
+	var relations int64
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM bike_relations").Error; err != nil {
+			return err
+		}
+
+		result := tx.Exec(bikeRelationsSQL)
+		if result.Error != nil {
+			return result.Error
+		}
+		relations = result.RowsAffected
+
+		return nil
+	})
+
+	if err != nil {
+		logger.Error("failed to recompute related bikes", zap.Error(err))
+		return 0, err
+	}
+
+	logger.Info("success recomputing related bikes", zap.Int64("relations", relations))
+
+	return relations, nil
+}
@@ -1,7 +1,11 @@
 package services
 
 import (
+	"fmt"
+	"net/http"
+
 	"github.com/gin-gonic/gin"
+	"github.com/gowesmart/api-gowesmart/exceptions"
 	"github.com/gowesmart/api-gowesmart/model/entity"
 	"github.com/gowesmart/api-gowesmart/model/web"
 	"github.com/gowesmart/api-gowesmart/model/web/request"
@@ -35,14 +39,7 @@ func (service *ReviewService) CreateReview(c *gin.Context, reviewReq *request.Cr
 			return err
 		}
 
-		var bike entity.Bike
-		if err := tx.Where("id = ?", review.BikeID).First(&bike).Error; err != nil {
-			return err
-		}
-
-		bike.Rating += review.Rating
-		bike.Reviewers += 1
-		if err := tx.Save(&bike).Error; err != nil {
+		if err := adjustBikeRating(tx, review.BikeID, 0, review.Rating); err != nil {
 			return err
 		}
 
@@ -84,12 +81,7 @@ func (service *ReviewService) UpdateReview(c *gin.Context, reviewReq *request.Up
 			return err
 		}
 
-		var bike entity.Bike
-		if err := tx.Where("id = ?", review.BikeID).First(&bike).Error; err != nil {
-			return err
-		}
-
-		bike.Rating -= review.Rating
+		oldRating := review.Rating
 
 		review.Comment = reviewReq.Comment
 		review.Rating = reviewReq.Rating
@@ -98,8 +90,7 @@ func (service *ReviewService) UpdateReview(c *gin.Context, reviewReq *request.Up
 			return err
 		}
 
-		bike.Rating += review.Rating
-		if err := tx.Save(&bike).Error; err != nil {
+		if err := adjustBikeRating(tx, review.BikeID, oldRating, review.Rating); err != nil {
 			return err
 		}
 
@@ -130,14 +121,7 @@ func (service *ReviewService) DeleteReview(c *gin.Context, id uint) error {
 			return err
 		}
 
-		var bike entity.Bike
-		if err := tx.Where("id = ?", review.BikeID).First(&bike).Error; err != nil {
-			return err
-		}
-
-		bike.Rating -= review.Rating
-		bike.Reviewers -= 1
-		if err := tx.Save(&bike).Error; err != nil {
+		if err := adjustBikeRating(tx, review.BikeID, review.Rating, 0); err != nil {
 			return err
 		}
 
@@ -270,3 +254,86 @@ func (service *ReviewService) GetReviewByBikeID(c *gin.Context, bikeID uint) ([]
 
 	return reviews, nil
 }
+
+// RecomputeBikeRatings rebuilds the review aggregates of every bike from the reviews table, repairing
+// any drift. It returns how many bikes had to be corrected.
+func (service *ReviewService) RecomputeBikeRatings(db *gorm.DB, logger *zap.Logger) (int64, error) {
+	result := db.Exec(`
+		WITH aggregates AS (
+			SELECT bikes.id AS bike_id,
+				COALESCE(SUM(reviews.rating), 0) AS rating,
+				COUNT(reviews.id) AS reviewers,
+				COALESCE(ROUND(AVG(reviews.rating), 2), 0) AS average_rating,
+				COUNT(reviews.id) FILTER (WHERE reviews.rating = 1) AS stars1,
+				COUNT(reviews.id) FILTER (WHERE reviews.rating = 2) AS stars2,
+				COUNT(reviews.id) FILTER (WHERE reviews.rating = 3) AS stars3,
+				COUNT(reviews.id) FILTER (WHERE reviews.rating = 4) AS stars4,
+				COUNT(reviews.id) FILTER (WHERE reviews.rating = 5) AS stars5
+			FROM bikes LEFT JOIN reviews ON reviews.bike_id = bikes.id
+			GROUP BY bikes.id
+		)
+		UPDATE bikes SET
+			rating = aggregates.rating,
+			reviewers = aggregates.reviewers,
+			average_rating = aggregates.average_rating,
+			stars1 = aggregates.stars1,
+			stars2 = aggregates.stars2,
+			stars3 = aggregates.stars3,
+			stars4 = aggregates.stars4,
+			stars5 = aggregates.stars5
+		FROM aggregates
+		WHERE aggregates.bike_id = bikes.id
+			AND (bikes.rating, bikes.reviewers, bikes.average_rating, bikes.stars1, bikes.stars2, bikes.stars3, bikes.stars4, bikes.stars5)
+			IS DISTINCT FROM (aggregates.rating, aggregates.reviewers, aggregates.average_rating, aggregates.stars1, aggregates.stars2, aggregates.stars3, aggregates.stars4, aggregates.stars5)`)
+	if result.Error != nil {
+		logger.Error("failed to recompute bike ratings", zap.Error(result.Error))
+		return 0, result.Error
+	}
+
+	logger.Info("success recomputing bike ratings", zap.Int64("corrected", result.RowsAffected))
+
+	return result.RowsAffected, nil
+}
+
+// adjustBikeRating moves a review's rating from oldRating to newRating in the bike aggregates, where 0
+// stands for no review. It is a single UPDATE so concurrent reviews of the same bike can't lose each
+// other's changes.
+func adjustBikeRating(tx *gorm.DB, bikeID uint, oldRating, newRating int) error {
+	reviewers := 0
+	if oldRating == 0 {
+		reviewers = 1
+	}
+	if newRating == 0 {
+		reviewers = -1
+	}
+
+	ratingDelta := newRating - oldRating
+	assignments := map[string]any{
+		"rating":    gorm.Expr("rating + ?", ratingDelta),
+		"reviewers": gorm.Expr("reviewers + ?", reviewers),
+		// the right hand sides all see the row before the update
+		"average_rating": gorm.Expr("CASE WHEN reviewers + ? > 0 THEN ROUND((rating + ?)::numeric / (reviewers + ?), 2) ELSE 0 END", reviewers, ratingDelta, reviewers),
+	}
+	if oldRating != newRating {
+		if oldRating != 0 {
+			column := fmt.Sprintf("stars%d", oldRating)
+			assignments[column] = gorm.Expr(column + " - 1")
+		}
+		if newRating != 0 {
+			column := fmt.Sprintf("stars%d", newRating)
+			assignments[column] = gorm.Expr(column + " + 1")
+		}
+	}
+
+	// archived bikes can still be reviewed by the people who bought them
+	result := tx.Unscoped().Model(&entity.Bike{}).Where("id = ?", bikeID).UpdateColumns(assignments)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return exceptions.NewCustomError(http.StatusNotFound, "Bike not found")
+	}
+
+	return nil
+}
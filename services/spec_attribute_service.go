@@ -0,0 +1,413 @@
+package services
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gowesmart/api-gowesmart/exceptions"
+	"github.com/gowesmart/api-gowesmart/model/entity"
+	"github.com/gowesmart/api-gowesmart/model/web/request"
+	"github.com/gowesmart/api-gowesmart/model/web/response"
+	"github.com/gowesmart/api-gowesmart/utils"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// bikeSpecExistsSQL matches bikes having a spec with the key given as its first parameter, whose value
+// satisfies the condition filled in with fmt.
+const bikeSpecExistsSQL = "EXISTS (SELECT 1 FROM bike_specs JOIN spec_attributes ON spec_attributes.id = bike_specs.spec_attribute_id " +
+	"WHERE bike_specs.bike_id = bikes.id AND spec_attributes.key = ? AND %s)"
+
+type SpecAttributeService struct{}
+
+func NewSpecAttributeService() *SpecAttributeService {
+	return &SpecAttributeService{}
+}
+
+func (service *SpecAttributeService) CreateSpecAttribute(c *gin.Context, attributeReq *request.CreateSpecAttributeRequest) (*response.SpecAttributeResponse, error) {
+	db, logger := utils.GetDBAndLogger(c)
+
+	var res response.SpecAttributeResponse
+
+	attribute := entity.SpecAttribute{
+		CategoryID: attributeReq.CategoryID,
+		Key:        attributeReq.Key,
+		Name:       attributeReq.Name,
+		Type:       attributeReq.Type,
+		Unit:       attributeReq.Unit,
+		Options:    attributeReq.Options,
+	}
+
+	if attribute.Type != entity.SpecTypeEnum && len(attribute.Options) > 0 {
+		return nil, exceptions.NewCustomError(http.StatusBadRequest, "Only enum attributes have options")
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Select("id").Take(&entity.Category{}, attribute.CategoryID).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return exceptions.NewCustomError(http.StatusNotFound, "Category not found")
+			}
+			return err
+		}
+
+		// the attributes of a category apply to its subcategories, so a key is used once along a branch
+		var clash entity.SpecAttribute
+		err := tx.Select("id, category_id").
+			Where("key = ?", attribute.Key).
+			Where("(category_id IN ("+categoryAncestorsSQL+") OR category_id IN ("+categorySubtreeSQL("id = ?", false)+"))", attribute.CategoryID, attribute.CategoryID).
+			Take(&clash).Error
+		if err == nil {
+			return exceptions.NewCustomError(http.StatusConflict, fmt.Sprintf("Spec %s is already defined for category %d", attribute.Key, clash.CategoryID))
+		}
+		if err != gorm.ErrRecordNotFound {
+			return err
+		}
+
+		// search filters don't know the category, a key has to mean the same kind of value everywhere
+		var otherTypes []string
+		if err := tx.Model(&entity.SpecAttribute{}).
+			Where("key = ? AND type <> ?", attribute.Key, attribute.Type).
+			Distinct().
+			Pluck("type", &otherTypes).Error; err != nil {
+			return err
+		}
+		if len(otherTypes) > 0 {
+			return exceptions.NewCustomError(http.StatusConflict, fmt.Sprintf("Spec %s is a %s in other categories", attribute.Key, otherTypes[0]))
+		}
+
+		if err := tx.Create(&attribute).Error; err != nil {
+			return err
+		}
+
+		res = toSpecAttributeResponse(attribute)
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("success creating spec attribute", zap.Uint("specAttributeID", attribute.ID))
+
+	return &res, nil
+}
+
+func (service *SpecAttributeService) UpdateSpecAttribute(c *gin.Context, id uint, attributeReq *request.UpdateSpecAttributeRequest) (*response.SpecAttributeResponse, error) {
+	db, logger := utils.GetDBAndLogger(c)
+
+	var res response.SpecAttributeResponse
+	var attribute entity.SpecAttribute
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&attribute, id).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return exceptions.NewCustomError(http.StatusNotFound, "Spec attribute not found")
+			}
+			return err
+		}
+
+		if attributeReq.Name != "" {
+			attribute.Name = attributeReq.Name
+		}
+		if attributeReq.Unit != nil {
+			attribute.Unit = *attributeReq.Unit
+		}
+
+		if attributeReq.Options != nil {
+			if attribute.Type != entity.SpecTypeEnum {
+				return exceptions.NewCustomError(http.StatusBadRequest, "Only enum attributes have options")
+			}
+
+			// options still used by bikes can't be removed
+			var used []string
+			if err := tx.Model(&entity.BikeSpec{}).
+				Where("spec_attribute_id = ? AND value NOT IN ?", attribute.ID, attributeReq.Options).
+				Distinct().
+				Order("value").
+				Pluck("value", &used).Error; err != nil {
+				return err
+			}
+			if len(used) > 0 {
+				return exceptions.NewDetailedError(http.StatusConflict, "Options are still used by bikes", used)
+			}
+
+			attribute.Options = attributeReq.Options
+		}
+
+		if err := tx.Save(&attribute).Error; err != nil {
+			return err
+		}
+
+		res = toSpecAttributeResponse(attribute)
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("success updating spec attribute", zap.Uint("specAttributeID", attribute.ID))
+
+	return &res, nil
+}
+
+// DeleteSpecAttribute deletes an attribute together with the values bikes have for it.
+func (service *SpecAttributeService) DeleteSpecAttribute(c *gin.Context, id uint) error {
+	db, logger := utils.GetDBAndLogger(c)
+
+	result := db.Delete(&entity.SpecAttribute{}, id)
+	if result.Error != nil {
+		logger.Error("failed to delete spec attribute", zap.Error(result.Error))
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return exceptions.NewCustomError(http.StatusNotFound, "Spec attribute not found")
+	}
+
+	logger.Info("success deleting spec attribute", zap.Uint("specAttributeID", id))
+
+	return nil
+}
+
+// GetSpecAttributes lists the attributes that apply to a category, or all of them when no category is given.
+func (service *SpecAttributeService) GetSpecAttributes(c *gin.Context, attributeQueryReq *request.SpecAttributeQueryRequest) ([]response.SpecAttributeResponse, error) {
+	db, logger := utils.GetDBAndLogger(c)
+
+	var attributes []entity.SpecAttribute
+	var err error
+
+	if attributeQueryReq.CategoryID != 0 {
+		attributes, err = findCategorySpecAttributes(db, attributeQueryReq.CategoryID)
+	} else {
+		err = db.Order("category_id, name").Find(&attributes).Error
+	}
+	if err != nil {
+		logger.Error("failed to fetch spec attributes", zap.Error(err))
+		return nil, err
+	}
+
+	res := make([]response.SpecAttributeResponse, 0, len(attributes))
+	for _, attribute := range attributes {
+		res = append(res, toSpecAttributeResponse(attribute))
+	}
+
+	logger.Info("success fetching spec attributes", zap.Int("total_data", len(res)))
+
+	return res, nil
+}
+
+// findCategorySpecAttributes returns the attributes of a category and of its parents.
+func findCategorySpecAttributes(db *gorm.DB, categoryID uint) ([]entity.SpecAttribute, error) {
+	var attributes []entity.SpecAttribute
+	if err := db.Where("category_id IN ("+categoryAncestorsSQL+")", categoryID).
+		Order("name").
+		Find(&attributes).Error; err != nil {
+		return nil, err
+	}
+	return attributes, nil
+}
+
+// checkBikeSpecs matches spec values to the attributes of the bike's category, keyed by spec key. It
+// returns the values to store, the attributes whose value is removed and a message per invalid spec.
+func checkBikeSpecs(attributes []entity.SpecAttribute, specs map[string]any) ([]entity.BikeSpec, []uint, map[string]string) {
+	attributesByKey := make(map[string]entity.SpecAttribute, len(attributes))
+	for _, attribute := range attributes {
+		attributesByKey[attribute.Key] = attribute
+	}
+
+	var values []entity.BikeSpec
+	var removed []uint
+	invalid := make(map[string]string)
+
+	for key, value := range specs {
+		attribute, ok := attributesByKey[key]
+		if !ok {
+			invalid[key] = "Unknown spec for this category"
+			continue
+		}
+
+		if value == nil {
+			removed = append(removed, attribute.ID)
+			continue
+		}
+
+		text, number, ok := attribute.Encode(value)
+		if !ok {
+			switch attribute.Type {
+			case entity.SpecTypeEnum:
+				invalid[key] = "Must be one of " + strings.Join(attribute.Options, ", ")
+			default:
+				invalid[key] = "Must be a " + attribute.Type
+			}
+			continue
+		}
+
+		values = append(values, entity.BikeSpec{SpecAttributeID: attribute.ID, Value: text, NumberValue: number})
+	}
+
+	return values, removed, invalid
+}
+
+// saveBikeSpecs sets the given specs of a bike and removes those with a null value.
+func saveBikeSpecs(tx *gorm.DB, bikeID, categoryID uint, specs map[string]any) error {
+	if len(specs) == 0 {
+		return nil
+	}
+
+	attributes, err := findCategorySpecAttributes(tx, categoryID)
+	if err != nil {
+		return err
+	}
+
+	values, removed, invalid := checkBikeSpecs(attributes, specs)
+	if len(invalid) > 0 {
+		return exceptions.NewDetailedError(http.StatusBadRequest, "Invalid specs", invalid)
+	}
+
+	if len(removed) > 0 {
+		if err := tx.Where("bike_id = ? AND spec_attribute_id IN ?", bikeID, removed).Delete(&entity.BikeSpec{}).Error; err != nil {
+			return err
+		}
+	}
+
+	if len(values) == 0 {
+		return nil
+	}
+
+	for i := range values {
+		values[i].BikeID = bikeID
+	}
+
+	return tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "bike_id"}, {Name: "spec_attribute_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"value", "number_value", "updated_at"}),
+	}).Create(&values).Error
+}
+
+// dropInapplicableBikeSpecs removes the specs that don't belong to the bike's category any more,
+// after the bike moved to another category.
+func dropInapplicableBikeSpecs(tx *gorm.DB, bikeID, categoryID uint) error {
+	return tx.Where("bike_id = ? AND spec_attribute_id NOT IN (SELECT id FROM spec_attributes WHERE category_id IN ("+categoryAncestorsSQL+"))", bikeID, categoryID).
+		Delete(&entity.BikeSpec{}).Error
+}
+
+func findBikeSpecs(db *gorm.DB, bikeID uint) ([]response.BikeSpecResponse, error) {
+	specs, err := findBikesSpecs(db, []uint{bikeID})
+	if err != nil {
+		return nil, err
+	}
+
+	if specs[bikeID] == nil {
+		return []response.BikeSpecResponse{}, nil
+	}
+	return specs[bikeID], nil
+}
+
+// findBikesSpecs loads the specs of several bikes at once keyed by bike id, each sorted by name.
+func findBikesSpecs(db *gorm.DB, bikeIDs []uint) (map[uint][]response.BikeSpecResponse, error) {
+	var specs []entity.BikeSpec
+	if err := db.Joins("SpecAttribute").
+		Where("bike_specs.bike_id IN ?", bikeIDs).
+		Order(`"SpecAttribute".name`).
+		Find(&specs).Error; err != nil {
+		return nil, err
+	}
+
+	res := make(map[uint][]response.BikeSpecResponse, len(bikeIDs))
+	for _, spec := range specs {
+		res[spec.BikeID] = append(res[spec.BikeID], response.BikeSpecResponse{
+			Key:   spec.SpecAttribute.Key,
+			Name:  spec.SpecAttribute.Name,
+			Type:  spec.SpecAttribute.Type,
+			Unit:  spec.SpecAttribute.Unit,
+			Value: spec.SpecAttribute.Decode(&spec),
+		})
+	}
+
+	return res, nil
+}
+
+// normalizeSpecFilters checks the spec search filters and writes numbers the way they are stored, so
+// spec[wheel_size]=29.0 finds bikes with a wheel size of 29.
+func normalizeSpecFilters(specs map[string]string) (map[string]string, error) {
+	normalized := make(map[string]string, len(specs))
+
+	for key, value := range specs {
+		if strings.HasSuffix(key, "_min") || strings.HasSuffix(key, "_max") {
+			number, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+			if err != nil {
+				return nil, exceptions.NewCustomError(http.StatusBadRequest, fmt.Sprintf("spec[%s] must be a number", key))
+			}
+			normalized[key] = strconv.FormatFloat(number, 'f', -1, 64)
+			continue
+		}
+
+		var values []string
+		for _, v := range strings.Split(value, ",") {
+			v = strings.TrimSpace(v)
+			if number, err := strconv.ParseFloat(v, 64); err == nil {
+				v = strconv.FormatFloat(number, 'f', -1, 64)
+			} else if boolean, err := strconv.ParseBool(v); err == nil && strings.EqualFold(v, strconv.FormatBool(boolean)) {
+				v = strconv.FormatBool(boolean)
+			}
+			if v != "" && !slices.Contains(values, v) {
+				values = append(values, v)
+			}
+		}
+		if len(values) > 0 {
+			normalized[key] = strings.Join(values, ",")
+		}
+	}
+
+	return normalized, nil
+}
+
+// filterBikeSpecs keeps the bikes whose specs match every filter.
+func filterBikeSpecs(query *gorm.DB, specs map[string]string) *gorm.DB {
+	keys := make([]string, 0, len(specs))
+	for key := range specs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	// normalizeSpecFilters has checked the range values are numbers
+	for _, key := range keys {
+		value := specs[key]
+		switch {
+		case strings.HasSuffix(key, "_min"):
+			number, _ := strconv.ParseFloat(value, 64)
+			query = query.Where(fmt.Sprintf(bikeSpecExistsSQL, "bike_specs.number_value >= ?"), strings.TrimSuffix(key, "_min"), number)
+		case strings.HasSuffix(key, "_max"):
+			number, _ := strconv.ParseFloat(value, 64)
+			query = query.Where(fmt.Sprintf(bikeSpecExistsSQL, "bike_specs.number_value <= ?"), strings.TrimSuffix(key, "_max"), number)
+		default:
+			query = query.Where(fmt.Sprintf(bikeSpecExistsSQL, "bike_specs.value IN ?"), key, strings.Split(value, ","))
+		}
+	}
+
+	return query
+}
+
+func toSpecAttributeResponse(attribute entity.SpecAttribute) response.SpecAttributeResponse {
+	options := attribute.Options
+	if options == nil {
+		options = []string{}
+	}
+
+	return response.SpecAttributeResponse{
+		ID:         attribute.ID,
+		CategoryID: attribute.CategoryID,
+		Key:        attribute.Key,
+		Name:       attribute.Name,
+		Type:       attribute.Type,
+		Unit:       attribute.Unit,
+		Options:    options,
+	}
+}
@@ -0,0 +1,323 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gowesmart/api-gowesmart/exceptions"
+	"github.com/gowesmart/api-gowesmart/model/entity"
+	"github.com/gowesmart/api-gowesmart/model/web"
+	"github.com/gowesmart/api-gowesmart/model/web/request"
+	"github.com/gowesmart/api-gowesmart/model/web/response"
+	"github.com/gowesmart/api-gowesmart/utils"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// stockAlertDeliveryTimeout bounds a single delivery through the notifier.
+	stockAlertDeliveryTimeout = 30 * time.Second
+	// stockAlertClaimTimeout is how long an alert stays claimed by a worker that doesn't report back.
+	stockAlertClaimTimeout = 5 * time.Minute
+)
+
+// stockAlertColumns are the stock alert columns with the names the alert is shown with, the variant
+// may have been deleted since.
+const stockAlertColumns = "stock_alerts.*, bikes.name AS bike_name, bike_variants.sku"
+
+// stockAlertRow is a stock alert with the names it is shown with.
+type stockAlertRow struct {
+	entity.StockAlert
+	BikeName string
+	SKU      string
+}
+
+type StockAlertService struct {
+	notifier utils.Notifier
+}
+
+func NewStockAlertService(notifier utils.Notifier) *StockAlertService {
+	return &StockAlertService{
+		notifier: notifier,
+	}
+}
+
+func (service *StockAlertService) GetStockAlerts(c *gin.Context, alertQueryReq *request.StockAlertQueryRequest) ([]response.StockAlertResponse, *web.Metadata, error) {
+	db, logger := utils.GetDBAndLogger(c)
+
+	query := stockAlertQuery(db)
+	if alertQueryReq.BikeID != 0 {
+		query = query.Where("stock_alerts.bike_id = ?", alertQueryReq.BikeID)
+	}
+	if alertQueryReq.Type != "" {
+		query = query.Where("stock_alerts.type = ?", alertQueryReq.Type)
+	}
+	switch alertQueryReq.Status {
+	case entity.StockAlertStatusOpen:
+		query = query.Where("stock_alerts.resolved_at IS NULL AND stock_alerts.acknowledged_at IS NULL")
+	case entity.StockAlertStatusAcknowledged:
+		query = query.Where("stock_alerts.resolved_at IS NULL AND stock_alerts.acknowledged_at IS NOT NULL")
+	case entity.StockAlertStatusResolved:
+		query = query.Where("stock_alerts.resolved_at IS NOT NULL")
+	}
+
+	paginationReq := &alertQueryReq.PaginationRequest
+
+	var totalData int64
+	if err := query.Count(&totalData).Error; err != nil {
+		logger.Error("failed to count stock alerts", zap.Error(err))
+		return nil, nil, err
+	}
+	paginationReq.TotalData = totalData
+
+	offset := paginationReq.GetOffset()
+	limit := paginationReq.GetLimit()
+
+	var rows []stockAlertRow
+	if err := query.Select(stockAlertColumns).
+		Order("stock_alerts.created_at DESC, stock_alerts.id DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&rows).Error; err != nil {
+		logger.Error("failed to fetch stock alerts", zap.Error(err))
+		return nil, nil, err
+	}
+
+	paginationReq.TotalPages = int((totalData + int64(limit) - 1) / int64(limit))
+
+	res := make([]response.StockAlertResponse, 0, len(rows))
+	for _, row := range rows {
+		res = append(res, toStockAlertResponse(row))
+	}
+
+	metadata := &web.Metadata{
+		Page:       &paginationReq.Page,
+		Limit:      &paginationReq.Limit,
+		TotalPages: &paginationReq.TotalPages,
+		TotalData:  &paginationReq.TotalData,
+	}
+
+	logger.Info("success fetching stock alerts", zap.Int("total_data", int(totalData)), zap.Int("total_pages", paginationReq.TotalPages))
+
+	return res, metadata, nil
+}
+
+// AcknowledgeStockAlert marks an alert as seen by an admin, acknowledging it again keeps the first acknowledgement.
+func (service *StockAlertService) AcknowledgeStockAlert(c *gin.Context, id uint, adminID uint) (*response.StockAlertResponse, error) {
+	db, logger := utils.GetDBAndLogger(c)
+
+	var row stockAlertRow
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var alert entity.StockAlert
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Take(&alert, id).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return exceptions.NewCustomError(http.StatusNotFound, "Stock alert not found")
+			}
+			return err
+		}
+
+		if alert.AcknowledgedAt == nil {
+			if err := tx.Model(&alert).Updates(map[string]any{
+				"acknowledged_at": time.Now(),
+				"acknowledged_by": adminID,
+			}).Error; err != nil {
+				return err
+			}
+		}
+
+		return stockAlertQuery(tx).Select(stockAlertColumns).Where("stock_alerts.id = ?", id).Take(&row).Error
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("success acknowledging stock alert", zap.Uint("stockAlertID", id))
+
+	res := toStockAlertResponse(row)
+	return &res, nil
+}
+
+// NotifyPending delivers at most limit alerts the notifier hasn't delivered yet, oldest first, and
+// returns how many were delivered. Alerts are claimed with FOR UPDATE SKIP LOCKED in a short transaction
+// of their own so several instances don't deliver the same alert, and are delivered once it committed so
+// no lock is held while waiting on the notifier. Each alert is then marked delivered or failed on its own,
+// failed ones are retried on the next run and claims of an instance that died are taken over after
+// stockAlertClaimTimeout.
+func (service *StockAlertService) NotifyPending(ctx context.Context, db *gorm.DB, logger *zap.Logger, limit int) (int, error) {
+	var rows []stockAlertRow
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+
+		if err := stockAlertQuery(tx).
+			Clauses(clause.Locking{Strength: "UPDATE", Table: clause.Table{Name: "stock_alerts"}, Options: "SKIP LOCKED"}).
+			Select(stockAlertColumns).
+			Where("stock_alerts.notified_at IS NULL").
+			Where("stock_alerts.notify_claimed_at IS NULL OR stock_alerts.notify_claimed_at < ?", now.Add(-stockAlertClaimTimeout)).
+			Order("stock_alerts.id").
+			Limit(limit).
+			Find(&rows).Error; err != nil {
+			return err
+		}
+
+		if len(rows) == 0 {
+			return nil
+		}
+
+		ids := make([]uint, 0, len(rows))
+		for _, row := range rows {
+			ids = append(ids, row.ID)
+		}
+
+		return tx.Model(&entity.StockAlert{}).Where("id IN ?", ids).Update("notify_claimed_at", now).Error
+	})
+
+	if err != nil {
+		logger.Error("failed to claim stock alerts", zap.Error(err))
+		return 0, err
+	}
+
+	notified := 0
+	for _, row := range rows {
+		deliverCtx, cancel := context.WithTimeout(ctx, stockAlertDeliveryTimeout)
+		deliverErr := service.notifier.Notify(deliverCtx, toStockAlertNotification(row))
+		cancel()
+
+		update := map[string]any{"notify_claimed_at": nil}
+		if deliverErr != nil {
+			logger.Warn("failed to deliver stock alert", zap.Uint("stockAlertID", row.ID), zap.Error(deliverErr))
+			update["notify_attempts"] = gorm.Expr("notify_attempts + 1")
+			message := deliverErr.Error()
+			if len(message) > 255 {
+				message = strings.ToValidUTF8(message[:255], "")
+			}
+			update["notify_error"] = message
+		} else {
+			update["notified_at"] = time.Now()
+			update["notify_error"] = ""
+			notified++
+		}
+
+		if err := db.Model(&entity.StockAlert{}).Where("id = ?", row.ID).Updates(update).Error; err != nil {
+			logger.Error("failed to mark stock alert", zap.Uint("stockAlertID", row.ID), zap.Error(err))
+			return notified, err
+		}
+	}
+
+	if notified > 0 {
+		logger.Info("success delivering stock alerts", zap.Int("notified", notified))
+	}
+
+	return notified, nil
+}
+
+func stockAlertQuery(db *gorm.DB) *gorm.DB {
+	return db.Table("stock_alerts").
+		Joins("LEFT JOIN bikes ON bikes.id = stock_alerts.bike_id").
+		Joins("LEFT JOIN bike_variants ON bike_variants.id = stock_alerts.bike_variant_id")
+}
+
+// checkStockLevel follows a change of delta to a variant's stock. It raises an alert when the stock
+// falls to the variant's low stock threshold or runs out, unless one is still unresolved, resolves the
+// alerts the stock recovered from, and keeps variants with AutoAvailability available only while they
+// have stock. The caller syncs the bike stock afterwards.
+func checkStockLevel(tx *gorm.DB, variantID uint, delta int) error {
+	if delta == 0 {
+		return nil
+	}
+
+	var variant entity.BikeVariant
+	if err := tx.Select("id, bike_id, stock, is_available, low_stock_threshold, auto_availability").Take(&variant, variantID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			// the variant was deleted, there is no stock left to watch
+			return nil
+		}
+		return err
+	}
+
+	if variant.AutoAvailability && variant.IsAvailable != (variant.Stock > 0) {
+		if err := tx.Model(&variant).Update("is_available", variant.Stock > 0).Error; err != nil {
+			return err
+		}
+	}
+
+	if delta > 0 {
+		return tx.Model(&entity.StockAlert{}).
+			Where("bike_variant_id = ? AND resolved_at IS NULL", variant.ID).
+			Where("(type = ? AND ? > 0) OR (type = ? AND ? > threshold)", entity.StockAlertOutOfStock, variant.Stock, entity.StockAlertLowStock, variant.Stock).
+			Update("resolved_at", time.Now()).Error
+	}
+
+	before := variant.Stock - delta
+
+	var alertType string
+	switch {
+	case variant.Stock <= 0 && before > 0:
+		alertType = entity.StockAlertOutOfStock
+	case variant.Stock <= variant.LowStockThreshold && before > variant.LowStockThreshold:
+		alertType = entity.StockAlertLowStock
+	default:
+		return nil
+	}
+
+	var unresolved int64
+	if err := tx.Model(&entity.StockAlert{}).
+		Where("bike_variant_id = ? AND type = ? AND resolved_at IS NULL", variant.ID, alertType).
+		Count(&unresolved).Error; err != nil {
+		return err
+	}
+	if unresolved > 0 {
+		return nil
+	}
+
+	return tx.Create(&entity.StockAlert{
+		BikeID:        variant.BikeID,
+		BikeVariantID: variant.ID,
+		Type:          alertType,
+		Stock:         variant.Stock,
+		Threshold:     variant.LowStockThreshold,
+	}).Error
+}
+
+func toStockAlertNotification(row stockAlertRow) utils.Notification {
+	notification := utils.Notification{
+		Event: "stock_alert." + row.Type,
+		Data:  toStockAlertResponse(row),
+	}
+
+	if row.Type == entity.StockAlertOutOfStock {
+		notification.Subject = fmt.Sprintf("%s (%s) is out of stock", row.BikeName, row.SKU)
+		notification.Message = fmt.Sprintf("%s (%s) ran out of stock.", row.BikeName, row.SKU)
+	} else {
+		notification.Subject = fmt.Sprintf("%s (%s) is low on stock", row.BikeName, row.SKU)
+		notification.Message = fmt.Sprintf("%s (%s) is down to %d in stock, its threshold is %d.", row.BikeName, row.SKU, row.Stock, row.Threshold)
+	}
+
+	return notification
+}
+
+func toStockAlertResponse(row stockAlertRow) response.StockAlertResponse {
+	return response.StockAlertResponse{
+		ID:             row.ID,
+		BikeID:         row.BikeID,
+		BikeName:       row.BikeName,
+		BikeVariantID:  row.BikeVariantID,
+		SKU:            row.SKU,
+		Type:           row.Type,
+		Stock:          row.Stock,
+		Threshold:      row.Threshold,
+		Status:         row.Status(),
+		NotifiedAt:     row.NotifiedAt,
+		AcknowledgedAt: row.AcknowledgedAt,
+		AcknowledgedBy: row.AcknowledgedBy,
+		ResolvedAt:     row.ResolvedAt,
+		CreatedAt:      row.CreatedAt,
+	}
+}
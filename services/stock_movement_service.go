@@ -0,0 +1,269 @@
+package services
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gowesmart/api-gowesmart/exceptions"
+	"github.com/gowesmart/api-gowesmart/model/entity"
+	"github.com/gowesmart/api-gowesmart/model/web"
+	"github.com/gowesmart/api-gowesmart/model/web/request"
+	"github.com/gowesmart/api-gowesmart/model/web/response"
+	"github.com/gowesmart/api-gowesmart/utils"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// stockLedgerSQL compares the stock of every variant with the sum of its stock movements.
+const stockLedgerSQL = `SELECT bike_variants.bike_id, bike_variants.id AS bike_variant_id, bike_variants.sku, bike_variants.stock, COALESCE(ledger.stock, 0) AS ledger_stock
+	FROM bike_variants
+	LEFT JOIN (SELECT bike_variant_id, SUM(quantity) AS stock FROM stock_movements GROUP BY bike_variant_id) AS ledger ON ledger.bike_variant_id = bike_variants.id
+	WHERE bike_variants.stock <> COALESCE(ledger.stock, 0)
+	ORDER BY bike_variants.id
+	FOR UPDATE OF bike_variants`
+
+type StockMovementService struct{}
+
+func NewStockMovementService() *StockMovementService {
+	return &StockMovementService{}
+}
+
+// CreateStockMovement posts a movement to a variant's stock, failing with a 409 when it would take
+// more stock than the variant has.
+func (service *StockMovementService) CreateStockMovement(c *gin.Context, bikeID, variantID uint, movementReq *request.CreateStockMovementRequest, adminID uint) (*response.StockMovementResponse, error) {
+	db, logger := utils.GetDBAndLogger(c)
+
+	if movementReq.Type != entity.StockMovementAdjustment && movementReq.Quantity < 0 {
+		return nil, exceptions.NewCustomError(http.StatusBadRequest, fmt.Sprintf("Quantity of a %s must be positive", movementReq.Type))
+	}
+
+	var movement entity.StockMovement
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var variant entity.BikeVariant
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id, bike_id, stock").
+			Where("bike_id = ?", bikeID).
+			Take(&variant, variantID).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return exceptions.NewCustomError(http.StatusNotFound, "Bike variant not found")
+			}
+			return err
+		}
+
+		if variant.Stock+movementReq.Quantity < 0 {
+			return exceptions.NewCustomError(http.StatusConflict, fmt.Sprintf("Bike variant only has %d in stock", variant.Stock))
+		}
+
+		if err := tx.Model(&variant).Update("stock", gorm.Expr("stock + ?", movementReq.Quantity)).Error; err != nil {
+			return err
+		}
+
+		movement = entity.StockMovement{
+			BikeID:        variant.BikeID,
+			BikeVariantID: variant.ID,
+			Type:          movementReq.Type,
+			Quantity:      movementReq.Quantity,
+			Reference:     movementReq.Reference,
+			Note:          movementReq.Note,
+			ActorID:       &adminID,
+		}
+		if err := tx.Create(&movement).Error; err != nil {
+			return err
+		}
+
+		if err := checkStockLevel(tx, variant.ID, movement.Quantity); err != nil {
+			return err
+		}
+
+		return syncBikeStock(tx, variant.BikeID)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("success posting stock movement", zap.Uint("bikeID", bikeID), zap.Uint("variantID", variantID), zap.Int("quantity", movement.Quantity))
+
+	res := toStockMovementResponse(movement)
+	return &res, nil
+}
+
+// GetStockMovements lists the stock movements of a bike, newest first. Archived bikes keep their history.
+func (service *StockMovementService) GetStockMovements(c *gin.Context, bikeID uint, movementQueryReq *request.StockMovementQueryRequest) ([]response.StockMovementResponse, *web.Metadata, error) {
+	db, logger := utils.GetDBAndLogger(c)
+
+	if err := db.Unscoped().Select("id").Take(&entity.Bike{}, bikeID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil, exceptions.NewCustomError(http.StatusNotFound, "Bike not found")
+		}
+		return nil, nil, err
+	}
+
+	query := db.Model(&entity.StockMovement{}).Where("bike_id = ?", bikeID)
+	if movementQueryReq.VariantID != 0 {
+		query = query.Where("bike_variant_id = ?", movementQueryReq.VariantID)
+	}
+	if movementQueryReq.Type != "" {
+		query = query.Where("type = ?", movementQueryReq.Type)
+	}
+
+	paginationReq := &movementQueryReq.PaginationRequest
+
+	var totalData int64
+	if err := query.Count(&totalData).Error; err != nil {
+		logger.Error("failed to count stock movements", zap.Error(err))
+		return nil, nil, err
+	}
+	paginationReq.TotalData = totalData
+
+	offset := paginationReq.GetOffset()
+	limit := paginationReq.GetLimit()
+
+	var movements []entity.StockMovement
+	if err := query.Order("created_at DESC, id DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&movements).Error; err != nil {
+		logger.Error("failed to fetch stock movements", zap.Error(err))
+		return nil, nil, err
+	}
+
+	paginationReq.TotalPages = int((totalData + int64(limit) - 1) / int64(limit))
+
+	res := make([]response.StockMovementResponse, 0, len(movements))
+	for _, movement := range movements {
+		res = append(res, toStockMovementResponse(movement))
+	}
+
+	metadata := &web.Metadata{
+		Page:       &paginationReq.Page,
+		Limit:      &paginationReq.Limit,
+		TotalPages: &paginationReq.TotalPages,
+		TotalData:  &paginationReq.TotalData,
+	}
+
+	logger.Info("success fetching stock movements", zap.Uint("bikeID", bikeID), zap.Int("total_data", int(totalData)))
+
+	return res, metadata, nil
+}
+
+// ReconcileStock resets every variant whose stock doesn't match the sum of its stock movements to
+// what the ledger says, syncs the stock of their bikes and returns the variants it corrected.
+func (service *StockMovementService) ReconcileStock(c *gin.Context) ([]response.StockDiscrepancyResponse, error) {
+	db, logger := utils.GetDBAndLogger(c)
+
+	discrepancies := []response.StockDiscrepancyResponse{}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Raw(stockLedgerSQL).Scan(&discrepancies).Error; err != nil {
+			return err
+		}
+
+		variantIDs := make([]uint, 0, len(discrepancies))
+		for _, discrepancy := range discrepancies {
+			if err := tx.Model(&entity.BikeVariant{}).Where("id = ?", discrepancy.BikeVariantID).Update("stock", discrepancy.LedgerStock).Error; err != nil {
+				return err
+			}
+			if err := checkStockLevel(tx, discrepancy.BikeVariantID, discrepancy.LedgerStock-discrepancy.Stock); err != nil {
+				return err
+			}
+			variantIDs = append(variantIDs, discrepancy.BikeVariantID)
+		}
+
+		if len(variantIDs) == 0 {
+			return nil
+		}
+
+		return syncVariantBikeStock(tx, variantIDs)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("success reconciling stock", zap.Int("reconciled", len(discrepancies)))
+
+	return discrepancies, nil
+}
+
+// stockChange says why a variant's stock moves, every change of stock is recorded with one in the ledger.
+type stockChange struct {
+	Type      string
+	Reference string
+	Note      string
+	ActorID   *uint
+}
+
+func transactionStockChange(movementType string, transactionID int, actorID *uint) stockChange {
+	return stockChange{Type: movementType, Reference: fmt.Sprintf("transaction:%d", transactionID), ActorID: actorID}
+}
+
+// moveVariantStock adds delta to a variant's stock, records the movement and checks the new stock
+// level, the caller syncs the bike stock afterwards. The movement is still recorded when the variant
+// was deleted in the meantime, e.g. while a reservation on it was pending, only its stock is gone.
+func moveVariantStock(tx *gorm.DB, bikeID uint, variantID uint, delta int, change stockChange) error {
+	result := tx.Model(&entity.BikeVariant{}).Where("id = ?", variantID).Update("stock", gorm.Expr("stock + ?", delta))
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if err := recordStockMovement(tx, bikeID, variantID, delta, change); err != nil {
+		return err
+	}
+
+	if result.RowsAffected == 0 {
+		return nil
+	}
+
+	return checkStockLevel(tx, variantID, delta)
+}
+
+// setVariantStock sets a variant's stock to a counted quantity and records the difference, the
+// caller syncs the bike stock afterwards.
+func setVariantStock(tx *gorm.DB, variantID uint, stock int, change stockChange) error {
+	var variant entity.BikeVariant
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id, bike_id, stock").Take(&variant, variantID).Error; err != nil {
+		return err
+	}
+
+	if variant.Stock == stock {
+		return nil
+	}
+
+	return moveVariantStock(tx, variant.BikeID, variantID, stock-variant.Stock, change)
+}
+
+// recordStockMovement adds a movement of a variant's stock to the ledger without changing the stock,
+// for callers that already did. It doesn't need the variant to still exist.
+func recordStockMovement(tx *gorm.DB, bikeID uint, variantID uint, quantity int, change stockChange) error {
+	if quantity == 0 {
+		return nil
+	}
+
+	return tx.Create(&entity.StockMovement{
+		BikeID:        bikeID,
+		BikeVariantID: variantID,
+		Type:          change.Type,
+		Quantity:      quantity,
+		Reference:     change.Reference,
+		Note:          change.Note,
+		ActorID:       change.ActorID,
+	}).Error
+}
+
+func toStockMovementResponse(movement entity.StockMovement) response.StockMovementResponse {
+	return response.StockMovementResponse{
+		ID:            movement.ID,
+		BikeID:        movement.BikeID,
+		BikeVariantID: movement.BikeVariantID,
+		Type:          movement.Type,
+		Quantity:      movement.Quantity,
+		Reference:     movement.Reference,
+		Note:          movement.Note,
+		ActorID:       movement.ActorID,
+		CreatedAt:     movement.CreatedAt,
+	}
+}
@@ -1,7 +1,12 @@
 package services
 
 import (
+	"bytes"
+	"fmt"
 	"net/http"
+	"sort"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gowesmart/api-gowesmart/exceptions"
@@ -12,12 +17,23 @@ import (
 	"github.com/gowesmart/api-gowesmart/utils"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
-type TransactionService struct{}
+type TransactionService struct {
+	paymentGateway      utils.PaymentGateway
+	paymentWindow       time.Duration
+	restoreCartOnExpiry bool
+	invoiceIssuer       utils.InvoiceIssuer
+}
 
-func NewTransactionService() *TransactionService {
-	return &TransactionService{}
+func NewTransactionService(paymentGateway utils.PaymentGateway, paymentWindow time.Duration, restoreCartOnExpiry bool, invoiceIssuer utils.InvoiceIssuer) *TransactionService {
+	return &TransactionService{
+		paymentGateway:      paymentGateway,
+		paymentWindow:       paymentWindow,
+		restoreCartOnExpiry: restoreCartOnExpiry,
+		invoiceIssuer:       invoiceIssuer,
+	}
 }
 
 func (t TransactionService) GetAll(c *gin.Context, paginationReq *web.PaginationRequest) ([]response.GetAllTransactionResponse, *web.Metadata, error) {
@@ -38,7 +54,7 @@ func (t TransactionService) GetAll(c *gin.Context, paginationReq *web.Pagination
 	if err := query.Offset(offset).
 		Limit(limit).
 		Preload("User", func(db *gorm.DB) *gorm.DB { return db.Select("id, username") }).
-		Preload("Order.Bike").
+		Preload("Order.Bike", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
 		Find(&transactions).Error; err != nil {
 		logger.Error("failed to fetch transactions", zap.Error(err))
 		return nil, nil, err
@@ -63,11 +79,24 @@ func (t TransactionService) GetAll(c *gin.Context, paginationReq *web.Pagination
 	return results, metadata, nil
 }
 
-func (t TransactionService) GetById(c *gin.Context, transactionId int) (response.TransactionResponse, error) {
+// GetById returns a transaction with its orders, status history and refunds. Only the buyer or an admin can get it.
+func (t TransactionService) GetById(c *gin.Context, transactionId int, userID uint, isAdmin bool) (response.TransactionResponse, error) {
 	db, _ := utils.GetDBAndLogger(c)
 
+	query := db.Preload("Order").
+		Preload("StatusHistory", func(db *gorm.DB) *gorm.DB { return db.Order("created_at asc, id asc") }).
+		Preload("Refunds", func(db *gorm.DB) *gorm.DB { return db.Order("created_at asc, id asc") }).
+		Preload("Refunds.Items").
+		Where("id = ?", transactionId)
+	if !isAdmin {
+		query = query.Where("user_id = ?", userID)
+	}
+
 	var transaction entity.Transaction
-	if err := db.Preload("Order").Where("id = ?", transactionId).First(&transaction).Error; err != nil {
+	if err := query.First(&transaction).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return response.TransactionResponse{}, exceptions.NewCustomError(http.StatusNotFound, "Transaction not found")
+		}
 		return response.TransactionResponse{}, err
 	}
 
@@ -77,64 +106,104 @@ func (t TransactionService) GetById(c *gin.Context, transactionId int) (response
 }
 
 func (t TransactionService) Create(c *gin.Context, payloads []request.TransactionCreate, userID int) (response.CreateTransactionResponse, error) {
-	db, _ := utils.GetDBAndLogger(c)
-	var response response.CreateTransactionResponse
+	db, logger := utils.GetDBAndLogger(c)
 
-	err := db.Transaction(func(tx *gorm.DB) error {
-		transaction := toTransactionEntity(userID, payloads)
+	if len(payloads) == 0 {
+		return response.CreateTransactionResponse{}, exceptions.NewCustomError(http.StatusBadRequest, "Transaction must contain at least one bike")
+	}
+
+	var transaction entity.Transaction
 
-		if err := tx.Create(&transaction).Error; err != nil {
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		transaction, err = t.createTransaction(tx, logger, userID, payloads)
+		if err != nil {
 			return err
 		}
 
+		variantIDs := make([]uint, 0, len(payloads))
 		for _, payload := range payloads {
-			var cart entity.Cart
+			variantIDs = append(variantIDs, payload.VariantID)
+		}
 
-			if err := tx.Where("user_id = ?", userID).Select("id").First(&cart).Error; err != nil {
-				return err
-			}
+		return tx.Where("cart_id IN (?)", tx.Model(&entity.Cart{}).Select("id").Where("user_id = ?", userID)).
+			Where("bike_variant_id IN ?", variantIDs).
+			Delete(&entity.CartItem{}).Error
+	})
 
-			if err := tx.Model(&entity.CartItem{}).Where("bike_id = ?", payload.BikeID).Where("cart_id = ?", cart.ID).Delete(&entity.CartItem{}).Error; err != nil {
-				return err
-			}
+	if err != nil {
+		return response.CreateTransactionResponse{}, err
+	}
 
-			if err := createOrder(tx, userID, transaction.ID, payload); err != nil {
-				return err
+	return toCreateTransactionResponse(transaction), nil
+}
+
+// Checkout turns the user's cart, or only the given cart items, into a transaction
+// and removes the checked out items from the cart.
+func (t TransactionService) Checkout(c *gin.Context, checkoutReq *request.CartCheckoutRequest, userID int) (response.CreateTransactionResponse, error) {
+	db, logger := utils.GetDBAndLogger(c)
+
+	var transaction entity.Transaction
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var cart entity.Cart
+		if err := tx.Where("user_id = ?", userID).Select("id").First(&cart).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return exceptions.NewCustomError(http.StatusBadRequest, "Cart not found")
 			}
+			return err
 		}
 
-		response.TransactionID = transaction.ID
+		query := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("cart_id = ?", cart.ID)
+		if len(checkoutReq.CartItemIDs) > 0 {
+			query = query.Where("id IN ?", checkoutReq.CartItemIDs)
+		}
 
-		var user entity.User
-		if err := tx.Where("id = ?", userID).First(&user).Error; err != nil {
+		var cartItems []entity.CartItem
+		if err := query.Order("id").Find(&cartItems).Error; err != nil {
 			return err
 		}
 
-		paymentPayload := utils.PaymentPayload{
-			OrderId: transaction.ID,
-			Amount:  transaction.TotalPrice,
-			FName:   user.Username,
-			Email:   user.Email,
+		if len(cartItems) == 0 {
+			return exceptions.NewCustomError(http.StatusBadRequest, "Cart is empty")
 		}
 
-		paymentLink, err := utils.CreatePayment(paymentPayload)
-		if err != nil {
-			return err
+		if len(checkoutReq.CartItemIDs) > 0 {
+			requested := map[uint]bool{}
+			for _, id := range checkoutReq.CartItemIDs {
+				requested[id] = true
+			}
+			if len(cartItems) != len(requested) {
+				return exceptions.NewCustomError(http.StatusNotFound, "Some cart items not found")
+			}
 		}
 
-		transaction.PaymentLink = paymentLink
-		if err := tx.Save(&transaction).Error; err != nil {
+		payloads := make([]request.TransactionCreate, 0, len(cartItems))
+		cartItemIDs := make([]uint, 0, len(cartItems))
+		for _, cartItem := range cartItems {
+			payloads = append(payloads, request.TransactionCreate{
+				VariantID: cartItem.BikeVariantID,
+				Quantity:  cartItem.Quantity,
+			})
+			cartItemIDs = append(cartItemIDs, cartItem.ID)
+		}
+
+		var err error
+		transaction, err = t.createTransaction(tx, logger, userID, payloads)
+		if err != nil {
 			return err
 		}
 
-		return nil
+		return tx.Where("id IN ?", cartItemIDs).Delete(&entity.CartItem{}).Error
 	})
 
 	if err != nil {
-		return response, err
+		return response.CreateTransactionResponse{}, err
 	}
 
-	return response, nil
+	logger.Info("success checking out cart", zap.Int("transactionID", transaction.ID), zap.Int("userID", userID))
+
+	return toCreateTransactionResponse(transaction), nil
 }
 
 func (t TransactionService) Update(c *gin.Context, payloads []request.TransactionUpdate, transactionID, userID int) error {
@@ -142,20 +211,26 @@ func (t TransactionService) Update(c *gin.Context, payloads []request.Transactio
 
 	err := db.Transaction(func(tx *gorm.DB) error {
 		var transaction entity.Transaction
-		if err := tx.Where("user_id = ?", userID).Where("id = ?", transactionID).First(&transaction).Error; err != nil {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("user_id = ?", userID).Where("id = ?", transactionID).First(&transaction).Error; err != nil {
 			return err
 		}
 
-		if transaction.Status != "pending" {
+		if transaction.Status != entity.TransactionStatusPending {
 			return exceptions.NewCustomError(http.StatusBadRequest, "Invalid transaction")
 		}
 
+		totalPrice := transaction.TotalPrice
 		for _, payload := range payloads {
 			if err := updateorder(tx, payload, &transaction); err != nil {
 				return err
 			}
 		}
 
+		// the gateway charge keeps the amount it was created with, notifications for it would no longer match
+		if transaction.PaymentLink != "" && transaction.TotalPrice != totalPrice {
+			return exceptions.NewCustomError(http.StatusConflict, "Transaction total can't change once its payment was created, delete it and check out again")
+		}
+
 		if err := tx.Save(&transaction).Error; err != nil {
 			return err
 		}
@@ -170,209 +245,1350 @@ func (t TransactionService) Update(c *gin.Context, payloads []request.Transactio
 	return nil
 }
 
+// Delete deletes a transaction of the user that was never paid, releasing the stock it reserved.
+// Paid transactions keep their refunds and invoice and can't be deleted.
 func (t TransactionService) Delete(c *gin.Context, transactionID, userID int) error {
-	db, _ := utils.GetDBAndLogger(c)
+	db, logger := utils.GetDBAndLogger(c)
 
-	var transaction entity.Transaction
-	if err := db.Where("user_id = ?", userID).Where("id = ?", transactionID).Delete(&transaction).Error; err != nil {
+	var pending bool
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var transaction entity.Transaction
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("user_id = ?", userID).Where("id = ?", transactionID).First(&transaction).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return nil
+			}
+			return err
+		}
+
+		switch transaction.Status {
+		case entity.TransactionStatusPending, entity.TransactionStatusCancelled, entity.TransactionStatusExpired:
+		default:
+			return exceptions.NewCustomError(http.StatusBadRequest, fmt.Sprintf("Transaction can't be deleted while %s", transaction.Status))
+		}
+		pending = transaction.Status == entity.TransactionStatusPending
+
+		actorID := uint(userID)
+		if err := releaseStockReservation(tx, &transaction, &actorID); err != nil {
+			return err
+		}
+
+		if err := tx.Delete(&transaction).Error; err != nil {
+			return err
+		}
+
+		return nil
+	})
+
+	if err != nil {
 		return err
 	}
 
+	// the charge of a deleted pending transaction must not be paid anymore
+	if pending {
+		if _, err := t.paymentGateway.Cancel(strconv.Itoa(transactionID)); err != nil {
+			logger.Warn("failed to cancel deleted transaction payment", zap.Int("transactionID", transactionID), zap.Error(err))
+		}
+	}
+
 	return nil
 }
 
-func (t TransactionService) Pay(c *gin.Context, transactionID, userID int) error {
+func (t TransactionService) Pay(c *gin.Context, transactionID int, adminID uint) error {
 	db, _ := utils.GetDBAndLogger(c)
 
 	err := db.Transaction(func(tx *gorm.DB) error {
 		var transaction entity.Transaction
-		if err := tx.Preload("Order").Where("user_id = ?", userID).Where("id = ?", transactionID).First(&transaction).Error; err != nil {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Preload("Order").Where("id = ?", transactionID).First(&transaction).Error; err != nil {
 			return err
 		}
 
-		if transaction.Status != "pending" {
-			return exceptions.NewCustomError(http.StatusBadRequest, "Invalid transaction")
-		}
+		return changeTransactionStatus(tx, &transaction, entity.TransactionStatusPaid, entity.TransactionActorAdmin, &adminID, "payment confirmed manually")
+	})
 
-		transaction.Status = "paid"
-		if err := tx.Save(&transaction).Error; err != nil {
-			return err
-		}
+	if err != nil {
+		return err
+	}
 
-		for _, order := range transaction.Order {
-			var bike entity.Bike
-			if err := tx.Where("id = ?", order.BikeID).First(&bike).Error; err != nil {
-				return err
-			}
+	return nil
+}
 
-			bike.Stock -= order.Quantity
-			if err := tx.Save(&bike).Error; err != nil {
-				return err
+func (t TransactionService) UpdateStatus(c *gin.Context, transactionID int, payload *request.TransactionStatusUpdate, adminID uint) error {
+	db, logger := utils.GetDBAndLogger(c)
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var transaction entity.Transaction
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", transactionID).First(&transaction).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return exceptions.NewCustomError(http.StatusNotFound, "Transaction not found")
 			}
+			return err
 		}
 
-		return nil
+		return changeTransactionStatus(tx, &transaction, payload.Status, entity.TransactionActorAdmin, &adminID, payload.Reason)
 	})
 
 	if err != nil {
 		return err
 	}
 
+	// the charge of a cancelled transaction must not be paid anymore
+	if payload.Status == entity.TransactionStatusCancelled {
+		if _, err := t.paymentGateway.Cancel(strconv.Itoa(transactionID)); err != nil {
+			logger.Error("failed to cancel cancelled transaction payment", zap.Int("transactionID", transactionID), zap.Error(err))
+		}
+	}
+
+	logger.Info("success updating transaction status", zap.Int("transactionID", transactionID), zap.String("status", payload.Status))
+
 	return nil
 }
 
-func (t TransactionService) GetTransactionByUserID(c *gin.Context, paginationReq *web.PaginationRequest, userID uint) ([]response.GetAllTransactionResponse, *web.Metadata, error) {
+// Refund refunds a paid transaction, entirely or only some of its orders, through the payment gateway
+// and records it in the refund ledger. The refund is stored as pending before calling the gateway so
+// concurrent refunds can never add up to more than what was paid.
+func (t TransactionService) Refund(c *gin.Context, transactionID int, refundReq *request.RefundCreate, adminID uint) (response.RefundResponse, error) {
 	db, logger := utils.GetDBAndLogger(c)
 
-	var transactions []entity.Transaction
+	var refund entity.Refund
+	var orders []entity.Order
 
-	var totalData int64
-	query := db.Model(&entity.Transaction{})
-	if err := query.Count(&totalData).Error; err != nil {
-		logger.Error("failed to count transactions", zap.Error(err))
-		return nil, nil, err
-	}
-	paginationReq.TotalData = totalData
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var transaction entity.Transaction
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Preload("Order").Where("id = ?", transactionID).First(&transaction).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return exceptions.NewCustomError(http.StatusNotFound, "Transaction not found")
+			}
+			return err
+		}
 
-	offset := paginationReq.GetOffset()
-	limit := paginationReq.GetLimit()
-	if err := query.Offset(offset).
-		Limit(limit).
-		Where("user_id = ?", userID).
-		Preload("User", func(db *gorm.DB) *gorm.DB { return db.Select("id, username") }).
-		Preload("Order.Bike").
-		Order("created_at desc").
-		Find(&transactions).Error; err != nil {
-		logger.Error("failed to fetch transactions", zap.Error(err))
-		return nil, nil, err
-	}
+		if !transaction.CanTransitionTo(entity.TransactionStatusRefunded) {
+			return exceptions.NewCustomError(http.StatusBadRequest, fmt.Sprintf("Transaction can't be refunded while %s", transaction.Status))
+		}
 
-	paginationReq.TotalPages = int((totalData + int64(limit) - 1) / int64(limit))
+		refunded, err := findRefundedOrders(tx, transaction.ID)
+		if err != nil {
+			return err
+		}
 
-	var results []response.GetAllTransactionResponse
-	for _, transaction := range transactions {
-		results = append(results, toGetAllResponse(transaction))
-	}
+		items, err := toRefundItems(transaction.Order, refundReq.Items, refunded)
+		if err != nil {
+			return err
+		}
 
-	metadata := &web.Metadata{
-		Page:       &paginationReq.Page,
-		Limit:      &paginationReq.Limit,
-		TotalPages: &paginationReq.TotalPages,
-		TotalData:  &paginationReq.TotalData,
-	}
+		refund = entity.Refund{
+			TransactionID: transaction.ID,
+			Reason:        refundReq.Reason,
+			Status:        entity.RefundStatusPending,
+			Restock:       refundReq.Restock,
+			ActorID:       adminID,
+			Items:         items,
+		}
+		for _, item := range items {
+			refund.Amount += item.Amount
+		}
 
-	logger.Info("success fetching all transactions", zap.Int("total_data", int(totalData)), zap.Int("total_pages", paginationReq.TotalPages))
+		if refund.Amount <= 0 {
+			return exceptions.NewCustomError(http.StatusBadRequest, "Refund amount must be greater than zero")
+		}
 
-	return results, metadata, nil
-}
+		orders = transaction.Order
 
-// helpers
-func toTransactionEntity(userId int, payloads []request.TransactionCreate) entity.Transaction {
-	transaction := entity.Transaction{
-		UserID: userId,
-		Status: "pending",
-	}
+		return tx.Create(&refund).Error
+	})
 
-	for _, payload := range payloads {
-		transaction.TotalPrice += payload.TotalPrice
+	if err != nil {
+		return response.RefundResponse{}, err
 	}
 
-	return transaction
-}
+	gatewayRefund, gatewayErr := t.paymentGateway.Refund(strconv.Itoa(transactionID), utils.PaymentRefundPayload{
+		RefundKey: fmt.Sprintf("refund-%d", refund.ID),
+		Amount:    refund.Amount,
+		Reason:    refund.Reason,
+	})
 
-func toOrderEntity(userId int, transactionId int, payload request.TransactionCreate) entity.Order {
-	order := entity.Order{
-		BikeID:        payload.BikeID,
-		Quantity:      payload.Quantity,
-		TotalPrice:    payload.TotalPrice,
-		UserID:        userId,
-		TransactionID: transactionId,
-	}
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if gatewayErr != nil {
+			refund.Status = entity.RefundStatusFailed
+			return tx.Model(&refund).Update("status", refund.Status).Error
+		}
 
-	return order
-}
+		refund.Status = entity.RefundStatusSucceeded
+		refund.GatewayReference = gatewayRefund.RefundKey
+		if err := tx.Model(&refund).Updates(map[string]any{
+			"status":            refund.Status,
+			"gateway_reference": refund.GatewayReference,
+		}).Error; err != nil {
+			return err
+		}
 
-func toResponse(payload entity.Transaction) response.TransactionResponse {
-	var orders []response.OrderResponse
+		if refund.Restock {
+			change := stockChange{Type: entity.StockMovementReturn, Reference: fmt.Sprintf("refund:%d", refund.ID), ActorID: &adminID}
+			if err := restockRefundItems(tx, orders, refund.Items, change); err != nil {
+				return err
+			}
+		}
 
-	for _, order := range payload.Order {
-		temp := response.OrderResponse{
-			ID:         order.ID,
-			BikeID:     order.BikeID,
-			Quantity:   order.Quantity,
-			TotalPrice: order.TotalPrice,
+		var transaction entity.Transaction
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", transactionID).First(&transaction).Error; err != nil {
+			return err
 		}
 
-		orders = append(orders, temp)
+		var refundedAmount int
+		if err := tx.Model(&entity.Refund{}).
+			Where("transaction_id = ? AND status = ?", transactionID, entity.RefundStatusSucceeded).
+			Select("COALESCE(SUM(amount), 0)").
+			Scan(&refundedAmount).Error; err != nil {
+			return err
+		}
+
+		status := entity.TransactionStatusPartiallyRefunded
+		if refundedAmount >= transaction.TotalPrice {
+			status = entity.TransactionStatusRefunded
+		}
+
+		// the gateway's refund notification may already have moved the transaction
+		if !transaction.CanTransitionTo(status) {
+			logger.Warn("skipping transaction status change after refund", zap.Int("transactionID", transactionID), zap.String("from", transaction.Status), zap.String("to", status))
+			return nil
+		}
+
+		return changeTransactionStatus(tx, &transaction, status, entity.TransactionActorAdmin, &adminID, refund.Reason)
+	})
+
+	if err != nil {
+		return response.RefundResponse{}, err
 	}
 
-	return response.TransactionResponse{
-		ID:          payload.ID,
-		TotalPrice:  payload.TotalPrice,
-		UserID:      payload.UserID,
-		Status:      payload.Status,
-		PaymentLink: payload.PaymentLink,
-		Orders:      orders,
-		CreatedAt:   payload.CreatedAt.Format("02-01-2006"),
-		UpdatedAt:   payload.UpdatedAt.Format("02-01-2006"),
+	if gatewayErr != nil {
+		logger.Error("failed to refund payment", zap.Int("transactionID", transactionID), zap.Uint("refundID", refund.ID), zap.Error(gatewayErr))
+		return response.RefundResponse{}, exceptions.NewCustomError(http.StatusBadGateway, "Failed to refund payment")
 	}
+
+	logger.Info("success refunding transaction", zap.Int("transactionID", transactionID), zap.Uint("refundID", refund.ID), zap.Int("amount", refund.Amount))
+
+	return toRefundResponse(refund), nil
 }
 
-func toGetAllResponse(payload entity.Transaction) response.GetAllTransactionResponse {
-	var orders []response.GetAllOrderResponse
+// GetInvoice renders the invoice of a paid transaction as a PDF and returns it with its file name.
+// Only the buyer or an admin can get it. Transactions paid before invoices existed get their
+// invoice issued on the first request.
+func (t TransactionService) GetInvoice(c *gin.Context, transactionID int, userID uint, isAdmin bool) ([]byte, string, error) {
+	db, logger := utils.GetDBAndLogger(c)
 
-	for _, order := range payload.Order {
-		temp := response.GetAllOrderResponse{
-			ID: order.ID,
-			Bike: response.GetAllOrderBikeResponse{
-				ID:       order.Bike.ID,
-				Name:     order.Bike.Name,
-				ImageUrl: order.Bike.ImageUrl,
-			},
-			Quantity:   order.Quantity,
-			TotalPrice: order.TotalPrice,
-			IsReviewed: order.IsReviewed,
+	var transaction entity.Transaction
+	var invoice entity.Invoice
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		query := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Preload("User").Where("id = ?", transactionID)
+		if !isAdmin {
+			query = query.Where("user_id = ?", userID)
 		}
 
-		orders = append(orders, temp)
+		if err := query.First(&transaction).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return exceptions.NewCustomError(http.StatusNotFound, "Transaction not found")
+			}
+			return err
+		}
+
+		if !transaction.IsPaid() {
+			return exceptions.NewCustomError(http.StatusBadRequest, "Invoice is only available for paid transactions")
+		}
+
+		err := tx.Where("transaction_id = ?", transaction.ID).First(&invoice).Error
+		if err != gorm.ErrRecordNotFound {
+			return err
+		}
+
+		paidAt := transaction.UpdatedAt
+		var history entity.TransactionStatusHistory
+		if err := tx.Where("transaction_id = ? AND to_status = ?", transaction.ID, entity.TransactionStatusPaid).Order("created_at asc").First(&history).Error; err == nil {
+			paidAt = history.CreatedAt
+		} else if err != gorm.ErrRecordNotFound {
+			return err
+		}
+
+		invoice, err = issueInvoice(tx, transaction.ID, paidAt)
+		return err
+	})
+
+	if err != nil {
+		return nil, "", err
 	}
 
-	return response.GetAllTransactionResponse{
-		ID:         payload.ID,
-		TotalPrice: payload.TotalPrice,
-		User: response.GetAllTransactionUserResponse{
-			ID:       payload.User.ID,
-			Username: payload.User.Username,
-		},
-		Status:    payload.Status,
-		Orders:    orders,
-		CreatedAt: payload.CreatedAt,
-		UpdatedAt: payload.UpdatedAt,
+	orders, err := findTransactionOrders(db.Order("id"), transaction.ID)
+	if err != nil {
+		return nil, "", err
 	}
-}
 
-// ex-concurrent
-func createOrder(tx *gorm.DB, userId, transactionId int, payload request.TransactionCreate) error {
-	order := toOrderEntity(userId, transactionId, payload)
-	if err := tx.Create(&order).Error; err != nil {
-		return err
+	var refunded int
+	if err := db.Model(&entity.Refund{}).
+		Where("transaction_id = ? AND status = ?", transaction.ID, entity.RefundStatusSucceeded).
+		Select("COALESCE(SUM(amount), 0)").
+		Scan(&refunded).Error; err != nil {
+		return nil, "", err
 	}
 
-	return nil
+	var profile entity.Profile
+	if err := db.Where("user_id = ?", transaction.UserID).Select("name").Take(&profile).Error; err != nil && err != gorm.ErrRecordNotFound {
+		return nil, "", err
+	}
+
+	var file bytes.Buffer
+	if err := utils.RenderInvoicePDF(&file, t.toInvoice(transaction, invoice, orders, profile, refunded)); err != nil {
+		logger.Error("failed to render invoice", zap.Int("transactionID", transaction.ID), zap.Error(err))
+		return nil, "", err
+	}
+
+	return file.Bytes(), invoice.Number + ".pdf", nil
 }
 
-func updateorder(tx *gorm.DB, payload request.TransactionUpdate, transaction *entity.Transaction) error {
-	var order entity.Order
-	if err := tx.Where("id = ?", payload.ID).First(&order).Error; err != nil {
-		return err
+func (t TransactionService) HandleMidtransNotification(c *gin.Context, payload *request.MidtransNotificationRequest) error {
+	db, logger := utils.GetDBAndLogger(c)
+
+	if !t.paymentGateway.VerifySignature(payload.OrderID, payload.StatusCode, payload.GrossAmount, payload.SignatureKey) {
+		return exceptions.NewCustomError(http.StatusForbidden, "Invalid signature key")
 	}
 
-	transaction.TotalPrice -= order.TotalPrice
-	transaction.TotalPrice += payload.TotalPrice
+	transactionID, err := strconv.Atoi(payload.OrderID)
+	if err != nil {
+		return exceptions.NewCustomError(http.StatusBadRequest, "Invalid order id")
+	}
 
-	order.BikeID = payload.BikeID
-	order.Quantity = payload.Quantity
-	order.TotalPrice = payload.TotalPrice
+	grossAmount, err := strconv.ParseFloat(payload.GrossAmount, 64)
+	if err != nil {
+		return exceptions.NewCustomError(http.StatusBadRequest, "Invalid gross amount")
+	}
+
+	status, ok := toTransactionStatus(payload.TransactionStatus, payload.FraudStatus)
+	if !ok {
+		logger.Warn("ignoring midtrans notification with unhandled status", zap.Int("transactionID", transactionID), zap.String("transactionStatus", payload.TransactionStatus), zap.String("fraudStatus", payload.FraudStatus))
+		return nil
+	}
+
+	var latePayment *entity.Refund
+	err = db.Transaction(func(tx *gorm.DB) error {
+		var transaction entity.Transaction
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Preload("Order").Where("id = ?", transactionID).First(&transaction).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return exceptions.NewCustomError(http.StatusNotFound, "Transaction not found")
+			}
+			return err
+		}
+
+		if int(grossAmount) != transaction.TotalPrice {
+			return exceptions.NewCustomError(http.StatusBadRequest, "Gross amount does not match transaction total price")
+		}
+
+		if transaction.Status == status {
+			logger.Info("duplicate midtrans notification", zap.Int("transactionID", transactionID), zap.String("status", status))
+			return nil
+		}
+
+		if status == entity.TransactionStatusPaid && (transaction.Status == entity.TransactionStatusExpired || transaction.Status == entity.TransactionStatusCancelled) {
+			latePayment, err = recordLatePayment(tx, transaction)
+			return err
+		}
+
+		if !transaction.CanTransitionTo(status) {
+			logger.Warn("ignoring out of order midtrans notification", zap.Int("transactionID", transactionID), zap.String("from", transaction.Status), zap.String("to", status))
+			return nil
+		}
+
+		return changeTransactionStatus(tx, &transaction, status, entity.TransactionActorGateway, nil, "midtrans "+payload.TransactionStatus)
+	})
+
+	if err != nil {
+		return err
+	}
+
+	if latePayment != nil {
+		logger.Error("midtrans payment arrived for a dead transaction, refunding it", zap.Int("transactionID", transactionID), zap.Uint("refundID", latePayment.ID), zap.Int("amount", latePayment.Amount))
+		t.refundLatePayment(db, logger, latePayment)
+	}
+
+	logger.Info("success handling midtrans notification", zap.Int("transactionID", transactionID), zap.String("transactionStatus", payload.TransactionStatus))
+
+	return nil
+}
+
+// recordLatePayment books a system refund for a payment that settled after its transaction was
+// expired or cancelled. It returns nil when the payment was already booked by an earlier notification.
+func recordLatePayment(tx *gorm.DB, transaction entity.Transaction) (*entity.Refund, error) {
+	var booked int64
+	if err := tx.Model(&entity.Refund{}).
+		Where("transaction_id = ? AND actor_id = ?", transaction.ID, entity.RefundActorSystem).
+		Count(&booked).Error; err != nil {
+		return nil, err
+	}
+	if booked > 0 {
+		return nil, nil
+	}
+
+	refund := entity.Refund{
+		TransactionID: transaction.ID,
+		Amount:        transaction.TotalPrice,
+		Reason:        fmt.Sprintf("payment arrived after the transaction was %s", transaction.Status),
+		Status:        entity.RefundStatusPending,
+		ActorID:       entity.RefundActorSystem,
+	}
+	if err := tx.Create(&refund).Error; err != nil {
+		return nil, err
+	}
+
+	return &refund, nil
+}
+
+// refundLatePayment sends a booked late payment back through the gateway. When the gateway
+// refuses, the refund is left for an admin to settle by hand.
+func (t TransactionService) refundLatePayment(db *gorm.DB, logger *zap.Logger, refund *entity.Refund) {
+	gatewayRefund, err := t.paymentGateway.Refund(strconv.Itoa(refund.TransactionID), utils.PaymentRefundPayload{
+		RefundKey: fmt.Sprintf("refund-%d", refund.ID),
+		Amount:    refund.Amount,
+		Reason:    refund.Reason,
+	})
+	if err != nil {
+		logger.Error("failed to refund late payment, it must be refunded manually", zap.Int("transactionID", refund.TransactionID), zap.Uint("refundID", refund.ID), zap.Error(err))
+		if err := db.Model(refund).Update("status", entity.RefundStatusManual).Error; err != nil {
+			logger.Error("failed to mark late payment for manual refund", zap.Uint("refundID", refund.ID), zap.Error(err))
+		}
+		return
+	}
+
+	if err := db.Model(refund).Updates(map[string]any{
+		"status":            entity.RefundStatusSucceeded,
+		"gateway_reference": gatewayRefund.RefundKey,
+	}).Error; err != nil {
+		logger.Error("failed to mark late payment refunded", zap.Uint("refundID", refund.ID), zap.Error(err))
+	}
+}
+
+// ExpireOverdue moves at most limit pending transactions whose payment window ended before now to expired,
+// optionally putting their orders back into the buyer's cart. Rows are claimed with FOR UPDATE SKIP LOCKED
+// so several instances can run it at the same time without expiring a transaction twice.
+func (t TransactionService) ExpireOverdue(db *gorm.DB, logger *zap.Logger, now time.Time, limit int) (int, error) {
+	var expiredIDs []int
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var transactions []entity.Transaction
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Preload("Order").
+			Where("status = ?", entity.TransactionStatusPending).
+			Where("expires_at <= ?", now).
+			Order("expires_at asc").
+			Limit(limit).
+			Find(&transactions).Error; err != nil {
+			return err
+		}
+
+		for i := range transactions {
+			transaction := &transactions[i]
+
+			if err := changeTransactionStatus(tx, transaction, entity.TransactionStatusExpired, entity.TransactionActorSystem, nil, "payment window elapsed"); err != nil {
+				return err
+			}
+
+			if t.restoreCartOnExpiry {
+				if err := restoreCartItems(tx, transaction.UserID, transaction.Order); err != nil {
+					return err
+				}
+			}
+
+			expiredIDs = append(expiredIDs, transaction.ID)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		logger.Error("failed to expire overdue transactions", zap.Error(err))
+		return 0, err
+	}
+
+	// the gateway expires the charge on its own as well, cancelling here only closes the gap
+	for _, id := range expiredIDs {
+		if _, err := t.paymentGateway.Cancel(strconv.Itoa(id)); err != nil {
+			logger.Warn("failed to cancel expired transaction payment", zap.Int("transactionID", id), zap.Error(err))
+		}
+	}
+
+	if len(expiredIDs) > 0 {
+		logger.Info("success expiring overdue transactions", zap.Ints("transactionIDs", expiredIDs))
+	}
+
+	return len(expiredIDs), nil
+}
+
+func (t TransactionService) GetTransactionByUserID(c *gin.Context, paginationReq *web.PaginationRequest, userID uint) ([]response.GetAllTransactionResponse, *web.Metadata, error) {
+	db, logger := utils.GetDBAndLogger(c)
+
+	var transactions []entity.Transaction
+
+	var totalData int64
+	query := db.Model(&entity.Transaction{})
+	if err := query.Count(&totalData).Error; err != nil {
+		logger.Error("failed to count transactions", zap.Error(err))
+		return nil, nil, err
+	}
+	paginationReq.TotalData = totalData
+
+	offset := paginationReq.GetOffset()
+	limit := paginationReq.GetLimit()
+	if err := query.Offset(offset).
+		Limit(limit).
+		Where("user_id = ?", userID).
+		Preload("User", func(db *gorm.DB) *gorm.DB { return db.Select("id, username") }).
+		Preload("Order.Bike", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+		Order("created_at desc").
+		Find(&transactions).Error; err != nil {
+		logger.Error("failed to fetch transactions", zap.Error(err))
+		return nil, nil, err
+	}
+
+	paginationReq.TotalPages = int((totalData + int64(limit) - 1) / int64(limit))
+
+	var results []response.GetAllTransactionResponse
+	for _, transaction := range transactions {
+		results = append(results, toGetAllResponse(transaction))
+	}
+
+	metadata := &web.Metadata{
+		Page:       &paginationReq.Page,
+		Limit:      &paginationReq.Limit,
+		TotalPages: &paginationReq.TotalPages,
+		TotalData:  &paginationReq.TotalData,
+	}
+
+	logger.Info("success fetching all transactions", zap.Int("total_data", int(totalData)), zap.Int("total_pages", paginationReq.TotalPages))
+
+	return results, metadata, nil
+}
+
+// createTransaction prices the payloads from the bikes, reserves their stock, stores the transaction
+// with its orders and creates the payment charge. It must run inside a database transaction.
+func (t TransactionService) createTransaction(tx *gorm.DB, logger *zap.Logger, userID int, payloads []request.TransactionCreate) (entity.Transaction, error) {
+	variantIDs := make([]uint, 0, len(payloads))
+	quantities := map[uint]int{}
+	for _, payload := range payloads {
+		// carts stored before their quantities were validated may still hold lines that aren't positive
+		if payload.Quantity <= 0 {
+			return entity.Transaction{}, exceptions.NewCustomError(http.StatusBadRequest, fmt.Sprintf("Quantity of bike variant %d must be greater than zero", payload.VariantID))
+		}
+
+		variantIDs = append(variantIDs, payload.VariantID)
+		quantities[payload.VariantID] += payload.Quantity
+	}
+
+	variants, err := findOrderVariants(tx, variantIDs)
+	if err != nil {
+		return entity.Transaction{}, err
+	}
+
+	transaction := toTransactionEntity(userID, payloads, variants)
+
+	expiresAt := time.Now().Add(t.paymentWindow)
+	transaction.ExpiresAt = &expiresAt
+	transaction.StockReservation = entity.StockReservationReserved
+
+	if err := tx.Create(&transaction).Error; err != nil {
+		return entity.Transaction{}, err
+	}
+
+	actorID := uint(userID)
+	if err := reserveVariantStock(tx, quantities, transactionStockChange(entity.StockMovementReservation, transaction.ID, &actorID)); err != nil {
+		return entity.Transaction{}, err
+	}
+
+	if err := recordTransactionStatus(tx, transaction.ID, "", entity.TransactionStatusPending, entity.TransactionActorUser, &actorID, "transaction created"); err != nil {
+		return entity.Transaction{}, err
+	}
+
+	for _, payload := range payloads {
+		if err := createOrder(tx, userID, transaction.ID, payload, variants[payload.VariantID]); err != nil {
+			return entity.Transaction{}, err
+		}
+	}
+
+	var user entity.User
+	if err := tx.Where("id = ?", userID).First(&user).Error; err != nil {
+		return entity.Transaction{}, err
+	}
+
+	paymentPayload := utils.PaymentPayload{
+		OrderId: transaction.ID,
+		Amount:  transaction.TotalPrice,
+		FName:   user.Username,
+		Email:   user.Email,
+		Expiry:  t.paymentWindow,
+	}
+
+	charge, err := t.paymentGateway.CreateCharge(paymentPayload)
+	if err != nil {
+		logger.Error("failed to create payment charge", zap.Int("transactionID", transaction.ID), zap.Error(err))
+		return entity.Transaction{}, exceptions.NewCustomError(http.StatusBadGateway, "Failed to create payment")
+	}
+
+	transaction.PaymentLink = charge.RedirectURL
+	if err := tx.Model(&transaction).Update("payment_link", transaction.PaymentLink).Error; err != nil {
+		return entity.Transaction{}, err
+	}
+
+	return transaction, nil
+}
+
+// helpers
+func toTransactionEntity(userId int, payloads []request.TransactionCreate, variants map[uint]entity.BikeVariant) entity.Transaction {
+	transaction := entity.Transaction{
+		UserID: userId,
+		Status: entity.TransactionStatusPending,
+	}
+
+	for _, payload := range payloads {
+		variant := variants[payload.VariantID]
+		transaction.TotalPrice += variant.EffectivePrice() * payload.Quantity
+	}
+
+	return transaction
+}
+
+func toOrderEntity(userId int, transactionId int, payload request.TransactionCreate, variant entity.BikeVariant) entity.Order {
+	order := entity.Order{
+		Quantity:      payload.Quantity,
+		UserID:        userId,
+		TransactionID: transactionId,
+	}
+	setOrderVariant(&order, variant)
+
+	return order
+}
+
+// setOrderVariant snapshots the variant's current price, discount included, SKU and attributes and its bike's name
+// and brand onto the order, so the order keeps what was actually bought even if the bike is edited later.
+func setOrderVariant(order *entity.Order, variant entity.BikeVariant) {
+	order.BikeID = int(variant.BikeID)
+	order.BikeVariantID = variant.ID
+	order.UnitPrice = variant.EffectivePrice()
+	order.DiscountID = discountIDOf(variant.Discount)
+	order.BikeName = variant.Bike.Name
+	order.BikeBrand = variant.Bike.Brand
+	order.SKU = variant.SKU
+	order.VariantName = variant.Label()
+	order.TotalPrice = order.UnitPrice * order.Quantity
+}
+
+// findOrderVariants loads the bike variants being ordered, with their bikes, keyed by id,
+// failing if any of them doesn't exist or belongs to an archived bike.
+func findOrderVariants(tx *gorm.DB, variantIDs []uint) (map[uint]entity.BikeVariant, error) {
+	var variants []entity.BikeVariant
+	if err := tx.Preload("Bike", func(db *gorm.DB) *gorm.DB { return db.Select("id, name, brand, price, category_id, brand_id") }).
+		Where("id IN ?", variantIDs).
+		Find(&variants).Error; err != nil {
+		return nil, err
+	}
+
+	pricer, err := newBikePricer(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[uint]entity.BikeVariant, len(variants))
+	for _, variant := range variants {
+		// the bike isn't loaded when it is archived, and archived bikes can't be ordered
+		if variant.Bike.ID == 0 {
+			continue
+		}
+		pricer.applyToVariant(&variant)
+		result[variant.ID] = variant
+	}
+
+	for _, variantID := range variantIDs {
+		if _, ok := result[variantID]; !ok {
+			return nil, exceptions.NewCustomError(http.StatusNotFound, fmt.Sprintf("Bike variant %d not found", variantID))
+		}
+	}
+
+	return result, nil
+}
+
+func toCreateTransactionResponse(transaction entity.Transaction) response.CreateTransactionResponse {
+	return response.CreateTransactionResponse{
+		TransactionID: transaction.ID,
+		TotalPrice:    transaction.TotalPrice,
+		PaymentLink:   transaction.PaymentLink,
+		ExpiresAt:     transaction.ExpiresAt,
+	}
+}
+
+func toRefundResponse(refund entity.Refund) response.RefundResponse {
+	var items []response.RefundItemResponse
+	for _, item := range refund.Items {
+		items = append(items, response.RefundItemResponse{
+			OrderID:  item.OrderID,
+			Quantity: item.Quantity,
+			Amount:   item.Amount,
+		})
+	}
+
+	return response.RefundResponse{
+		ID:               refund.ID,
+		Amount:           refund.Amount,
+		Reason:           refund.Reason,
+		Status:           refund.Status,
+		GatewayReference: refund.GatewayReference,
+		Restock:          refund.Restock,
+		Items:            items,
+		CreatedAt:        refund.CreatedAt,
+	}
+}
+
+func (t TransactionService) toInvoice(transaction entity.Transaction, invoice entity.Invoice, orders []entity.Order, profile entity.Profile, refunded int) utils.Invoice {
+	buyerName := profile.Name
+	if buyerName == "" {
+		buyerName = transaction.User.Username
+	}
+
+	paymentStatus := "Paid"
+	switch transaction.Status {
+	case entity.TransactionStatusRefunded:
+		paymentStatus = "Refunded"
+	case entity.TransactionStatusPartiallyRefunded:
+		paymentStatus = "Partially refunded"
+	}
+
+	var lines []utils.InvoiceLine
+	for _, order := range orders {
+		description := order.BikeName
+		if order.BikeBrand != "" {
+			description = order.BikeBrand + " " + order.BikeName
+		}
+		if order.VariantName != "" {
+			description += " (" + order.VariantName + ")"
+		}
+
+		lines = append(lines, utils.InvoiceLine{
+			Description: description,
+			Quantity:    order.Quantity,
+			UnitPrice:   order.UnitPrice,
+			Total:       order.TotalPrice,
+		})
+	}
+
+	return utils.Invoice{
+		Issuer:        t.invoiceIssuer,
+		Number:        invoice.Number,
+		TransactionID: transaction.ID,
+		IssuedAt:      invoice.IssuedAt,
+		BuyerName:     buyerName,
+		BuyerEmail:    transaction.User.Email,
+		Lines:         lines,
+		Total:         transaction.TotalPrice,
+		Refunded:      refunded,
+		PaymentStatus: paymentStatus,
+		PaidAt:        invoice.IssuedAt,
+	}
+}
+
+func toResponse(payload entity.Transaction) response.TransactionResponse {
+	var orders []response.OrderResponse
+	var statusHistories []response.TransactionStatusHistoryResponse
+	var refunds []response.RefundResponse
+
+	for _, refund := range payload.Refunds {
+		refunds = append(refunds, toRefundResponse(refund))
+	}
+
+	for _, order := range payload.Order {
+		temp := response.OrderResponse{
+			ID:          order.ID,
+			BikeID:      order.BikeID,
+			VariantID:   order.BikeVariantID,
+			BikeName:    order.BikeName,
+			BikeBrand:   order.BikeBrand,
+			SKU:         order.SKU,
+			VariantName: order.VariantName,
+			Quantity:    order.Quantity,
+			UnitPrice:   order.UnitPrice,
+			TotalPrice:  order.TotalPrice,
+		}
+
+		orders = append(orders, temp)
+	}
+
+	for _, history := range payload.StatusHistory {
+		statusHistories = append(statusHistories, response.TransactionStatusHistoryResponse{
+			FromStatus: history.FromStatus,
+			ToStatus:   history.ToStatus,
+			ActorType:  history.ActorType,
+			ActorID:    history.ActorID,
+			Reason:     history.Reason,
+			CreatedAt:  history.CreatedAt,
+		})
+	}
+
+	return response.TransactionResponse{
+		ID:              payload.ID,
+		TotalPrice:      payload.TotalPrice,
+		UserID:          payload.UserID,
+		Status:          payload.Status,
+		PaymentLink:     payload.PaymentLink,
+		ExpiresAt:       payload.ExpiresAt,
+		Orders:          orders,
+		StatusHistories: statusHistories,
+		Refunds:         refunds,
+		CreatedAt:       payload.CreatedAt.Format("02-01-2006"),
+		UpdatedAt:       payload.UpdatedAt.Format("02-01-2006"),
+	}
+}
+
+func toGetAllResponse(payload entity.Transaction) response.GetAllTransactionResponse {
+	var orders []response.GetAllOrderResponse
+
+	for _, order := range payload.Order {
+		temp := response.GetAllOrderResponse{
+			ID: order.ID,
+			Bike: response.GetAllOrderBikeResponse{
+				ID:       order.Bike.ID,
+				Name:     order.BikeName,
+				Brand:    order.BikeBrand,
+				ImageUrl: order.Bike.ImageUrl,
+			},
+			Variant: response.GetAllOrderVariantResponse{
+				ID:   order.BikeVariantID,
+				SKU:  order.SKU,
+				Name: order.VariantName,
+			},
+			Quantity:   order.Quantity,
+			UnitPrice:  order.UnitPrice,
+			TotalPrice: order.TotalPrice,
+			IsReviewed: order.IsReviewed,
+		}
+
+		orders = append(orders, temp)
+	}
+
+	return response.GetAllTransactionResponse{
+		ID:         payload.ID,
+		TotalPrice: payload.TotalPrice,
+		User: response.GetAllTransactionUserResponse{
+			ID:       payload.User.ID,
+			Username: payload.User.Username,
+		},
+		Status:    payload.Status,
+		ExpiresAt: payload.ExpiresAt,
+		Orders:    orders,
+		CreatedAt: payload.CreatedAt,
+		UpdatedAt: payload.UpdatedAt,
+	}
+}
+
+// toTransactionStatus maps a Midtrans transaction_status/fraud_status pair onto our transaction status.
+// See https://docs.midtrans.com/docs/https-notification-webhooks#status-definition
+func toTransactionStatus(transactionStatus, fraudStatus string) (string, bool) {
+	switch transactionStatus {
+	case "capture":
+		switch fraudStatus {
+		case "", "accept":
+			return entity.TransactionStatusPaid, true
+		case "challenge":
+			return entity.TransactionStatusPending, true
+		default:
+			return entity.TransactionStatusCancelled, true
+		}
+	case "settlement":
+		return entity.TransactionStatusPaid, true
+	case "pending":
+		return entity.TransactionStatusPending, true
+	case "deny", "cancel":
+		return entity.TransactionStatusCancelled, true
+	case "expire":
+		return entity.TransactionStatusExpired, true
+	case "refund":
+		return entity.TransactionStatusRefunded, true
+	case "partial_refund":
+		return entity.TransactionStatusPartiallyRefunded, true
+	}
+
+	return "", false
+}
+
+// changeTransactionStatus is the only place a transaction's status should be changed,
+// it rejects transitions the lifecycle doesn't allow, commits or releases the reserved stock
+// and records the change in the status history.
+func changeTransactionStatus(tx *gorm.DB, transaction *entity.Transaction, status, actorType string, actorID *uint, reason string) error {
+	if !transaction.CanTransitionTo(status) {
+		return exceptions.NewCustomError(http.StatusBadRequest, fmt.Sprintf("Cannot change transaction status from %s to %s", transaction.Status, status))
+	}
+
+	from := transaction.Status
+	transaction.Status = status
+
+	switch {
+	case status != entity.TransactionStatusPartiallyRefunded:
+		transaction.FulfilmentStatus = ""
+	case from != entity.TransactionStatusPartiallyRefunded:
+		transaction.FulfilmentStatus = from
+	}
+
+	if err := tx.Model(transaction).Updates(map[string]any{
+		"status":            transaction.Status,
+		"fulfilment_status": transaction.FulfilmentStatus,
+	}).Error; err != nil {
+		return err
+	}
+
+	switch status {
+	case entity.TransactionStatusPaid:
+		if err := commitStockReservation(tx, transaction, actorID); err != nil {
+			return err
+		}
+		if _, err := issueInvoice(tx, transaction.ID, time.Now()); err != nil {
+			return err
+		}
+	case entity.TransactionStatusCancelled, entity.TransactionStatusExpired:
+		if err := releaseStockReservation(tx, transaction, actorID); err != nil {
+			return err
+		}
+	}
+
+	return recordTransactionStatus(tx, transaction.ID, from, status, actorType, actorID, reason)
+}
+
+// issueInvoice gives a paid transaction the next invoice number. The counter stays locked until tx ends,
+// so a rolled back transaction gives its number back instead of leaving a gap.
+func issueInvoice(tx *gorm.DB, transactionID int, issuedAt time.Time) (entity.Invoice, error) {
+	var counter entity.InvoiceCounter
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Take(&counter, entity.InvoiceCounterID).Error; err != nil {
+		return entity.Invoice{}, err
+	}
+
+	counter.LastNumber++
+	if err := tx.Model(&counter).Update("last_number", counter.LastNumber).Error; err != nil {
+		return entity.Invoice{}, err
+	}
+
+	invoice := entity.Invoice{
+		TransactionID: transactionID,
+		Number:        fmt.Sprintf("INV-%d-%06d", issuedAt.Year(), counter.LastNumber),
+		IssuedAt:      issuedAt,
+	}
+
+	if err := tx.Create(&invoice).Error; err != nil {
+		return entity.Invoice{}, err
+	}
+
+	return invoice, nil
+}
+
+func recordTransactionStatus(tx *gorm.DB, transactionID int, from, to, actorType string, actorID *uint, reason string) error {
+	history := entity.TransactionStatusHistory{
+		TransactionID: transactionID,
+		FromStatus:    from,
+		ToStatus:      to,
+		ActorType:     actorType,
+		ActorID:       actorID,
+		Reason:        reason,
+	}
+
+	return tx.Create(&history).Error
+}
+
+// ex-concurrent
+func createOrder(tx *gorm.DB, userId, transactionId int, payload request.TransactionCreate, variant entity.BikeVariant) error {
+	order := toOrderEntity(userId, transactionId, payload, variant)
+	if err := tx.Create(&order).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// reserveVariantStock takes the requested quantity of every bike variant out of its stock. Each variant is
+// decremented with a conditional UPDATE so concurrent checkouts can't oversell, variants are visited in id order
+// to avoid deadlocks, and every variant short on stock is reported together in a single 409.
+func reserveVariantStock(tx *gorm.DB, quantities map[uint]int, change stockChange) error {
+	variantIDs := make([]uint, 0, len(quantities))
+	for variantID := range quantities {
+		variantIDs = append(variantIDs, variantID)
+	}
+	sort.Slice(variantIDs, func(i, j int) bool { return variantIDs[i] < variantIDs[j] })
+
+	var shortages []response.InsufficientStockResponse
+	for _, variantID := range variantIDs {
+		quantity := quantities[variantID]
+
+		var reserved entity.BikeVariant
+		result := tx.Model(&reserved).
+			Clauses(clause.Returning{Columns: []clause.Column{{Name: "bike_id"}}}).
+			Where("id = ? AND is_available = ? AND stock >= ?", variantID, true, quantity).
+			Update("stock", gorm.Expr("stock - ?", quantity))
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			shortage := response.InsufficientStockResponse{VariantID: variantID, Requested: quantity}
+
+			var variant entity.BikeVariant
+			if err := tx.Preload("Bike", func(db *gorm.DB) *gorm.DB { return db.Unscoped().Select("id, name") }).
+				Select("id, bike_id, sku, stock, is_available").
+				Where("id = ?", variantID).
+				Take(&variant).Error; err != nil && err != gorm.ErrRecordNotFound {
+				return err
+			}
+			shortage.BikeID = int(variant.BikeID)
+			shortage.SKU = variant.SKU
+			shortage.Name = variant.Bike.Name
+			if variant.IsAvailable {
+				shortage.Available = variant.Stock
+			}
+
+			shortages = append(shortages, shortage)
+			continue
+		}
+
+		if err := recordStockMovement(tx, reserved.BikeID, variantID, -quantity, change); err != nil {
+			return err
+		}
+
+		if err := checkStockLevel(tx, variantID, -quantity); err != nil {
+			return err
+		}
+	}
+
+	if len(shortages) > 0 {
+		return exceptions.NewDetailedError(http.StatusConflict, "Some bikes don't have enough stock", shortages)
+	}
+
+	return syncVariantBikeStock(tx, variantIDs)
+}
+
+// commitStockReservation turns the stock reserved for a paid transaction into a sale. The stock already
+// left at checkout, so the ledger gets the reservation back and the sale takes it again.
+func commitStockReservation(tx *gorm.DB, transaction *entity.Transaction, actorID *uint) error {
+	if transaction.StockReservation != entity.StockReservationReserved && transaction.StockReservation != "" {
+		return nil
+	}
+
+	orders, err := findTransactionOrders(tx, transaction.ID)
+	if err != nil {
+		return err
+	}
+
+	sale := transactionStockChange(entity.StockMovementSale, transaction.ID, actorID)
+	if transaction.StockReservation == entity.StockReservationReserved {
+		release := transactionStockChange(entity.StockMovementReservation, transaction.ID, actorID)
+		for _, order := range orders {
+			if err := recordStockMovement(tx, uint(order.BikeID), order.BikeVariantID, order.Quantity, release); err != nil {
+				return err
+			}
+			if err := recordStockMovement(tx, uint(order.BikeID), order.BikeVariantID, -order.Quantity, sale); err != nil {
+				return err
+			}
+		}
+	} else {
+		// created before stock was reserved at checkout, take the stock now
+		variantIDs := make([]uint, 0, len(orders))
+		for _, order := range orders {
+			if err := takeSoldStock(tx, order, sale); err != nil {
+				return err
+			}
+			variantIDs = append(variantIDs, order.BikeVariantID)
+		}
+
+		if err := syncVariantBikeStock(tx, variantIDs); err != nil {
+			return err
+		}
+	}
+
+	transaction.StockReservation = entity.StockReservationCommitted
+	return tx.Model(transaction).Update("stock_reservation", transaction.StockReservation).Error
+}
+
+// takeSoldStock takes the stock of an order paid without a reservation. The payment can't be turned
+// down anymore, so when the stock ran short it takes what is left and notes the oversell on the sale
+// instead of letting the stock go negative.
+func takeSoldStock(tx *gorm.DB, order entity.Order, sale stockChange) error {
+	result := tx.Model(&entity.BikeVariant{}).
+		Where("id = ? AND stock >= ?", order.BikeVariantID, order.Quantity).
+		Update("stock", gorm.Expr("stock - ?", order.Quantity))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected > 0 {
+		if err := recordStockMovement(tx, uint(order.BikeID), order.BikeVariantID, -order.Quantity, sale); err != nil {
+			return err
+		}
+		return checkStockLevel(tx, order.BikeVariantID, -order.Quantity)
+	}
+
+	var variant entity.BikeVariant
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id, stock").Take(&variant, order.BikeVariantID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			// the variant was deleted, only the ledger keeps the sale
+			return recordStockMovement(tx, uint(order.BikeID), order.BikeVariantID, -order.Quantity, sale)
+		}
+		return err
+	}
+
+	available := max(variant.Stock, 0)
+	if available > 0 {
+		if err := tx.Model(&variant).Update("stock", gorm.Expr("stock - ?", available)).Error; err != nil {
+			return err
+		}
+	}
+
+	// recorded even when nothing was left, so the oversell shows up in the ledger
+	if err := tx.Create(&entity.StockMovement{
+		BikeID:        uint(order.BikeID),
+		BikeVariantID: order.BikeVariantID,
+		Type:          sale.Type,
+		Quantity:      -available,
+		Reference:     sale.Reference,
+		Note:          fmt.Sprintf("oversold by %d, only %d in stock", order.Quantity-available, available),
+		ActorID:       sale.ActorID,
+	}).Error; err != nil {
+		return err
+	}
+
+	return checkStockLevel(tx, order.BikeVariantID, -available)
+}
+
+func releaseStockReservation(tx *gorm.DB, transaction *entity.Transaction, actorID *uint) error {
+	if transaction.StockReservation != entity.StockReservationReserved {
+		return nil
+	}
+
+	orders, err := findTransactionOrders(tx, transaction.ID)
+	if err != nil {
+		return err
+	}
+
+	change := transactionStockChange(entity.StockMovementReservation, transaction.ID, actorID)
+	change.Note = "reservation released"
+
+	variantIDs := make([]uint, 0, len(orders))
+	for _, order := range orders {
+		if err := moveVariantStock(tx, uint(order.BikeID), order.BikeVariantID, order.Quantity, change); err != nil {
+			return err
+		}
+		variantIDs = append(variantIDs, order.BikeVariantID)
+	}
+
+	if err := syncVariantBikeStock(tx, variantIDs); err != nil {
+		return err
+	}
+
+	transaction.StockReservation = entity.StockReservationReleased
+	return tx.Model(transaction).Update("stock_reservation", transaction.StockReservation).Error
+}
+
+func findTransactionOrders(tx *gorm.DB, transactionID int) ([]entity.Order, error) {
+	var orders []entity.Order
+	if err := tx.Where("transaction_id = ?", transactionID).Find(&orders).Error; err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+
+// restoreCartItems puts the ordered bikes back into the user's cart, adding to any quantity already there.
+// Variants deleted since the order was placed are left out.
+func restoreCartItems(tx *gorm.DB, userID int, orders []entity.Order) error {
+	var cart entity.Cart
+	if err := tx.Where("user_id = ?", userID).Select("id").First(&cart).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil
+		}
+		return err
+	}
+
+	variantIDs := make([]uint, 0, len(orders))
+	for _, order := range orders {
+		variantIDs = append(variantIDs, order.BikeVariantID)
+	}
+
+	var existingIDs []uint
+	if err := tx.Model(&entity.BikeVariant{}).Where("id IN ?", variantIDs).Pluck("id", &existingIDs).Error; err != nil {
+		return err
+	}
+
+	existing := make(map[uint]bool, len(existingIDs))
+	for _, id := range existingIDs {
+		existing[id] = true
+	}
+
+	for _, order := range orders {
+		if !existing[order.BikeVariantID] {
+			continue
+		}
+
+		cartItem := entity.CartItem{
+			CartID:        cart.ID,
+			BikeID:        uint(order.BikeID),
+			BikeVariantID: order.BikeVariantID,
+			Quantity:      order.Quantity,
+		}
+
+		if err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "bike_variant_id"}, {Name: "cart_id"}},
+			DoUpdates: clause.Assignments(map[string]any{"quantity": gorm.Expr("cart_items.quantity + excluded.quantity")}),
+		}).Create(&cartItem).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type refundedOrder struct {
+	OrderID  int
+	Quantity int
+	Amount   int
+}
+
+// findRefundedOrders sums, per order, what pending and succeeded refunds of the transaction already cover.
+func findRefundedOrders(tx *gorm.DB, transactionID int) (map[int]refundedOrder, error) {
+	var rows []refundedOrder
+	if err := tx.Model(&entity.RefundItem{}).
+		Select("refund_items.order_id, SUM(refund_items.quantity) AS quantity, SUM(refund_items.amount) AS amount").
+		Joins("JOIN refunds ON refunds.id = refund_items.refund_id").
+		Where("refunds.transaction_id = ?", transactionID).
+		Where("refunds.status IN ?", []string{entity.RefundStatusPending, entity.RefundStatusSucceeded}).
+		Group("refund_items.order_id").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	refunded := make(map[int]refundedOrder, len(rows))
+	for _, row := range rows {
+		refunded[row.OrderID] = row
+	}
+	return refunded, nil
+}
+
+// toRefundItems builds the refund lines, refunding whatever is left of every order when no items are given.
+func toRefundItems(orders []entity.Order, payloads []request.RefundItemCreate, refunded map[int]refundedOrder) ([]entity.RefundItem, error) {
+	var items []entity.RefundItem
+
+	if len(payloads) == 0 {
+		for _, order := range orders {
+			quantity := order.Quantity - refunded[order.ID].Quantity
+			amount := order.TotalPrice - refunded[order.ID].Amount
+			if quantity <= 0 && amount <= 0 {
+				continue
+			}
+
+			items = append(items, entity.RefundItem{
+				OrderID:  order.ID,
+				Quantity: max(quantity, 0),
+				Amount:   max(amount, 0),
+			})
+		}
+
+		if len(items) == 0 {
+			return nil, exceptions.NewCustomError(http.StatusBadRequest, "Transaction is already fully refunded")
+		}
+		return items, nil
+	}
+
+	ordersByID := make(map[int]entity.Order, len(orders))
+	for _, order := range orders {
+		ordersByID[order.ID] = order
+	}
+
+	for _, payload := range payloads {
+		order, ok := ordersByID[payload.OrderID]
+		if !ok {
+			return nil, exceptions.NewCustomError(http.StatusNotFound, fmt.Sprintf("Order %d not found in transaction", payload.OrderID))
+		}
+
+		amount := payload.Amount
+		if amount == 0 {
+			amount = order.UnitPrice * payload.Quantity
+		}
+
+		done := refunded[order.ID]
+		if done.Quantity+payload.Quantity > order.Quantity {
+			return nil, exceptions.NewCustomError(http.StatusBadRequest, fmt.Sprintf("Order %d only has %d item(s) left to refund", order.ID, order.Quantity-done.Quantity))
+		}
+		if done.Amount+amount > order.TotalPrice {
+			return nil, exceptions.NewCustomError(http.StatusBadRequest, fmt.Sprintf("Refund amount for order %d exceeds what was paid", order.ID))
+		}
+
+		done.Quantity += payload.Quantity
+		done.Amount += amount
+		refunded[order.ID] = done
+
+		items = append(items, entity.RefundItem{
+			OrderID:  order.ID,
+			Quantity: payload.Quantity,
+			Amount:   amount,
+		})
+	}
+
+	return items, nil
+}
+
+func restockRefundItems(tx *gorm.DB, orders []entity.Order, items []entity.RefundItem, change stockChange) error {
+	ordersByID := make(map[int]entity.Order, len(orders))
+	for _, order := range orders {
+		ordersByID[order.ID] = order
+	}
+
+	var variantIDs []uint
+	for _, item := range items {
+		if item.Quantity == 0 {
+			continue
+		}
+
+		order := ordersByID[item.OrderID]
+		if err := moveVariantStock(tx, uint(order.BikeID), order.BikeVariantID, item.Quantity, change); err != nil {
+			return err
+		}
+		variantIDs = append(variantIDs, order.BikeVariantID)
+	}
+
+	if len(variantIDs) == 0 {
+		return nil
+	}
+
+	return syncVariantBikeStock(tx, variantIDs)
+}
+
+func updateorder(tx *gorm.DB, payload request.TransactionUpdate, transaction *entity.Transaction) error {
+	actorID := uint(transaction.UserID)
+	change := transactionStockChange(entity.StockMovementReservation, transaction.ID, &actorID)
+
+	var order entity.Order
+	if err := tx.Where("id = ?", payload.ID).Where("transaction_id = ?", transaction.ID).First(&order).Error; err != nil {
+		return err
+	}
+
+	if transaction.StockReservation == entity.StockReservationReserved {
+		if err := moveVariantStock(tx, uint(order.BikeID), order.BikeVariantID, order.Quantity, change); err != nil {
+			return err
+		}
+
+		if err := syncVariantBikeStock(tx, []uint{order.BikeVariantID}); err != nil {
+			return err
+		}
+
+		if err := reserveVariantStock(tx, map[uint]int{payload.VariantID: payload.Quantity}, change); err != nil {
+			return err
+		}
+	}
+
+	variants, err := findOrderVariants(tx, []uint{payload.VariantID})
+	if err != nil {
+		return err
+	}
+
+	transaction.TotalPrice -= order.TotalPrice
+
+	order.Quantity = payload.Quantity
+	setOrderVariant(&order, variants[payload.VariantID])
+
+	transaction.TotalPrice += order.TotalPrice
 
 	if err := tx.Save(&order).Error; err != nil {
 		return err
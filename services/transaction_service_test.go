@@ -0,0 +1,311 @@
+package services_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gowesmart/api-gowesmart/app/apptest"
+	"github.com/gowesmart/api-gowesmart/model/entity"
+	"github.com/gowesmart/api-gowesmart/model/web/request"
+	"github.com/gowesmart/api-gowesmart/services"
+	"github.com/gowesmart/api-gowesmart/utils"
+	"go.uber.org/zap"
+)
+
+func TestExpireOverdueReleasesReservationOfDeletedVariant(t *testing.T) {
+	db := apptest.NewDB(t)
+	user := apptest.CreateUser(t, db, entity.IDRoleUser)
+	variant := apptest.CreateVariant(t, db, 1000, 5)
+	if err := db.Omit("User").Create(&entity.Cart{UserID: user.ID}).Error; err != nil {
+		t.Fatalf("creating cart: %v", err)
+	}
+
+	service := services.NewTransactionService(utils.NewFakePaymentGateway("http://localhost/fake-payments"), time.Hour, true, utils.InvoiceIssuer{})
+
+	created, err := service.Create(apptest.NewContext(db), []request.TransactionCreate{{VariantID: variant.ID, Quantity: 2}}, int(user.ID))
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	if err := db.Delete(&entity.BikeVariant{}, variant.ID).Error; err != nil {
+		t.Fatalf("deleting variant: %v", err)
+	}
+
+	expired, err := service.ExpireOverdue(db, zap.NewNop(), time.Now().Add(2*time.Hour), 10)
+	if err != nil {
+		t.Fatalf("ExpireOverdue() error = %v", err)
+	}
+	if expired != 1 {
+		t.Fatalf("ExpireOverdue() expired %d transactions, want 1", expired)
+	}
+
+	var transaction entity.Transaction
+	if err := db.Take(&transaction, created.TransactionID).Error; err != nil {
+		t.Fatalf("loading transaction: %v", err)
+	}
+	if transaction.Status != entity.TransactionStatusExpired {
+		t.Errorf("status = %q, want %q", transaction.Status, entity.TransactionStatusExpired)
+	}
+	if transaction.StockReservation != entity.StockReservationReleased {
+		t.Errorf("stock reservation = %q, want %q", transaction.StockReservation, entity.StockReservationReleased)
+	}
+
+	var movements []entity.StockMovement
+	if err := db.Where("bike_variant_id = ? AND type = ?", variant.ID, entity.StockMovementReservation).Order("id").Find(&movements).Error; err != nil {
+		t.Fatalf("loading stock movements: %v", err)
+	}
+	if len(movements) != 2 || movements[0].Quantity != -2 || movements[1].Quantity != 2 {
+		t.Fatalf("reservation movements = %+v, want -2 then 2", movements)
+	}
+	if movements[1].BikeID != variant.BikeID {
+		t.Errorf("released movement bike id = %d, want %d", movements[1].BikeID, variant.BikeID)
+	}
+
+	var cartItems int64
+	if err := db.Model(&entity.CartItem{}).Where("bike_variant_id = ?", variant.ID).Count(&cartItems).Error; err != nil {
+		t.Fatalf("counting cart items: %v", err)
+	}
+	if cartItems != 0 {
+		t.Errorf("restored %d cart items of the deleted variant, want 0", cartItems)
+	}
+}
+
+func TestHandleMidtransNotificationRejectsInvalidSignatures(t *testing.T) {
+	gateway := utils.NewMidtransPaymentGateway(services.MidtransTestServerKey, "sandbox")
+	service := services.NewTransactionService(gateway, time.Hour, true, utils.InvoiceIssuer{})
+
+	for _, name := range []string{"settlement", "capture_accept", "capture_challenge", "pending", "deny", "cancel", "expire", "refund", "partial_refund", "authorize"} {
+		notification := services.LoadMidtransNotification(t, name)
+		if !gateway.VerifySignature(notification.OrderID, notification.StatusCode, notification.GrossAmount, notification.SignatureKey) {
+			t.Errorf("%s notification fixture fails signature verification", name)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		tamper func(notification *request.MidtransNotificationRequest)
+	}{
+		{"gross amount changed", func(n *request.MidtransNotificationRequest) { n.GrossAmount = "1000.00" }},
+		{"status code changed", func(n *request.MidtransNotificationRequest) { n.StatusCode = "201" }},
+		{"order id changed", func(n *request.MidtransNotificationRequest) { n.OrderID = "1043" }},
+		{"signed with another server key", func(n *request.MidtransNotificationRequest) {
+			n.SignatureKey = utils.MidtransSignature(n.OrderID, n.StatusCode, n.GrossAmount, "SB-Mid-server-another")
+		}},
+		{"signature missing", func(n *request.MidtransNotificationRequest) { n.SignatureKey = "" }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			notification := services.LoadMidtransNotification(t, "settlement")
+			tt.tamper(&notification)
+
+			// the signature is checked before the database is touched
+			err := service.HandleMidtransNotification(apptest.NewContext(nil), &notification)
+			if code := errorCode(err); code != http.StatusForbidden {
+				t.Errorf("HandleMidtransNotification() error = %v (code %d), want code %d", err, code, http.StatusForbidden)
+			}
+		})
+	}
+}
+
+func TestHandleMidtransNotification(t *testing.T) {
+	tests := []struct {
+		name            string
+		notifications   []string
+		grossAmount     string
+		refundErr       error
+		wantCode        int
+		wantStatus      string
+		wantReservation string
+		wantSales       int
+		wantRefund      string
+	}{
+		{name: "settlement pays", notifications: []string{"settlement"}, wantStatus: entity.TransactionStatusPaid, wantReservation: entity.StockReservationCommitted, wantSales: 1},
+		{name: "accepted capture pays", notifications: []string{"capture_accept"}, wantStatus: entity.TransactionStatusPaid, wantReservation: entity.StockReservationCommitted, wantSales: 1},
+		{name: "challenged capture stays pending", notifications: []string{"capture_challenge"}, wantStatus: entity.TransactionStatusPending, wantReservation: entity.StockReservationReserved},
+		{name: "deny cancels", notifications: []string{"deny"}, wantStatus: entity.TransactionStatusCancelled, wantReservation: entity.StockReservationReleased},
+		{name: "cancel cancels", notifications: []string{"cancel"}, wantStatus: entity.TransactionStatusCancelled, wantReservation: entity.StockReservationReleased},
+		{name: "expire expires", notifications: []string{"expire"}, wantStatus: entity.TransactionStatusExpired, wantReservation: entity.StockReservationReleased},
+		{name: "duplicate settlement is applied once", notifications: []string{"settlement", "settlement"}, wantStatus: entity.TransactionStatusPaid, wantReservation: entity.StockReservationCommitted, wantSales: 1},
+		{name: "settlement after expiry is refunded", notifications: []string{"expire", "settlement"}, wantStatus: entity.TransactionStatusExpired, wantReservation: entity.StockReservationReleased, wantRefund: entity.RefundStatusSucceeded},
+		{name: "duplicate settlement after expiry is refunded once", notifications: []string{"expire", "settlement", "settlement"}, wantStatus: entity.TransactionStatusExpired, wantReservation: entity.StockReservationReleased, wantRefund: entity.RefundStatusSucceeded},
+		{name: "settlement after cancel the gateway cannot refund is left for a manual refund", notifications: []string{"cancel", "settlement"}, refundErr: errors.New("refund rejected"), wantStatus: entity.TransactionStatusCancelled, wantReservation: entity.StockReservationReleased, wantRefund: entity.RefundStatusManual},
+		{name: "gross amount mismatch is rejected", notifications: []string{"settlement"}, grossAmount: "1000.00", wantCode: http.StatusBadRequest, wantStatus: entity.TransactionStatusPending, wantReservation: entity.StockReservationReserved},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := apptest.NewDB(t)
+			user := apptest.CreateUser(t, db, entity.IDRoleUser)
+			variant := apptest.CreateVariant(t, db, 125000, 5)
+
+			gateway := &midtransTestGateway{FakePaymentGateway: utils.NewFakePaymentGateway("http://localhost/fake-payments"), refundErr: tt.refundErr}
+			service := services.NewTransactionService(gateway, time.Hour, true, utils.InvoiceIssuer{})
+
+			created, err := service.Create(apptest.NewContext(db), []request.TransactionCreate{{VariantID: variant.ID, Quantity: 2}}, int(user.ID))
+			if err != nil {
+				t.Fatalf("Create() error = %v", err)
+			}
+
+			for i, name := range tt.notifications {
+				notification := services.LoadMidtransNotification(t, name)
+				notification.OrderID = strconv.Itoa(created.TransactionID)
+				notification.GrossAmount = fmt.Sprintf("%d.00", created.TotalPrice)
+				if tt.grossAmount != "" {
+					notification.GrossAmount = tt.grossAmount
+				}
+				notification.SignatureKey = utils.MidtransSignature(notification.OrderID, notification.StatusCode, notification.GrossAmount, services.MidtransTestServerKey)
+
+				// keep the charge in step with what the notification reports, as Midtrans would
+				if _, err := gateway.Notify(notification.OrderID, notification.TransactionStatus); err != nil {
+					t.Fatalf("Notify() error = %v", err)
+				}
+
+				err := service.HandleMidtransNotification(apptest.NewContext(db), &notification)
+
+				wantCode := 0
+				if i == len(tt.notifications)-1 {
+					wantCode = tt.wantCode
+				}
+				if code := errorCode(err); code != wantCode {
+					t.Fatalf("HandleMidtransNotification(%s) error = %v (code %d), want code %d", name, err, code, wantCode)
+				}
+			}
+
+			var transaction entity.Transaction
+			if err := db.Take(&transaction, created.TransactionID).Error; err != nil {
+				t.Fatalf("loading transaction: %v", err)
+			}
+			if transaction.Status != tt.wantStatus {
+				t.Errorf("status = %q, want %q", transaction.Status, tt.wantStatus)
+			}
+			if transaction.StockReservation != tt.wantReservation {
+				t.Errorf("stock reservation = %q, want %q", transaction.StockReservation, tt.wantReservation)
+			}
+
+			var changes int64
+			if err := db.Model(&entity.TransactionStatusHistory{}).Where("transaction_id = ? AND to_status = ?", transaction.ID, tt.wantStatus).Count(&changes).Error; err != nil {
+				t.Fatalf("counting status history: %v", err)
+			}
+			if changes != 1 {
+				t.Errorf("moved to %s %d times, want once", tt.wantStatus, changes)
+			}
+
+			var sales int64
+			if err := db.Model(&entity.StockMovement{}).Where("bike_variant_id = ? AND type = ?", variant.ID, entity.StockMovementSale).Count(&sales).Error; err != nil {
+				t.Fatalf("counting sales: %v", err)
+			}
+			if sales != int64(tt.wantSales) {
+				t.Errorf("recorded %d sales, want %d", sales, tt.wantSales)
+			}
+
+			var refunds []entity.Refund
+			if err := db.Where("transaction_id = ?", transaction.ID).Find(&refunds).Error; err != nil {
+				t.Fatalf("loading refunds: %v", err)
+			}
+			if tt.wantRefund == "" {
+				if len(refunds) != 0 {
+					t.Errorf("booked %d refunds, want none", len(refunds))
+				}
+				return
+			}
+			if len(refunds) != 1 {
+				t.Fatalf("booked %d refunds, want one", len(refunds))
+			}
+			if refunds[0].Status != tt.wantRefund {
+				t.Errorf("refund status = %q, want %q", refunds[0].Status, tt.wantRefund)
+			}
+			if refunds[0].Amount != created.TotalPrice || refunds[0].ActorID != entity.RefundActorSystem {
+				t.Errorf("refund = %d by actor %d, want %d by the system", refunds[0].Amount, refunds[0].ActorID, created.TotalPrice)
+			}
+		})
+	}
+}
+
+func TestHandleMidtransNotificationDoesNotOversellTransactionsWithoutReservation(t *testing.T) {
+	db := apptest.NewDB(t)
+	user := apptest.CreateUser(t, db, entity.IDRoleUser)
+	variant := apptest.CreateVariant(t, db, 125000, 5)
+
+	gateway := &midtransTestGateway{FakePaymentGateway: utils.NewFakePaymentGateway("http://localhost/fake-payments")}
+	service := services.NewTransactionService(gateway, time.Hour, true, utils.InvoiceIssuer{})
+
+	created, err := service.Create(apptest.NewContext(db), []request.TransactionCreate{{VariantID: variant.ID, Quantity: 2}}, int(user.ID))
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	// a transaction from before stock was reserved at checkout, by now only one bike is left
+	if err := db.Model(&entity.Transaction{}).Where("id = ?", created.TransactionID).Update("stock_reservation", "").Error; err != nil {
+		t.Fatalf("clearing stock reservation: %v", err)
+	}
+	if err := db.Model(&entity.BikeVariant{}).Where("id = ?", variant.ID).Update("stock", 1).Error; err != nil {
+		t.Fatalf("setting variant stock: %v", err)
+	}
+
+	notification := services.LoadMidtransNotification(t, "settlement")
+	notification.OrderID = strconv.Itoa(created.TransactionID)
+	notification.GrossAmount = fmt.Sprintf("%d.00", created.TotalPrice)
+	notification.SignatureKey = utils.MidtransSignature(notification.OrderID, notification.StatusCode, notification.GrossAmount, services.MidtransTestServerKey)
+
+	if err := service.HandleMidtransNotification(apptest.NewContext(db), &notification); err != nil {
+		t.Fatalf("HandleMidtransNotification() error = %v", err)
+	}
+
+	var stock int
+	if err := db.Model(&entity.BikeVariant{}).Select("stock").Where("id = ?", variant.ID).Scan(&stock).Error; err != nil {
+		t.Fatalf("loading variant stock: %v", err)
+	}
+	if stock != 0 {
+		t.Errorf("variant stock = %d, want 0", stock)
+	}
+
+	var sale entity.StockMovement
+	if err := db.Where("bike_variant_id = ? AND type = ?", variant.ID, entity.StockMovementSale).Take(&sale).Error; err != nil {
+		t.Fatalf("loading sale: %v", err)
+	}
+	if sale.Quantity != -1 || sale.Note != "oversold by 1, only 1 in stock" {
+		t.Errorf("sale = %d %q, want -1 noting the oversell", sale.Quantity, sale.Note)
+	}
+}
+
+// midtransTestGateway is the fake gateway checking signatures against the key the testdata notifications
+// were signed with, and optionally refusing refunds.
+type midtransTestGateway struct {
+	*utils.FakePaymentGateway
+	refundErr error
+}
+
+func (g *midtransTestGateway) VerifySignature(orderID, statusCode, grossAmount, signatureKey string) bool {
+	return signatureKey == utils.MidtransSignature(orderID, statusCode, grossAmount, services.MidtransTestServerKey)
+}
+
+func (g *midtransTestGateway) Refund(orderID string, payload utils.PaymentRefundPayload) (*utils.PaymentRefund, error) {
+	if g.refundErr != nil {
+		return nil, g.refundErr
+	}
+	return g.FakePaymentGateway.Refund(orderID, payload)
+}
+
+// errorCode is the HTTP status code a service error is answered with, 0 for no error.
+func errorCode(err error) int {
+	if err == nil {
+		return 0
+	}
+
+	var body struct {
+		Code int `json:"code"`
+	}
+	if encoded, marshalErr := json.Marshal(err); marshalErr == nil {
+		json.Unmarshal(encoded, &body)
+	}
+	if body.Code == 0 {
+		return http.StatusInternalServerError
+	}
+	return body.Code
+}
@@ -0,0 +1,45 @@
+package utils
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestEmailNotifierGivesUpWhenContextIsDone(t *testing.T) {
+	// a server that accepts connections but never greets
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listening: %v", err)
+	}
+	defer listener.Close()
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+		}
+	}()
+
+	host, port, _ := net.SplitHostPort(listener.Addr().String())
+	notifier := NewEmailNotifier(EmailConfig{Host: host, Port: port, From: "store@example.com", To: []string{"admin@example.com"}})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() { done <- notifier.Notify(ctx, Notification{Subject: "subject", Message: "message"}) }()
+
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Error("Notify() error = nil, want the context's deadline to end the conversation")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Notify() kept waiting on the server after the context was done")
+	}
+}
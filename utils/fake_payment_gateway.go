@@ -0,0 +1,145 @@
+package utils
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/gowesmart/api-gowesmart/model/web/request"
+)
+
+const fakeServerKey = "fake-server-key"
+
+// FakePaymentGateway is an in-process PaymentGateway for local development and tests.
+// Charges are kept in memory, payment links are derived from the order ID and
+// settlements are triggered explicitly instead of by a real payment.
+type FakePaymentGateway struct {
+	baseURL string
+	mu      sync.Mutex
+	charges map[string]*PaymentStatus
+	refunds map[string]int
+}
+
+func NewFakePaymentGateway(baseURL string) *FakePaymentGateway {
+	return &FakePaymentGateway{
+		baseURL: baseURL,
+		charges: map[string]*PaymentStatus{},
+		refunds: map[string]int{},
+	}
+}
+
+func (g *FakePaymentGateway) CreateCharge(payload PaymentPayload) (*PaymentCharge, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	orderID := strconv.Itoa(payload.OrderId)
+	g.charges[orderID] = &PaymentStatus{
+		OrderID:           orderID,
+		StatusCode:        "201",
+		GrossAmount:       fmt.Sprintf("%d.00", payload.Amount),
+		TransactionStatus: "pending",
+	}
+
+	return &PaymentCharge{
+		OrderID:     orderID,
+		Token:       "fake-token-" + orderID,
+		RedirectURL: fmt.Sprintf("%s/%s", g.baseURL, orderID),
+	}, nil
+}
+
+func (g *FakePaymentGateway) GetStatus(orderID string) (*PaymentStatus, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	charge, err := g.charge(orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	status := *charge
+	return &status, nil
+}
+
+func (g *FakePaymentGateway) Cancel(orderID string) (*PaymentStatus, error) {
+	return g.setStatus(orderID, "cancel", "200")
+}
+
+func (g *FakePaymentGateway) Refund(orderID string, payload PaymentRefundPayload) (*PaymentRefund, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	charge, err := g.charge(orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	if charge.TransactionStatus != "settlement" && charge.TransactionStatus != "partial_refund" {
+		return nil, fmt.Errorf("fake payment gateway: order %s cannot be refunded in status %s", orderID, charge.TransactionStatus)
+	}
+
+	g.refunds[orderID] += payload.Amount
+	charge.StatusCode = "200"
+	charge.TransactionStatus = "partial_refund"
+	if gross, _ := strconv.ParseFloat(charge.GrossAmount, 64); g.refunds[orderID] >= int(gross) {
+		charge.TransactionStatus = "refund"
+	}
+
+	return &PaymentRefund{
+		RefundKey:         payload.RefundKey,
+		Amount:            payload.Amount,
+		TransactionStatus: charge.TransactionStatus,
+	}, nil
+}
+
+func (g *FakePaymentGateway) VerifySignature(orderID, statusCode, grossAmount, signatureKey string) bool {
+	return verifyMidtransSignature(orderID, statusCode, grossAmount, fakeServerKey, signatureKey)
+}
+
+// Settle marks the charge as paid and returns the signed notification Midtrans would send,
+// ready to be posted to the notification endpoint.
+func (g *FakePaymentGateway) Settle(orderID string) (*request.MidtransNotificationRequest, error) {
+	return g.Notify(orderID, "settlement")
+}
+
+// Notify moves the charge to the given Midtrans transaction_status and returns the matching signed notification.
+func (g *FakePaymentGateway) Notify(orderID, transactionStatus string) (*request.MidtransNotificationRequest, error) {
+	status, err := g.setStatus(orderID, transactionStatus, "200")
+	if err != nil {
+		return nil, err
+	}
+
+	return &request.MidtransNotificationRequest{
+		TransactionID:     "fake-" + orderID,
+		TransactionStatus: status.TransactionStatus,
+		StatusCode:        status.StatusCode,
+		SignatureKey:      MidtransSignature(orderID, status.StatusCode, status.GrossAmount, fakeServerKey),
+		PaymentType:       "fake",
+		OrderID:           orderID,
+		GrossAmount:       status.GrossAmount,
+		FraudStatus:       "accept",
+	}, nil
+}
+
+func (g *FakePaymentGateway) setStatus(orderID, transactionStatus, statusCode string) (*PaymentStatus, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	charge, err := g.charge(orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	charge.TransactionStatus = transactionStatus
+	charge.StatusCode = statusCode
+
+	status := *charge
+	return &status, nil
+}
+
+func (g *FakePaymentGateway) charge(orderID string) (*PaymentStatus, error) {
+	charge, ok := g.charges[orderID]
+	if !ok {
+		return nil, fmt.Errorf("fake payment gateway: order %s not found", orderID)
+	}
+	return charge, nil
+}
@@ -0,0 +1,105 @@
+package utils
+
+import (
+	"sort"
+	"unicode"
+)
+
+// TextRange marks the characters of a text from Start up to but excluding End, counted in runes.
+type TextRange struct {
+	Start int
+	End   int
+}
+
+// Highlight finds the words of query in text, ignoring case. A word found in the text is marked where
+// it appears, otherwise the longest prefix it shares with a word of the text is, so a misspelled
+// "marlni" still marks "Marl" in "Marlin". Shared prefixes under 2 characters aren't marked, and the
+// ranges come back sorted and merged.
+func Highlight(text, query string) []TextRange {
+	haystack := lowerRunes(text)
+	terms := lowerRunes(query)
+
+	var ranges []TextRange
+	for _, term := range wordRanges(query) {
+		needle := terms[term.Start:term.End]
+
+		if i := indexRunes(haystack, needle); i >= 0 {
+			ranges = append(ranges, TextRange{Start: i, End: i + len(needle)})
+			continue
+		}
+
+		best := TextRange{}
+		for _, word := range wordRanges(text) {
+			n := 0
+			for n < len(needle) && word.Start+n < word.End && haystack[word.Start+n] == needle[n] {
+				n++
+			}
+			if n > best.End-best.Start {
+				best = TextRange{Start: word.Start, End: word.Start + n}
+			}
+		}
+		if best.End-best.Start >= 2 {
+			ranges = append(ranges, best)
+		}
+	}
+
+	sort.Slice(ranges, func(i, j int) bool { return ranges[i].Start < ranges[j].Start })
+
+	merged := make([]TextRange, 0, len(ranges))
+	for _, r := range ranges {
+		if last := len(merged) - 1; last >= 0 && r.Start <= merged[last].End {
+			if r.End > merged[last].End {
+				merged[last].End = r.End
+			}
+			continue
+		}
+		merged = append(merged, r)
+	}
+
+	return merged
+}
+
+// lowerRunes lowercases rune by rune so offsets stay those of the original text.
+func lowerRunes(s string) []rune {
+	runes := []rune(s)
+	for i, r := range runes {
+		runes[i] = unicode.ToLower(r)
+	}
+	return runes
+}
+
+// wordRanges finds the runs of letters and digits in s.
+func wordRanges(s string) []TextRange {
+	var words []TextRange
+	start := -1
+	for i, r := range []rune(s) {
+		isWord := unicode.IsLetter(r) || unicode.IsDigit(r)
+		switch {
+		case isWord && start < 0:
+			start = i
+		case !isWord && start >= 0:
+			words = append(words, TextRange{Start: start, End: i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		words = append(words, TextRange{Start: start, End: len([]rune(s))})
+	}
+	return words
+}
+
+func indexRunes(haystack, needle []rune) int {
+	for i := 0; i+len(needle) <= len(haystack); i++ {
+		match := true
+		for j := range needle {
+			if haystack[i+j] != needle[j] {
+				match = false
+				break
+			}
+		}
+		if match {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,47 @@
+package utils
+
+import (
+	"bytes"
+	"image"
+	"image/jpeg"
+	_ "image/png"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+// ImageExtensions maps the image content types accepted for upload to their file extension.
+var ImageExtensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/webp": ".webp",
+}
+
+// Thumbnail scales img down to fit in a maxSize x maxSize box, keeping its aspect ratio,
+// and encodes it as a JPEG. Images already small enough are only re-encoded.
+func Thumbnail(img image.Image, maxSize int) ([]byte, error) {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+
+	if width > maxSize || height > maxSize {
+		if width >= height {
+			height = max(height*maxSize/width, 1)
+			width = maxSize
+		} else {
+			width = max(width*maxSize/height, 1)
+			height = maxSize
+		}
+	}
+
+	// JPEG has no transparency, so transparent pixels are laid over white instead of turning black
+	thumbnail := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(thumbnail, thumbnail.Bounds(), image.White, image.Point{}, draw.Src)
+	draw.CatmullRom.Scale(thumbnail, thumbnail.Bounds(), img, bounds, draw.Over, nil)
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, thumbnail, &jpeg.Options{Quality: 85}); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
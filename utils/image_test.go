@@ -0,0 +1,59 @@
+package utils
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"testing"
+)
+
+func TestThumbnailSize(t *testing.T) {
+	tests := []struct {
+		name          string
+		width, height int
+		wantW, wantH  int
+	}{
+		{"landscape", 1600, 900, 400, 225},
+		{"portrait", 300, 1200, 100, 400},
+		{"square", 1000, 1000, 400, 400},
+		{"small images keep their size", 200, 100, 200, 100},
+		{"very thin images keep a pixel", 5000, 4, 400, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := Thumbnail(image.NewRGBA(image.Rect(0, 0, tt.width, tt.height)), 400)
+			if err != nil {
+				t.Fatalf("Thumbnail() error = %v", err)
+			}
+
+			thumbnail, err := jpeg.Decode(bytes.NewReader(data))
+			if err != nil {
+				t.Fatalf("decoding thumbnail: %v", err)
+			}
+
+			if got := thumbnail.Bounds().Size(); got.X != tt.wantW || got.Y != tt.wantH {
+				t.Errorf("Thumbnail() of %dx%d is %dx%d, want %dx%d", tt.width, tt.height, got.X, got.Y, tt.wantW, tt.wantH)
+			}
+		})
+	}
+}
+
+func TestThumbnailLaysTransparencyOverWhite(t *testing.T) {
+	// a fully transparent image
+	data, err := Thumbnail(image.NewNRGBA(image.Rect(0, 0, 800, 800)), 400)
+	if err != nil {
+		t.Fatalf("Thumbnail() error = %v", err)
+	}
+
+	thumbnail, err := jpeg.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("decoding thumbnail: %v", err)
+	}
+
+	r, g, b, _ := color.RGBAModel.Convert(thumbnail.At(200, 200)).RGBA()
+	if r>>8 < 250 || g>>8 < 250 || b>>8 < 250 {
+		t.Errorf("transparent pixel became rgb(%d, %d, %d), want white", r>>8, g>>8, b>>8)
+	}
+}
@@ -0,0 +1,177 @@
+package utils
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/go-pdf/fpdf"
+)
+
+// InvoiceIssuer is the store printed in the invoice header.
+type InvoiceIssuer struct {
+	Name    string
+	Address string
+	Email   string
+}
+
+type InvoiceLine struct {
+	Description string
+	Quantity    int
+	UnitPrice   int
+	Total       int
+}
+
+type Invoice struct {
+	Issuer        InvoiceIssuer
+	Number        string
+	TransactionID int
+	IssuedAt      time.Time
+	BuyerName     string
+	BuyerEmail    string
+	Lines         []InvoiceLine
+	Total         int
+	Refunded      int
+	PaymentStatus string
+	PaidAt        time.Time
+}
+
+// invoice dates are printed in the store's timezone regardless of where the server runs
+var invoiceLocation = time.FixedZone("WIB", 7*60*60)
+
+func NewInvoiceIssuer() InvoiceIssuer {
+	return InvoiceIssuer{
+		Name:    GetEnv("STORE_NAME", "GowesMart"),
+		Address: GetEnv("STORE_ADDRESS", ""),
+		Email:   GetEnv("STORE_EMAIL", ""),
+	}
+}
+
+// RenderInvoicePDF writes the invoice as an A4 PDF. The output only depends on the invoice,
+// the document dates are taken from IssuedAt so rendering the same invoice twice gives the same bytes.
+func RenderInvoicePDF(w io.Writer, invoice Invoice) error {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetCreationDate(invoice.IssuedAt)
+	pdf.SetModificationDate(invoice.IssuedAt)
+	pdf.SetCatalogSort(true)
+	pdf.SetProducer(invoice.Issuer.Name, true)
+	pdf.SetCreator(invoice.Issuer.Name, true)
+	pdf.SetAuthor(invoice.Issuer.Name, true)
+	pdf.SetTitle("Invoice "+invoice.Number, true)
+	pdf.SetMargins(20, 20, 20)
+	pdf.SetAutoPageBreak(true, 20)
+	pdf.AddPage()
+
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+
+	// store header
+	pdf.SetFont("Helvetica", "B", 18)
+	pdf.CellFormat(100, 9, tr(invoice.Issuer.Name), "", 0, "L", false, 0, "")
+	pdf.SetFont("Helvetica", "B", 14)
+	pdf.CellFormat(70, 9, "INVOICE", "", 1, "R", false, 0, "")
+
+	pdf.SetFont("Helvetica", "", 9)
+	for _, line := range []string{invoice.Issuer.Address, invoice.Issuer.Email} {
+		if line != "" {
+			pdf.CellFormat(170, 5, tr(line), "", 1, "L", false, 0, "")
+		}
+	}
+	pdf.Ln(6)
+
+	// invoice and buyer details
+	pdf.SetFont("Helvetica", "B", 10)
+	pdf.CellFormat(85, 6, "Billed to", "", 0, "L", false, 0, "")
+	pdf.CellFormat(85, 6, "Invoice details", "", 1, "L", false, 0, "")
+
+	pdf.SetFont("Helvetica", "", 10)
+	left := []string{invoice.BuyerName, invoice.BuyerEmail}
+	right := []string{
+		"Number: " + invoice.Number,
+		"Transaction: #" + strconv.Itoa(invoice.TransactionID),
+		"Date: " + invoice.IssuedAt.In(invoiceLocation).Format("02 Jan 2006"),
+	}
+	for i := 0; i < len(right); i++ {
+		var buyer string
+		if i < len(left) {
+			buyer = left[i]
+		}
+		pdf.CellFormat(85, 5, tr(buyer), "", 0, "L", false, 0, "")
+		pdf.CellFormat(85, 5, tr(right[i]), "", 1, "L", false, 0, "")
+	}
+	pdf.Ln(8)
+
+	// order lines
+	widths := []float64{85, 20, 32.5, 32.5}
+	pdf.SetFont("Helvetica", "B", 10)
+	pdf.SetFillColor(235, 235, 235)
+	for i, header := range []string{"Bike", "Qty", "Unit price", "Amount"} {
+		align := "R"
+		if i == 0 {
+			align = "L"
+		}
+		pdf.CellFormat(widths[i], 7, header, "B", 0, align, true, 0, "")
+	}
+	pdf.Ln(-1)
+
+	pdf.SetFont("Helvetica", "", 10)
+	for _, line := range invoice.Lines {
+		pdf.CellFormat(widths[0], 7, tr(line.Description), "B", 0, "L", false, 0, "")
+		pdf.CellFormat(widths[1], 7, strconv.Itoa(line.Quantity), "B", 0, "R", false, 0, "")
+		pdf.CellFormat(widths[2], 7, FormatRupiah(line.UnitPrice), "B", 0, "R", false, 0, "")
+		pdf.CellFormat(widths[3], 7, FormatRupiah(line.Total), "B", 1, "R", false, 0, "")
+	}
+	pdf.Ln(3)
+
+	// totals
+	totals := [][2]string{{"Total", FormatRupiah(invoice.Total)}}
+	if invoice.Refunded > 0 {
+		totals = append(totals,
+			[2]string{"Refunded", "- " + FormatRupiah(invoice.Refunded)},
+			[2]string{"Net paid", FormatRupiah(invoice.Total - invoice.Refunded)},
+		)
+	}
+	for i, total := range totals {
+		style := ""
+		if i == 0 || i == len(totals)-1 {
+			style = "B"
+		}
+		pdf.SetFont("Helvetica", style, 10)
+		pdf.CellFormat(widths[0]+widths[1]+widths[2], 7, total[0], "", 0, "R", false, 0, "")
+		pdf.CellFormat(widths[3], 7, total[1], "", 1, "R", false, 0, "")
+	}
+	pdf.Ln(8)
+
+	// payment
+	pdf.SetFont("Helvetica", "B", 10)
+	pdf.CellFormat(170, 6, "Payment", "", 1, "L", false, 0, "")
+	pdf.SetFont("Helvetica", "", 10)
+	pdf.CellFormat(170, 5, "Status: "+invoice.PaymentStatus, "", 1, "L", false, 0, "")
+	pdf.CellFormat(170, 5, "Paid at: "+invoice.PaidAt.In(invoiceLocation).Format("02 Jan 2006 15:04 MST"), "", 1, "L", false, 0, "")
+
+	if err := pdf.Error(); err != nil {
+		return err
+	}
+
+	return pdf.Output(w)
+}
+
+// FormatRupiah formats an amount the way Indonesian prices are written, e.g. Rp 18.000.000.
+func FormatRupiah(amount int) string {
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+
+	digits := strconv.Itoa(amount)
+	var grouped []byte
+	for i := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			grouped = append(grouped, '.')
+		}
+		grouped = append(grouped, digits[i])
+	}
+
+	return fmt.Sprintf("%sRp %s", sign, grouped)
+}
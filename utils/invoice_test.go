@@ -0,0 +1,76 @@
+package utils
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+var updateGolden = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+func TestRenderInvoicePDF(t *testing.T) {
+	invoice := Invoice{
+		Issuer: InvoiceIssuer{
+			Name:    "GowesMart",
+			Address: "Jl. Sudirman No. 1, Jakarta",
+			Email:   "hello@gowesmart.id",
+		},
+		Number:        "INV-2024-000042",
+		TransactionID: 42,
+		IssuedAt:      time.Date(2024, 3, 14, 9, 30, 0, 0, time.UTC),
+		BuyerName:     "Budi Santoso",
+		BuyerEmail:    "budi@example.com",
+		Lines: []InvoiceLine{
+			{Description: "Polygon Strattos S5 (M / Red)", Quantity: 1, UnitPrice: 18000000, Total: 18000000},
+			{Description: "Brompton C Line", Quantity: 2, UnitPrice: 35500000, Total: 71000000},
+		},
+		Total:         89000000,
+		Refunded:      35500000,
+		PaymentStatus: "Partially refunded",
+		PaidAt:        time.Date(2024, 3, 14, 9, 28, 5, 0, time.UTC),
+	}
+
+	var got bytes.Buffer
+	if err := RenderInvoicePDF(&got, invoice); err != nil {
+		t.Fatalf("RenderInvoicePDF() error = %v", err)
+	}
+
+	golden := filepath.Join("testdata", "invoice.golden.pdf")
+	if *updateGolden {
+		if err := os.WriteFile(golden, got.Bytes(), 0o644); err != nil {
+			t.Fatalf("writing golden file: %v", err)
+		}
+	}
+
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatalf("reading golden file, run with -update to create it: %v", err)
+	}
+
+	if !bytes.Equal(got.Bytes(), want) {
+		t.Errorf("RenderInvoicePDF() output differs from %s, run with -update if the change is intended", golden)
+	}
+}
+
+func TestFormatRupiah(t *testing.T) {
+	tests := []struct {
+		amount int
+		want   string
+	}{
+		{0, "Rp 0"},
+		{999, "Rp 999"},
+		{1000, "Rp 1.000"},
+		{18000000, "Rp 18.000.000"},
+		{123456789, "Rp 123.456.789"},
+		{-35500000, "-Rp 35.500.000"},
+	}
+
+	for _, tt := range tests {
+		if got := FormatRupiah(tt.amount); got != tt.want {
+			t.Errorf("FormatRupiah(%d) = %q, want %q", tt.amount, got, tt.want)
+		}
+	}
+}
@@ -1,25 +1,38 @@
 package utils
 
 import (
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
 	"strconv"
 
 	"github.com/midtrans/midtrans-go"
+	"github.com/midtrans/midtrans-go/coreapi"
 	"github.com/midtrans/midtrans-go/snap"
 )
 
-type PaymentPayload struct {
-	OrderId int
-	Amount int
-	FName string
-	Email string
+type MidtransPaymentGateway struct {
+	serverKey  string
+	snapClient snap.Client
+	coreClient coreapi.Client
 }
 
-func CreatePayment(payload PaymentPayload) (string, error) {
-	serverKey := MustGetEnv("MIDTRANS_SERVER_KEY")
+// NewMidtransPaymentGateway creates a Midtrans gateway, environment is either "sandbox" or "production".
+func NewMidtransPaymentGateway(serverKey, environment string) *MidtransPaymentGateway {
+	env := midtrans.Sandbox
+	if environment == "production" {
+		env = midtrans.Production
+	}
+
+	gateway := &MidtransPaymentGateway{serverKey: serverKey}
+	gateway.snapClient.New(serverKey, env)
+	gateway.coreClient.New(serverKey, env)
 
-	s := snap.Client{}
-	s.New(serverKey, midtrans.Sandbox)
+	return gateway
+}
 
+func (g *MidtransPaymentGateway) CreateCharge(payload PaymentPayload) (*PaymentCharge, error) {
 	req := &snap.Request{
 		TransactionDetails: midtrans.TransactionDetails{
 			OrderID:  strconv.Itoa(payload.OrderId),
@@ -31,9 +44,94 @@ func CreatePayment(payload PaymentPayload) (string, error) {
 		},
 	}
 
-	snapRes, err := s.CreateTransaction(req)
+	if payload.Expiry > 0 {
+		req.Expiry = &snap.ExpiryDetails{
+			Unit:     "minute",
+			Duration: int64(payload.Expiry.Minutes()),
+		}
+	}
+
+	snapRes, err := g.snapClient.CreateTransaction(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PaymentCharge{
+		OrderID:     req.TransactionDetails.OrderID,
+		Token:       snapRes.Token,
+		RedirectURL: snapRes.RedirectURL,
+	}, nil
+}
+
+func (g *MidtransPaymentGateway) GetStatus(orderID string) (*PaymentStatus, error) {
+	res, err := g.coreClient.CheckTransaction(orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PaymentStatus{
+		OrderID:           res.OrderID,
+		StatusCode:        res.StatusCode,
+		GrossAmount:       res.GrossAmount,
+		TransactionStatus: res.TransactionStatus,
+		FraudStatus:       res.FraudStatus,
+	}, nil
+}
+
+func (g *MidtransPaymentGateway) Cancel(orderID string) (*PaymentStatus, error) {
+	res, err := g.coreClient.CancelTransaction(orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PaymentStatus{
+		OrderID:           res.OrderID,
+		StatusCode:        res.StatusCode,
+		GrossAmount:       res.GrossAmount,
+		TransactionStatus: res.TransactionStatus,
+		FraudStatus:       res.FraudStatus,
+	}, nil
+}
+
+func (g *MidtransPaymentGateway) Refund(orderID string, payload PaymentRefundPayload) (*PaymentRefund, error) {
+	res, err := g.coreClient.RefundTransaction(orderID, &coreapi.RefundReq{
+		RefundKey: payload.RefundKey,
+		Amount:    int64(payload.Amount),
+		Reason:    payload.Reason,
+	})
 	if err != nil {
-		return "", nil
+		return nil, err
+	}
+
+	// Midtrans answers a refund it turned down with a non 2xx status_code in a successful response
+	if res.StatusCode != "200" && res.StatusCode != "201" {
+		return nil, fmt.Errorf("midtrans: refund of order %s failed with status %s: %s", orderID, res.StatusCode, res.StatusMessage)
+	}
+
+	amount, parseErr := strconv.ParseFloat(res.RefundAmount, 64)
+	if parseErr != nil {
+		return nil, fmt.Errorf("midtrans: invalid refund amount %q of order %s: %w", res.RefundAmount, orderID, parseErr)
 	}
-	return snapRes.RedirectURL, nil
-}
\ No newline at end of file
+
+	return &PaymentRefund{
+		RefundKey:         res.RefundKey,
+		Amount:            int(amount),
+		TransactionStatus: res.TransactionStatus,
+	}, nil
+}
+
+func (g *MidtransPaymentGateway) VerifySignature(orderID, statusCode, grossAmount, signatureKey string) bool {
+	return verifyMidtransSignature(orderID, statusCode, grossAmount, g.serverKey, signatureKey)
+}
+
+// MidtransSignature computes the signature_key Midtrans attaches to HTTP notifications:
+// SHA512(order_id + status_code + gross_amount + server_key).
+func MidtransSignature(orderID, statusCode, grossAmount, serverKey string) string {
+	hash := sha512.Sum512([]byte(orderID + statusCode + grossAmount + serverKey))
+	return hex.EncodeToString(hash[:])
+}
+
+func verifyMidtransSignature(orderID, statusCode, grossAmount, serverKey, signatureKey string) bool {
+	expected := MidtransSignature(orderID, statusCode, grossAmount, serverKey)
+	return subtle.ConstantTimeCompare([]byte(expected), []byte(signatureKey)) == 1
+}
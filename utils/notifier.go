@@ -0,0 +1,55 @@
+package utils
+
+import (
+	"context"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// Notification is a message for the store's staff, e.g. a stock alert. Event names what happened,
+// Data carries its details for notifiers that forward structured payloads.
+type Notification struct {
+	Event   string `json:"event"`
+	Subject string `json:"subject"`
+	Message string `json:"message"`
+	Data    any    `json:"data"`
+}
+
+// Notifier delivers notifications to the store's staff.
+type Notifier interface {
+	Notify(ctx context.Context, notification Notification) error
+}
+
+// NewNotifier picks the notifier from NOTIFIER_DRIVER, "log" (default), "webhook" or "email".
+func NewNotifier(logger *zap.Logger) Notifier {
+	switch GetEnv("NOTIFIER_DRIVER", "log") {
+	case "webhook":
+		return NewWebhookNotifier(MustGetEnv("NOTIFIER_WEBHOOK_URL"), GetEnv("NOTIFIER_WEBHOOK_SECRET", ""))
+	case "email":
+		return NewEmailNotifier(EmailConfig{
+			Host:     MustGetEnv("SMTP_HOST"),
+			Port:     GetEnv("SMTP_PORT", "587"),
+			Username: GetEnv("SMTP_USERNAME", ""),
+			Password: GetEnv("SMTP_PASSWORD", ""),
+			From:     MustGetEnv("NOTIFIER_EMAIL_FROM"),
+			To:       strings.FieldsFunc(MustGetEnv("NOTIFIER_EMAIL_TO"), func(r rune) bool { return r == ',' || r == ' ' }),
+		})
+	}
+
+	return NewLogNotifier(logger)
+}
+
+// LogNotifier writes notifications to the application log.
+type LogNotifier struct {
+	logger *zap.Logger
+}
+
+func NewLogNotifier(logger *zap.Logger) *LogNotifier {
+	return &LogNotifier{logger: logger}
+}
+
+func (n *LogNotifier) Notify(ctx context.Context, notification Notification) error {
+	n.logger.Warn(notification.Subject, zap.String("event", notification.Event), zap.String("message", notification.Message), zap.Any("data", notification.Data))
+	return nil
+}
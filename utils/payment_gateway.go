@@ -0,0 +1,56 @@
+package utils
+
+import "time"
+
+// PaymentGateway is the payment provider used to charge, inspect, cancel and refund transactions.
+// Order IDs are our transaction IDs formatted as strings.
+type PaymentGateway interface {
+	CreateCharge(payload PaymentPayload) (*PaymentCharge, error)
+	GetStatus(orderID string) (*PaymentStatus, error)
+	Cancel(orderID string) (*PaymentStatus, error)
+	Refund(orderID string, payload PaymentRefundPayload) (*PaymentRefund, error)
+	VerifySignature(orderID, statusCode, grossAmount, signatureKey string) bool
+}
+
+type PaymentPayload struct {
+	OrderId int
+	Amount  int
+	FName   string
+	Email   string
+	Expiry  time.Duration
+}
+
+type PaymentCharge struct {
+	OrderID     string
+	Token       string
+	RedirectURL string
+}
+
+type PaymentStatus struct {
+	OrderID           string
+	StatusCode        string
+	GrossAmount       string
+	TransactionStatus string
+	FraudStatus       string
+}
+
+type PaymentRefundPayload struct {
+	RefundKey string
+	Amount    int
+	Reason    string
+}
+
+type PaymentRefund struct {
+	RefundKey         string
+	Amount            int
+	TransactionStatus string
+}
+
+// NewPaymentGateway picks the gateway from the PAYMENT_GATEWAY env, "midtrans" (default) or "fake".
+func NewPaymentGateway() PaymentGateway {
+	if GetEnv("PAYMENT_GATEWAY", "midtrans") == "fake" {
+		return NewFakePaymentGateway(GetEnv("FAKE_PAYMENT_BASE_URL", "http://localhost:3000/fake-payments"))
+	}
+
+	return NewMidtransPaymentGateway(MustGetEnv("MIDTRANS_SERVER_KEY"), GetEnv("MIDTRANS_ENVIRONMENT", "sandbox"))
+}
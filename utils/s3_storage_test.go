@@ -0,0 +1,150 @@
+package utils
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// s3StandIn is an in-memory S3 bucket serving path-style object requests, it checks every request
+// carries the Signature V4 headers and the hash of its body.
+type s3StandIn struct {
+	t       *testing.T
+	bucket  string
+	mu      sync.Mutex
+	objects map[string]s3Object
+}
+
+type s3Object struct {
+	data        []byte
+	contentType string
+}
+
+func newS3StandIn(t *testing.T, bucket string) (*s3StandIn, *httptest.Server) {
+	standIn := &s3StandIn{t: t, bucket: bucket, objects: map[string]s3Object{}}
+	server := httptest.NewServer(standIn)
+	t.Cleanup(server.Close)
+	return standIn, server
+}
+
+func (s *s3StandIn) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	sum := sha256.Sum256(body)
+	if r.Header.Get("X-Amz-Content-Sha256") != hex.EncodeToString(sum[:]) {
+		http.Error(w, "XAmzContentSHA256Mismatch", http.StatusBadRequest)
+		return
+	}
+
+	date := r.Header.Get("X-Amz-Date")
+	authorization := r.Header.Get("Authorization")
+	if len(date) != len("20060102T150405Z") || !strings.HasPrefix(authorization, "AWS4-HMAC-SHA256 Credential=test-key/"+date[:8]+"/ap-southeast-1/s3/aws4_request, ") {
+		http.Error(w, "AccessDenied", http.StatusForbidden)
+		return
+	}
+
+	key, ok := strings.CutPrefix(r.URL.Path, "/"+s.bucket+"/")
+	if !ok {
+		http.Error(w, "NoSuchBucket", http.StatusNotFound)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch r.Method {
+	case http.MethodPut:
+		if !strings.Contains(authorization, "SignedHeaders=content-type;host;x-amz-content-sha256;x-amz-date,") {
+			http.Error(w, "AccessDenied", http.StatusForbidden)
+			return
+		}
+		s.objects[key] = s3Object{data: body, contentType: r.Header.Get("Content-Type")}
+	case http.MethodDelete:
+		if _, ok := s.objects[key]; !ok {
+			http.Error(w, "NoSuchKey", http.StatusNotFound)
+			return
+		}
+		delete(s.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "MethodNotAllowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *s3StandIn) object(key string) (s3Object, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	object, ok := s.objects[key]
+	return object, ok
+}
+
+func newTestS3Storage(t *testing.T, endpoint, bucket string) *S3Storage {
+	storage, err := NewS3Storage(S3Config{
+		Endpoint:        endpoint,
+		Region:          "ap-southeast-1",
+		Bucket:          bucket,
+		AccessKeyID:     "test-key",
+		SecretAccessKey: "test-secret",
+	})
+	if err != nil {
+		t.Fatalf("NewS3Storage() error = %v", err)
+	}
+	storage.now = func() time.Time { return time.Date(2024, 3, 14, 9, 30, 0, 0, time.UTC) }
+	return storage
+}
+
+func TestS3StoragePutAndDelete(t *testing.T) {
+	standIn, server := newS3StandIn(t, "bikes")
+	storage := newTestS3Storage(t, server.URL, "bikes")
+	ctx := context.Background()
+
+	key := "bikes/12/image.jpg"
+	if err := storage.Put(ctx, key, []byte("jpeg bytes"), "image/jpeg"); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+
+	object, ok := standIn.object(key)
+	if !ok {
+		t.Fatalf("Put() didn't store %s", key)
+	}
+	if string(object.data) != "jpeg bytes" || object.contentType != "image/jpeg" {
+		t.Errorf("stored %q as %q, want %q as %q", object.data, object.contentType, "jpeg bytes", "image/jpeg")
+	}
+
+	if got, want := storage.URL(key), server.URL+"/bikes/"+key; got != want {
+		t.Errorf("URL() = %q, want %q", got, want)
+	}
+
+	if err := storage.Delete(ctx, key); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, ok := standIn.object(key); ok {
+		t.Errorf("Delete() left %s in the bucket", key)
+	}
+
+	// deleting an object that is already gone succeeds
+	if err := storage.Delete(ctx, key); err != nil {
+		t.Errorf("Delete() of a missing object error = %v", err)
+	}
+}
+
+func TestS3StorageReportsErrors(t *testing.T) {
+	_, server := newS3StandIn(t, "bikes")
+	storage := newTestS3Storage(t, server.URL, "other-bucket")
+
+	err := storage.Put(context.Background(), "image.jpg", []byte("jpeg bytes"), "image/jpeg")
+	if err == nil || !strings.Contains(err.Error(), "404") || !strings.Contains(err.Error(), "NoSuchBucket") {
+		t.Errorf("Put() into a missing bucket error = %v, want the 404 and its body", err)
+	}
+}
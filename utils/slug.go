@@ -0,0 +1,46 @@
+package utils
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Slugify turns a name into a url friendly slug, e.g. "Full Suspension" becomes "full-suspension".
+// Anything that isn't an ascii letter or digit separates words.
+func Slugify(name string) string {
+	var b strings.Builder
+	pendingDash := false
+
+	for _, r := range strings.ToLower(name) {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			if pendingDash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			pendingDash = false
+			continue
+		}
+		pendingDash = true
+	}
+
+	return b.String()
+}
+
+// IsSpecKey reports whether s is a spec attribute key: lowercase ascii letters, digits and underscores,
+// starting with a letter and not ending in _min or _max, which search filters use for ranges.
+func IsSpecKey(s string) bool {
+	if s == "" || s[0] < 'a' || s[0] > 'z' || strings.HasSuffix(s, "_min") || strings.HasSuffix(s, "_max") {
+		return false
+	}
+	for _, r := range s {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '_' {
+			return false
+		}
+	}
+	return true
+}
+
+// IsSlug reports whether s is a slug as produced by Slugify.
+func IsSlug(s string) bool {
+	return s != "" && Slugify(s) == s
+}
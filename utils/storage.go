@@ -0,0 +1,29 @@
+package utils
+
+import "context"
+
+// Storage keeps uploaded files, e.g. bike images, and tells where they are served from.
+type Storage interface {
+	Put(ctx context.Context, key string, data []byte, contentType string) error
+	Delete(ctx context.Context, key string) error
+	URL(key string) string
+}
+
+// NewStorage picks the storage backend from STORAGE_DRIVER, local disk by default
+// or any S3 compatible object storage (AWS S3, MinIO, ...).
+func NewStorage() Storage {
+	if GetEnv("STORAGE_DRIVER", "local") == "s3" {
+		storage, err := NewS3Storage(S3Config{
+			Endpoint:        MustGetEnv("S3_ENDPOINT"),
+			Region:          GetEnv("S3_REGION", "us-east-1"),
+			Bucket:          MustGetEnv("S3_BUCKET"),
+			AccessKeyID:     MustGetEnv("S3_ACCESS_KEY_ID"),
+			SecretAccessKey: MustGetEnv("S3_SECRET_ACCESS_KEY"),
+			PublicURL:       GetEnv("S3_PUBLIC_URL", ""),
+		})
+		PanicIfError(err)
+		return storage
+	}
+
+	return NewLocalStorage(GetEnv("LOCAL_STORAGE_DIR", "uploads"), GetEnv("LOCAL_STORAGE_BASE_URL", "http://localhost:3000/uploads"))
+}
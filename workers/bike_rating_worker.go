@@ -0,0 +1,49 @@
+package workers
+
+import (
+	"context"
+	"time"
+
+	"github.com/gowesmart/api-gowesmart/services"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// BikeRatingWorker recomputes the review aggregates of all bikes on start and then every interval,
+// repairing drift and filling in aggregates added after reviews were written.
+type BikeRatingWorker struct {
+	db            *gorm.DB
+	logger        *zap.Logger
+	reviewService *services.ReviewService
+	interval      time.Duration
+}
+
+func NewBikeRatingWorker(db *gorm.DB, logger *zap.Logger, reviewService *services.ReviewService, interval time.Duration) *BikeRatingWorker {
+	return &BikeRatingWorker{
+		db:            db,
+		logger:        logger,
+		reviewService: reviewService,
+		interval:      interval,
+	}
+}
+
+// Start runs the worker right away and every interval until ctx is done.
+func (w *BikeRatingWorker) Start(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	w.logger.Info("bike rating worker started", zap.Duration("interval", w.interval))
+
+	for {
+		if _, err := w.reviewService.RecomputeBikeRatings(w.db, w.logger); err != nil {
+			w.logger.Error("bike rating worker run failed", zap.Error(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			w.logger.Info("bike rating worker stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
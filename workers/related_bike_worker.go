@@ -0,0 +1,48 @@
+package workers
+
+import (
+	"context"
+	"time"
+
+	"github.com/gowesmart/api-gowesmart/services"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// RelatedBikeWorker rebuilds the bikes bought together from the order history on start and then every interval.
+type RelatedBikeWorker struct {
+	db                 *gorm.DB
+	logger             *zap.Logger
+	relatedBikeService *services.RelatedBikeService
+	interval           time.Duration
+}
+
+func NewRelatedBikeWorker(db *gorm.DB, logger *zap.Logger, relatedBikeService *services.RelatedBikeService, interval time.Duration) *RelatedBikeWorker {
+	return &RelatedBikeWorker{
+		db:                 db,
+		logger:             logger,
+		relatedBikeService: relatedBikeService,
+		interval:           interval,
+	}
+}
+
+// Start runs the worker right away and every interval until ctx is done.
+func (w *RelatedBikeWorker) Start(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	w.logger.Info("related bike worker started", zap.Duration("interval", w.interval))
+
+	for {
+		if _, err := w.relatedBikeService.RecomputeRelatedBikes(w.db, w.logger); err != nil {
+			w.logger.Error("related bike worker run failed", zap.Error(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			w.logger.Info("related bike worker stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
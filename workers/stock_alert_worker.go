@@ -0,0 +1,67 @@
+package workers
+
+import (
+	"context"
+	"time"
+
+	"github.com/gowesmart/api-gowesmart/services"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const stockAlertBatchSize = 50
+
+// StockAlertWorker periodically delivers the stock alerts raised since its last run through the notifier.
+type StockAlertWorker struct {
+	db                *gorm.DB
+	logger            *zap.Logger
+	stockAlertService *services.StockAlertService
+	interval          time.Duration
+}
+
+func NewStockAlertWorker(db *gorm.DB, logger *zap.Logger, stockAlertService *services.StockAlertService, interval time.Duration) *StockAlertWorker {
+	return &StockAlertWorker{
+		db:                db,
+		logger:            logger,
+		stockAlertService: stockAlertService,
+		interval:          interval,
+	}
+}
+
+// Start runs the worker every interval until ctx is done.
+func (w *StockAlertWorker) Start(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	w.logger.Info("stock alert worker started", zap.Duration("interval", w.interval))
+
+	for {
+		select {
+		case <-ctx.Done():
+			w.logger.Info("stock alert worker stopped")
+			return
+		case <-ticker.C:
+			if _, err := w.RunOnce(ctx); err != nil {
+				w.logger.Error("stock alert worker run failed", zap.Error(err))
+			}
+		}
+	}
+}
+
+// RunOnce delivers pending alerts batch by batch until a batch comes back short, returning how many were delivered.
+// Deliveries still running when ctx is done are cancelled.
+func (w *StockAlertWorker) RunOnce(ctx context.Context) (int, error) {
+	total := 0
+
+	for {
+		notified, err := w.stockAlertService.NotifyPending(ctx, w.db, w.logger, stockAlertBatchSize)
+		if err != nil {
+			return total, err
+		}
+
+		total += notified
+		if notified < stockAlertBatchSize {
+			return total, nil
+		}
+	}
+}
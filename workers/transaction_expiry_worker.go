@@ -0,0 +1,75 @@
+package workers
+
+import (
+	"context"
+	"time"
+
+	"github.com/gowesmart/api-gowesmart/services"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const transactionExpiryBatchSize = 100
+
+// TransactionExpiryWorker periodically expires pending transactions whose payment window has elapsed.
+type TransactionExpiryWorker struct {
+	db                 *gorm.DB
+	logger             *zap.Logger
+	transactionService *services.TransactionService
+	interval           time.Duration
+	now                func() time.Time
+}
+
+func NewTransactionExpiryWorker(db *gorm.DB, logger *zap.Logger, transactionService *services.TransactionService, interval time.Duration) *TransactionExpiryWorker {
+	return &TransactionExpiryWorker{
+		db:                 db,
+		logger:             logger,
+		transactionService: transactionService,
+		interval:           interval,
+		now:                time.Now,
+	}
+}
+
+// WithClock replaces the clock used to decide which transactions are overdue.
+func (w *TransactionExpiryWorker) WithClock(now func() time.Time) *TransactionExpiryWorker {
+	w.now = now
+	return w
+}
+
+// Start runs the worker every interval until ctx is done.
+func (w *TransactionExpiryWorker) Start(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	w.logger.Info("transaction expiry worker started", zap.Duration("interval", w.interval))
+
+	for {
+		select {
+		case <-ctx.Done():
+			w.logger.Info("transaction expiry worker stopped")
+			return
+		case <-ticker.C:
+			if _, err := w.RunOnce(); err != nil {
+				w.logger.Error("transaction expiry worker run failed", zap.Error(err))
+			}
+		}
+	}
+}
+
+// RunOnce expires overdue transactions batch by batch until none are left, returning how many were expired.
+func (w *TransactionExpiryWorker) RunOnce() (int, error) {
+	total := 0
+	now := w.now()
+
+	for {
+		expired, err := w.transactionService.ExpireOverdue(w.db, w.logger, now, transactionExpiryBatchSize)
+		if err != nil {
+			return total, err
+		}
+
+		total += expired
+		if expired < transactionExpiryBatchSize {
+			return total, nil
+		}
+	}
+}
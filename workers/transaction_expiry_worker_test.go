@@ -0,0 +1,82 @@
+package workers_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gowesmart/api-gowesmart/app/apptest"
+	"github.com/gowesmart/api-gowesmart/model/entity"
+	"github.com/gowesmart/api-gowesmart/model/web/request"
+	"github.com/gowesmart/api-gowesmart/services"
+	"github.com/gowesmart/api-gowesmart/utils"
+	"github.com/gowesmart/api-gowesmart/workers"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+func TestTransactionExpiryWorkerExpiresOverdueTransactionsOnce(t *testing.T) {
+	db := apptest.NewDB(t)
+	user := apptest.CreateUser(t, db, entity.IDRoleUser)
+	variant := apptest.CreateVariant(t, db, 125000, 5)
+
+	service := services.NewTransactionService(utils.NewFakePaymentGateway("http://localhost/fake-payments"), time.Hour, false, utils.InvoiceIssuer{})
+
+	created, err := service.Create(apptest.NewContext(db), []request.TransactionCreate{{VariantID: variant.ID, Quantity: 2}}, int(user.ID))
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	now := time.Now()
+	worker := workers.NewTransactionExpiryWorker(db, zap.NewNop(), service, time.Minute).WithClock(func() time.Time { return now })
+
+	// still inside the payment window
+	if expired, err := worker.RunOnce(); err != nil || expired != 0 {
+		t.Fatalf("RunOnce() before expires_at = %d, %v, want 0, nil", expired, err)
+	}
+	assertVariantStock(t, db, variant.ID, 3)
+
+	now = created.ExpiresAt.Add(time.Second)
+	if expired, err := worker.RunOnce(); err != nil || expired != 1 {
+		t.Fatalf("RunOnce() after expires_at = %d, %v, want 1, nil", expired, err)
+	}
+
+	now = now.Add(time.Hour)
+	if expired, err := worker.RunOnce(); err != nil || expired != 0 {
+		t.Fatalf("RunOnce() on an expired transaction = %d, %v, want 0, nil", expired, err)
+	}
+
+	var transaction entity.Transaction
+	if err := db.Take(&transaction, created.TransactionID).Error; err != nil {
+		t.Fatalf("loading transaction: %v", err)
+	}
+	if transaction.Status != entity.TransactionStatusExpired {
+		t.Errorf("status = %q, want %q", transaction.Status, entity.TransactionStatusExpired)
+	}
+	if transaction.StockReservation != entity.StockReservationReleased {
+		t.Errorf("stock reservation = %q, want %q", transaction.StockReservation, entity.StockReservationReleased)
+	}
+
+	assertVariantStock(t, db, variant.ID, 5)
+
+	var releases int64
+	if err := db.Model(&entity.StockMovement{}).
+		Where("bike_variant_id = ? AND type = ? AND quantity > 0", variant.ID, entity.StockMovementReservation).
+		Count(&releases).Error; err != nil {
+		t.Fatalf("counting released reservations: %v", err)
+	}
+	if releases != 1 {
+		t.Errorf("released the reservation %d times, want once", releases)
+	}
+}
+
+func assertVariantStock(t *testing.T, db *gorm.DB, variantID uint, want int) {
+	t.Helper()
+
+	var variant entity.BikeVariant
+	if err := db.Select("id, stock").Take(&variant, variantID).Error; err != nil {
+		t.Fatalf("loading variant: %v", err)
+	}
+	if variant.Stock != want {
+		t.Errorf("variant stock = %d, want %d", variant.Stock, want)
+	}
+}